@@ -1,6 +1,7 @@
 package main
 
 import (
+	"errors"
 	"flag"
 	"fmt"
 	"os"
@@ -8,17 +9,20 @@ import (
 	"strings"
 	"time"
 
+	"github.com/bmf-san/gohan/internal/assets"
 	"github.com/bmf-san/gohan/internal/config"
 	"github.com/bmf-san/gohan/internal/diff"
 	"github.com/bmf-san/gohan/internal/generator"
+	"github.com/bmf-san/gohan/internal/imageproc"
 	"github.com/bmf-san/gohan/internal/model"
 	"github.com/bmf-san/gohan/internal/parser"
 	"github.com/bmf-san/gohan/internal/plugin"
+	"github.com/bmf-san/gohan/internal/precompress"
 	"github.com/bmf-san/gohan/internal/processor"
 	gohantemplate "github.com/bmf-san/gohan/internal/template"
 )
 
-func runBuild(args []string) error {
+func runBuild(args []string) (err error) {
 	fs := flag.NewFlagSet("build", flag.ContinueOnError)
 	full := fs.Bool("full", false, "force full build (bypass diff detection)")
 	configPath := fs.String("config", "config.yaml", "path to config file")
@@ -30,13 +34,20 @@ func runBuild(args []string) error {
 	future := fs.Bool("future", false, "include articles with a future date in the build")
 	stats := fs.Bool("stats", false, "print per-phase timing and file counts after the build")
 	explain := fs.Bool("explain", false, "print which files triggered a rebuild and why")
+	prune := fs.Bool("prune", true, "delete output files left behind by removed or renamed content")
+	noPrune := fs.Bool("no-prune", false, "keep stale output files (overrides --prune)")
 	if err := fs.Parse(args); err != nil {
 		return err
 	}
-	_ = logFmt // reserved for structured logging
+	log, err := newCLILog(*logFmt, os.Stdout, os.Stderr)
+	if err != nil {
+		return err
+	}
+	defer func() { log.Fail("build failed", err) }()
 
 	start := time.Now()
 	phases := newPhaseTimer()
+	phases.log = log
 
 	// Determine project root from config file location.
 	cfgAbs, err := filepath.Abs(*configPath)
@@ -53,13 +64,14 @@ func runBuild(args []string) error {
 	gohanDir := filepath.Join(rootDir, ".gohan")
 	// Print .gitignore hint on first run (before creating the directory).
 	if _, statErr := os.Stat(gohanDir); os.IsNotExist(statErr) {
-		fmt.Println("hint: add '.gohan/' to your .gitignore to exclude build cache")
+		const hint = "hint: add '.gohan/' to your .gitignore to exclude build cache"
+		log.Info(hint, hint)
 	}
 	_ = os.MkdirAll(gohanDir, 0o755)
 	lockPath := filepath.Join(gohanDir, "build.lock")
 	unlock, acquired := tryLockBuildFile(lockPath)
 	if !acquired {
-		fmt.Println("build: another build is already running — skipping")
+		log.Info("build: another build is already running — skipping", "build skipped", "reason", "another build is already running")
 		return nil
 	}
 	defer unlock()
@@ -88,11 +100,28 @@ func runBuild(args []string) error {
 		return fmt.Errorf("read manifest: %w", err)
 	}
 
-	// Full build when: --full flag, config hashing failed, config changed, or no manifest yet.
-	// If we cannot hash the config, we must assume it has changed to avoid stale output.
-	forceFullBuild := *full || configHashErr != nil || diff.CheckConfigChange(manifest, configHash)
+	// Hash the theme templates: template edits do not touch content files, so
+	// they are invisible to content diff detection and must force a full build.
+	templateDir := filepath.Join(rootDir, cfg.Theme.Dir, "templates")
+	themeHash, themeHashErr := diff.HashDir(templateDir)
+
+	// Full build when: --full flag, config hashing failed, config changed, theme
+	// changed, or no manifest yet. If we cannot hash the config or theme, we
+	// must assume it has changed to avoid stale output.
+	configChanged := diff.CheckConfigChange(manifest, configHash)
+	themeChanged := !configChanged && (themeHashErr != nil || diff.CheckThemeChange(manifest, themeHash))
+	forceFullBuild := *full || configHashErr != nil || configChanged || themeChanged
+	// Remember what the previous build wrote before a full build discards the
+	// manifest, so that outputs it no longer produces can still be pruned.
+	var prevOutputs []model.OutputFile
+	if manifest != nil {
+		prevOutputs = manifest.OutputFiles
+	}
+	pruneStale := *prune && !*noPrune
 	if forceFullBuild && manifest != nil {
-		if clearErr := diff.ClearCache(cacheDir); clearErr != nil {
+		// The render and image caches are content-addressed and stay valid
+		// across config and theme changes, so they survive a full rebuild.
+		if clearErr := diff.ClearCache(cacheDir, processor.RenderCacheDir, imageproc.CacheDir); clearErr != nil {
 			return fmt.Errorf("clear cache: %w", clearErr)
 		}
 		manifest = nil
@@ -100,16 +129,9 @@ func runBuild(args []string) error {
 
 	// Parse content.
 	p := parser.NewFileParser(cfg.Build.ExcludeFiles...)
-	contentDir := filepath.Join(rootDir, cfg.Build.ContentDir)
-	// Resolve path fields to absolute so that processor functions that call
-	// filepath.Rel(cfg.Build.ContentDir, a.FilePath) work correctly when
-	// article FilePaths are absolute (as set by the file parser).
-	cfg.Build.ContentDir = contentDir
-	cfg.Build.OutputDir = filepath.Join(rootDir, cfg.Build.OutputDir)
-	cfg.Build.AssetsDir = filepath.Join(rootDir, cfg.Build.AssetsDir)
-	if cfg.Build.StaticDir != "" {
-		cfg.Build.StaticDir = filepath.Join(rootDir, cfg.Build.StaticDir)
-	}
+	p.SetParallelism(cfg.Build.Parallelism)
+	resolveBuildDirs(cfg, rootDir)
+	contentDir := cfg.Build.ContentDir
 	var articles []*model.Article
 	if err := phases.Phase("parse", func() error {
 		var perr error
@@ -142,10 +164,21 @@ func runBuild(args []string) error {
 		articles = filtered
 	}
 
-	// Detect diff.
+	if cfg.Build.EnforceSchema {
+		if errs := processor.ValidateContentSchema(articles, *cfg); len(errs) > 0 {
+			return fmt.Errorf("content schema: %d violation(s):\n%w", len(errs), errors.Join(errs...))
+		}
+	}
+
+	// Detect diff. Inside a git work tree only the files git reports as changed
+	// since the last built commit are hashed; otherwise every file is hashed.
+	repoEngine, repoErr := diff.NewRepoDiffEngine(contentDir)
+	var engine diff.DiffEngine = diff.NewGitDiffEngine(contentDir)
+	if repoErr == nil {
+		engine = repoEngine
+	}
 	var changeSet *model.ChangeSet
 	if !forceFullBuild {
-		engine := diff.NewGitDiffEngine(contentDir)
 		if err := phases.Phase("diff", func() error {
 			var derr error
 			changeSet, derr = engine.Detect(manifest)
@@ -156,7 +189,14 @@ func runBuild(args []string) error {
 	}
 
 	// Process articles.
+	// Unchanged articles reuse their rendered HTML from the render cache;
+	// --dry-run leaves the cache untouched.
 	proc := processor.NewSiteProcessor()
+	var renderCache *processor.RenderCache
+	if !*dryRun {
+		renderCache = processor.NewRenderCache(filepath.Join(cacheDir, processor.RenderCacheDir))
+		proc.SetRenderCache(renderCache)
+	}
 	var processed []*model.ProcessedArticle
 	if err := phases.Phase("process", func() error {
 		var perr error
@@ -165,10 +205,50 @@ func runBuild(args []string) error {
 	}); err != nil {
 		return fmt.Errorf("process articles: %w", err)
 	}
+	if renderCache != nil {
+		if pruneErr := renderCache.Prune(); pruneErr != nil {
+			log.Warn("render cache", pruneErr)
+		}
+	}
+
+	// Attach commit dates, authors, and hashes from git history. Articles
+	// whose history changed since the last build are rendered again even if
+	// their contents did not.
+	var gitDigests map[string]string
+	if cfg.Build.GitInfo {
+		_ = phases.Phase("git", func() error {
+			infos, gerr := diff.LoadGitInfo(contentDir)
+			if gerr != nil {
+				log.Warn("git_info", gerr)
+				return nil
+			}
+			processor.ApplyGitInfo(processed, infos)
+			gitDigests = diff.GitInfoDigests(infos)
+			if changeSet != nil {
+				paths := make([]string, len(processed))
+				for i, a := range processed {
+					paths[i] = a.ContentPath
+				}
+				diff.MarkGitInfoChanges(changeSet, manifest, gitDigests, paths)
+			}
+			return nil
+		})
+	}
 
 	// Link translations across locales (no-op when i18n is not configured).
 	proc.BuildTranslationMap(processed)
 
+	// Build the article → tag/category/archive dependency graph used to limit
+	// rendering to the pages impacted by the change set.
+	var graph *model.DependencyGraph
+	if err := phases.Phase("graph", func() error {
+		var gerr error
+		graph, gerr = proc.BuildDependencyGraph(processed)
+		return gerr
+	}); err != nil {
+		return fmt.Errorf("build dependency graph: %w", err)
+	}
+
 	// Validate that no two articles resolve to the same output path.
 	// Duplicate output paths would cause non-deterministic page overwrites.
 	if errs := processor.ValidateOutputPaths(processed); len(errs) > 0 {
@@ -183,29 +263,25 @@ func runBuild(args []string) error {
 	// When i18n is active, locale-specific files are preferred:
 	//   {contentDir}/{locale}/tags.yaml        (falls back to {contentDir}/tags.yaml)
 	//   {contentDir}/{locale}/categories.yaml  (falls back to {contentDir}/categories.yaml)
+	//   {contentDir}/{locale}/{name}.yaml      (each user-defined taxonomy)
 	// When no registry files exist, the registry is derived from article frontmatter
 	// (no validation is performed).
 	var taxo *model.TaxonomyRegistry
 	{
-		regs, loadErr := processor.LoadLocaleAwareTaxonomyRegistries(contentDir, cfg.I18n.Locales)
+		regs, loadErr := processor.LoadLocaleAwareTaxonomyRegistries(contentDir, cfg.I18n.Locales, cfg.Taxonomies...)
 		if loadErr != nil {
 			return fmt.Errorf("load taxonomy registries: %w", loadErr)
 		}
-		merged := processor.MergeTaxonomyRegistries(regs)
-		if len(merged.Tags) > 0 || len(merged.Categories) > 0 {
-			taxo = merged
-			if errs := processor.ValidateArticleTaxonomiesLocale(processed, regs); len(errs) > 0 {
-				for _, e := range errs {
-					fmt.Fprintf(os.Stderr, "warn: taxonomy: %v\n", e)
-				}
-			}
-		} else {
-			computed, err := proc.BuildTaxonomyRegistry(processed, *cfg)
-			if err != nil {
-				return fmt.Errorf("build taxonomy: %w", err)
+		if errs := processor.ValidateArticleTaxonomiesLocale(processed, regs); len(errs) > 0 {
+			for _, e := range errs {
+				log.Warn("taxonomy", e)
 			}
-			taxo = computed
 		}
+		computed, err := proc.BuildTaxonomyRegistry(processed, *cfg)
+		if err != nil {
+			return fmt.Errorf("build taxonomy: %w", err)
+		}
+		taxo = processor.ResolveTaxonomyRegistry(computed, processor.MergeTaxonomyRegistries(regs))
 	}
 
 	site := &model.Site{
@@ -213,6 +289,8 @@ func runBuild(args []string) error {
 		Articles:   processed,
 		Tags:       taxo.Tags,
 		Categories: taxo.Categories,
+		Taxonomies: taxo.Taxonomies,
+		Series:     proc.BuildSeries(processed),
 	}
 
 	// Run plugins (article enrichment + virtual page generation).
@@ -228,11 +306,52 @@ func runBuild(args []string) error {
 		return err
 	}
 
+	// jsonSummary emits the final record of a build in JSON mode. It carries
+	// everything text mode prints after the summary line.
+	jsonSummary := func(msg string, elapsed time.Duration, cache *processor.RenderCache, args ...any) {
+		args = append([]any{
+			"articles", len(processed),
+			"warnings", log.warns,
+			"duration_ms", durationMS(elapsed),
+			"full", forceFullBuild,
+		}, args...)
+		if *explain {
+			args = append(args, explainAttr(forceFullBuild, explainFullReason(*full, configHashErr, themeChanged, manifest == nil), changeSet))
+		}
+		if *stats {
+			args = append(args, phases.statsAttr(elapsed, cache))
+		}
+		log.json.Info(msg, args...)
+	}
+
+	// Minify, bundle, and fingerprint CSS and JS before rendering, so that
+	// templates can resolve the published names with the asset function.
+	assetPipeline, err := assets.New(cfg.Build.AssetsDir, cfg.Assets)
+	if err != nil {
+		return fmt.Errorf("asset pipeline: %w", err)
+	}
+
+	outDir := cfg.Build.OutputDir
 	if *dryRun {
 		elapsed := time.Since(start)
+		var wouldPrune []string
+		if pruneStale {
+			dryGen := generator.NewHTMLGenerator(outDir, nil, *cfg)
+			dryGen.SetAssetPipeline(assetPipeline)
+			dryGen.SetWarnFunc(log.Warn)
+			planned := append(dryGen.PlannedOutputs(site), generator.FeedOutputs(outDir, *cfg)...)
+			planned = append(planned, generator.TaxonomyFeedOutputs(outDir, site, *cfg)...)
+			planned = append(planned, precompress.Variants(planned, cfg.Build.Precompress)...)
+			wouldPrune = diff.StaleOutputs(outDir, prevOutputs, planned)
+		}
+		if log.isJSON() {
+			jsonSummary("dry-run complete", elapsed, nil, "would_prune", relOutputs(outDir, wouldPrune))
+			return nil
+		}
 		fmt.Printf("dry-run: %d articles, %s\n", len(processed), elapsed.Round(time.Millisecond))
+		writePruneList(os.Stdout, "would prune", outDir, wouldPrune)
 		if *explain {
-			writeExplain(os.Stdout, forceFullBuild, explainFullReason(*full, configHashErr, manifest == nil), changeSet)
+			writeExplain(os.Stdout, forceFullBuild, explainFullReason(*full, configHashErr, themeChanged, manifest == nil), changeSet)
 		}
 		if *stats {
 			phases.writeStats(os.Stdout, elapsed)
@@ -241,71 +360,176 @@ func runBuild(args []string) error {
 	}
 
 	// Render HTML.
-	outDir := cfg.Build.OutputDir
-	templateDir := filepath.Join(rootDir, cfg.Theme.Dir, "templates")
 	tmpl := gohantemplate.NewEngine()
-	if loadErr := tmpl.Load(templateDir, nil, cfg.I18n.DefaultLocale); loadErr != nil {
+	if loadErr := tmpl.Load(templateDir, assetPipeline.FuncMap(), cfg.I18n.DefaultLocale); loadErr != nil {
 		return fmt.Errorf("load templates: %w", loadErr)
 	}
 	gen := generator.NewHTMLGenerator(outDir, tmpl, *cfg)
+	gen.SetDependencyGraph(graph)
+	gen.SetAssetPipeline(assetPipeline)
+	gen.SetWarnFunc(log.Warn)
+	imageCacheDir := filepath.Join(cacheDir, imageproc.CacheDir)
+	gen.SetImageCacheDir(imageCacheDir)
+	// The previous build's graph lets deletions and taxonomy moves be
+	// rendered incrementally. It is only meaningful against the same config
+	// and theme, which forceFullBuild already guarantees (manifest is nil
+	// otherwise).
+	if changeSet != nil && manifest != nil && manifest.Dependencies != nil {
+		gen.SetPreviousDependencyGraph(processor.GraphFromDependencyMap(manifest.Dependencies, contentDir))
+	}
 	if err := phases.Phase("render", func() error {
 		return gen.Generate(site, changeSet)
 	}); err != nil {
 		return fmt.Errorf("generate HTML: %w", err)
 	}
+	if cfg.Images.Enabled {
+		if pruneErr := imageproc.Prune(imageCacheDir, generator.ImageVariants(site), cfg.Images.Quality); pruneErr != nil {
+			log.Warn("image cache", pruneErr)
+		}
+	}
 
 	// Sitemap + feeds.
 	_ = phases.Phase("feeds", func() error {
-		if err := generator.GenerateSitemap(outDir, cfg.Site.BaseURL, processed, site.VirtualPages, generator.TaxonomyURLs(site, *cfg), *cfg); err != nil {
-			fmt.Fprintf(os.Stderr, "warn: sitemap: %v\n", err)
+		listed := site
+		if !tmpl.Has("series.html") {
+			// Series listing pages were skipped; keep them out of the sitemap.
+			withoutSeries := *site
+			withoutSeries.Series = nil
+			listed = &withoutSeries
+		}
+		if err := generator.GenerateSitemap(outDir, cfg.Site.BaseURL, processed, site.VirtualPages, generator.TaxonomyURLs(listed, *cfg), *cfg); err != nil {
+			log.Warn("sitemap", err, "file", "sitemap.xml")
 		}
 		if err := generator.GenerateFeeds(outDir, cfg.Site.BaseURL, cfg.Site.Title, processed, *cfg); err != nil {
-			fmt.Fprintf(os.Stderr, "warn: feeds: %v\n", err)
+			log.Warn("feeds", err, "files", relOutputs(outDir, generator.FeedOutputs(outDir, *cfg)))
 		}
-		if err := generator.GenerateSearchIndex(outDir, cfg.Site.BaseURL, processed, *cfg); err != nil {
-			fmt.Fprintf(os.Stderr, "warn: search index: %v\n", err)
+		if err := generator.GenerateTaxonomyFeeds(outDir, cfg.Site.BaseURL, cfg.Site.Title, site, *cfg); err != nil {
+			log.Warn("feeds", err, "files", relOutputs(outDir, generator.TaxonomyFeedOutputs(outDir, site, *cfg)))
+		}
+		if err := generator.GenerateSearchIndex(outDir, cfg.Site.BaseURL, processed, site.SearchDocuments, *cfg); err != nil {
+			log.Warn("search index", err)
 		}
 		return nil
 	})
 
+	outputs := append(gen.Outputs(), generator.FeedOutputs(outDir, *cfg)...)
+	outputs = append(outputs, generator.TaxonomyFeedOutputs(outDir, site, *cfg)...)
+
+	// Write gzip and brotli variants of text outputs. A variant recorded in
+	// the previous manifest is kept when neither it nor its source changed.
+	if pc := cfg.Build.Precompress; pc.Gzip || pc.Brotli {
+		_ = phases.Phase("precompress", func() error {
+			variants, cerr := precompress.Write(outputs, pc, cfg.Build.Parallelism, diff.OutputUnchanged(outDir, prevOutputs))
+			if cerr != nil {
+				log.Warn("precompress", cerr)
+			}
+			outputs = append(outputs, variants...)
+			return nil
+		})
+	}
+
+	// Delete outputs of the previous build that this build no longer
+	// produces: pages of deleted or renamed articles, their OGP images, and
+	// listings of tags or categories that are no longer used. With --no-prune
+	// they stay recorded in the manifest so a later build can still remove them.
+	var pruned []string
+	stale := diff.StaleOutputs(outDir, prevOutputs, outputs)
+	if pruneStale {
+		_ = phases.Phase("prune", func() error {
+			var perr error
+			pruned, perr = diff.PruneOutputs(outDir, stale)
+			if perr != nil {
+				log.Warn("prune", perr)
+			}
+			return nil
+		})
+	} else {
+		outputs = append(outputs, stale...)
+	}
+
 	// Update manifest.
 	_ = phases.Phase("manifest", func() error {
+		manifestPath := diff.ManifestPath(cacheDir)
 		newManifest := diff.NewManifest(configHash)
-		hashEngine := diff.NewGitDiffEngine(contentDir)
-		for _, a := range articles {
-			rel, relErr := filepath.Rel(contentDir, a.FilePath)
-			if relErr != nil {
-				continue
-			}
-			if h, herr := hashEngine.Hash(a.FilePath); herr == nil {
+		if themeHashErr == nil {
+			diff.SetThemeHash(newManifest, themeHash)
+		}
+		// Record every content file, not just built articles, so drafts and
+		// taxonomy YAML files are only reported by the next diff when they
+		// actually change. An incremental build only re-hashes the change set.
+		var hashes map[string]string
+		var herr error
+		if changeSet != nil && manifest != nil {
+			hashes, herr = diff.UpdateHashes(contentDir, manifest.FileHashes, changeSet)
+		} else {
+			hashes, herr = diff.NewGitDiffEngine(contentDir).Snapshot()
+		}
+		if herr == nil {
+			for rel, h := range hashes {
 				newManifest.FileHashes[rel] = h
 			}
+		} else {
+			log.Warn("manifest hashes", herr, "file", manifestPath)
+		}
+		if repoErr == nil {
+			if commit, cerr := repoEngine.CleanCommit(); cerr == nil {
+				newManifest.LastCommit = commit
+			}
+		}
+		newManifest.Dependencies = processor.DependencyMap(graph, contentDir)
+		newManifest.GitInfo = gitDigests
+		if files, ofErr := diff.CollectOutputFiles(outDir, outputs); ofErr != nil {
+			log.Warn("manifest outputs", ofErr, "file", manifestPath)
+		} else {
+			newManifest.OutputFiles = files
 		}
 		if err := diff.WriteManifest(cacheDir, newManifest); err != nil {
-			fmt.Fprintf(os.Stderr, "warn: write manifest: %v\n", err)
+			log.Warn("write manifest", err, "file", manifestPath)
 		}
 		return nil
 	})
 
 	elapsed := time.Since(start)
+	if log.isJSON() {
+		jsonSummary("build complete", elapsed, renderCache, "pruned", relOutputs(outDir, pruned))
+		return nil
+	}
 	fmt.Printf("build: %d articles, 0 errors, %s\n", len(processed), elapsed.Round(time.Millisecond))
+	writePruneList(os.Stdout, "pruned", outDir, pruned)
 	if *explain {
-		writeExplain(os.Stdout, forceFullBuild, explainFullReason(*full, configHashErr, manifest == nil), changeSet)
+		writeExplain(os.Stdout, forceFullBuild, explainFullReason(*full, configHashErr, themeChanged, manifest == nil), changeSet)
 	}
 	if *stats {
 		phases.writeStats(os.Stdout, elapsed)
+		hits, misses := renderCache.Stats()
+		fmt.Printf("  %-12s %d hits, %d misses\n", "cache:", hits, misses)
 	}
 	return nil
 }
 
+// resolveBuildDirs makes the directory fields of cfg.Build absolute, relative
+// to rootDir, so that processor functions that call
+// filepath.Rel(cfg.Build.ContentDir, a.FilePath) work correctly when article
+// FilePaths are absolute (as set by the file parser).
+func resolveBuildDirs(cfg *model.Config, rootDir string) {
+	cfg.Build.ContentDir = filepath.Join(rootDir, cfg.Build.ContentDir)
+	cfg.Build.OutputDir = filepath.Join(rootDir, cfg.Build.OutputDir)
+	cfg.Build.AssetsDir = filepath.Join(rootDir, cfg.Build.AssetsDir)
+	if cfg.Build.StaticDir != "" {
+		cfg.Build.StaticDir = filepath.Join(rootDir, cfg.Build.StaticDir)
+	}
+}
+
 // explainFullReason returns a human-readable reason why a full build was
 // performed. It is used by writeExplain when forceFullBuild is true.
-func explainFullReason(fullFlag bool, configHashErr error, manifestMissing bool) string {
+func explainFullReason(fullFlag bool, configHashErr error, themeChanged, manifestMissing bool) string {
 	switch {
 	case fullFlag:
 		return "--full flag"
 	case configHashErr != nil:
 		return fmt.Sprintf("config hash unavailable: %v", configHashErr)
+	case themeChanged:
+		return "theme templates changed"
 	case manifestMissing:
 		return "no build manifest (first build or config changed)"
 	}
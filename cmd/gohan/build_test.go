@@ -3,6 +3,7 @@ package main
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -218,3 +219,25 @@ if err != nil {
 t.Fatalf("--explain --dry-run: %v", err)
 }
 }
+
+// TestRunBuild_EnforceSchema verifies that build.enforce_schema fails the
+// build on content_schema violations.
+func TestRunBuild_EnforceSchema(t *testing.T) {
+	dir := t.TempDir()
+	cfg := []byte("site:\n  title: Test\n  base_url: http://localhost\nbuild:\n  enforce_schema: true\n" +
+		"content_schema:\n  posts:\n    fields:\n      description:\n        required: true\n")
+	if err := os.WriteFile(filepath.Join(dir, "config.yaml"), cfg, 0644); err != nil {
+		t.Fatal(err)
+	}
+	postsDir := filepath.Join(dir, "content", "posts")
+	if err := os.MkdirAll(postsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(postsDir, "a.md"), []byte("---\ntitle: A\n---\nbody\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	err := runBuild([]string{"--config=" + filepath.Join(dir, "config.yaml"), "--dry-run"})
+	if err == nil || !strings.Contains(err.Error(), "description: required field is missing") {
+		t.Fatalf("expected schema violation, got %v", err)
+	}
+}
@@ -1,16 +1,22 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"io"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"slices"
 	"sort"
+	"strings"
 
 	"github.com/bmf-san/gohan/internal/config"
 	"github.com/bmf-san/gohan/internal/model"
 	"github.com/bmf-san/gohan/internal/parser"
+	"github.com/bmf-san/gohan/internal/processor"
 )
 
 // runCheck implements `gohan check`, a lightweight linter that validates
@@ -19,15 +25,46 @@ import (
 //   - Articles missing required front matter (currently: title and date).
 //   - translation_key values that only have a single article (no actual
 //     translation pair).
+//   - Markdown links to pages the site does not generate, and images or
+//     files that do not exist.
+//   - Templates the build would render that the theme does not define, and
+//     templates that fail to parse or to render a page (see lintTemplates).
 //
-// Exit code is 0 when no problems are found, 1 when warnings are reported.
+// Each rule has a severity (error, warning, or info) that can be changed in
+// the check section of config.yaml, where rules can also be enabled or
+// disabled. Exit code is 0 unless an issue with severity error is reported.
+// The report is plain text by default; --format selects json, sarif, or
+// github (workflow command annotations) instead; log records then go to
+// stderr so that stdout holds only the report.
+// With --output, the content rules are skipped and the links of the site
+// already built into the output directory are checked instead (see
+// lintOutput); --anchors additionally checks #fragment targets.
 // Pure warnings policy: this command never modifies the filesystem.
-func runCheck(args []string) error {
+func runCheck(args []string) (err error) {
 	fs := flag.NewFlagSet("check", flag.ContinueOnError)
 	configPath := fs.String("config", "config.yaml", "Path to config file")
+	logFmt := fs.String("log-format", "text", "log format: text or json")
+	format := fs.String("format", "text", "report format: text, json, sarif, or github")
+	output := fs.Bool("output", false, "check the links of the built site in the output directory instead of the content")
+	anchors := fs.Bool("anchors", false, "with --output, also check that #fragment links name an element id")
 	if err := fs.Parse(args); err != nil {
 		return err
 	}
+	writeReport, ok := checkReportWriters[*format]
+	if !ok {
+		return fmt.Errorf("unknown report format %q (want text, json, sarif, or github)", *format)
+	}
+	// A machine-readable report owns stdout; log records go to stderr so
+	// that they cannot corrupt it.
+	logOut := io.Writer(os.Stdout)
+	if *format != "text" {
+		logOut = os.Stderr
+	}
+	log, err := newCLILog(*logFmt, logOut, os.Stderr)
+	if err != nil {
+		return err
+	}
+	defer func() { log.Fail("check failed", err) }()
 
 	rootDir, err := os.Getwd()
 	if err != nil {
@@ -47,30 +84,233 @@ func runCheck(args []string) error {
 		return fmt.Errorf("load config: %w", err)
 	}
 
-	contentDir := filepath.Join(rootDir, cfg.Build.ContentDir)
+	checkCfg := cfg.Check
+	if *anchors {
+		checkCfg.Enable = append(slices.Clone(checkCfg.Enable), "output-missing-anchor")
+	}
+	rules, err := newCheckRuleSet(checkCfg)
+	if err != nil {
+		return err
+	}
+	var issues []checkIssue
+	if *output {
+		issues, err = lintOutput(rootDir, *cfg, rules)
+	} else {
+		// Annotations and SARIF locations are resolved against the
+		// repository root, so report paths relative to the project root.
+		issues, err = lintSource(rootDir, *cfg, rules, *format != "text")
+	}
+	if err != nil {
+		return err
+	}
+	issues = rules.apply(issues)
+	switch {
+	case *format != "text":
+		if err := writeReport(os.Stdout, issues); err != nil {
+			return fmt.Errorf("write report: %w", err)
+		}
+	case log.isJSON():
+		logCheckReport(log, issues)
+	default:
+		writeCheckReport(os.Stdout, issues)
+	}
+	if errs, _, _ := countSeverities(issues); errs > 0 {
+		return fmt.Errorf("%d issue(s) with severity error found", errs)
+	}
+	return nil
+}
+
+// lintSource runs the enabled content and template rules over the project at
+// rootDir. Issue paths are relative to the content directory, or to rootDir
+// for files outside it such as templates; with projectPaths, every path is
+// relative to rootDir.
+func lintSource(rootDir string, cfg model.Config, rules *checkRuleSet, projectPaths bool) ([]checkIssue, error) {
+	contentRel := cfg.Build.ContentDir
+	contentDir := filepath.Join(rootDir, contentRel)
 	p := parser.NewFileParser(cfg.Build.ExcludeFiles...)
+	p.SetParallelism(cfg.Build.Parallelism)
 	articles, err := p.ParseAll(contentDir)
 	if err != nil {
-		return fmt.Errorf("parse content: %w", err)
+		return nil, fmt.Errorf("parse content: %w", err)
+	}
+
+	resolved := cfg
+	resolveBuildDirs(&resolved, rootDir)
+	issues := lintArticles(articles, resolved)
+	if rules.enabled("schema-violation") {
+		for _, err := range processor.ValidateContentSchema(articles, resolved) {
+			var se *processor.SchemaError
+			if !errors.As(err, &se) {
+				continue
+			}
+			rel, relErr := filepath.Rel(contentDir, se.FilePath)
+			if relErr != nil {
+				rel = se.FilePath
+			}
+			issues = append(issues, checkIssue{File: rel, Kind: "schema-violation", Message: se.Field + ": " + se.Message})
+		}
+	}
+	var siteIssues []checkIssue // with absolute paths
+	if rules.enabled("broken-link") || rules.enabled("missing-asset") || rules.enabled("missing-template") || rules.enabled("template-error") {
+		resolveBuildDirs(&cfg, rootDir)
+		site, err := checkSite(articles, cfg)
+		if err != nil {
+			return nil, err
+		}
+		if rules.enabled("broken-link") || rules.enabled("missing-asset") {
+			issues = append(issues, lintLinks(site)...)
+		}
+		siteIssues = lintTemplates(site, filepath.Join(rootDir, cfg.Theme.Dir, "templates"), rules)
 	}
 
-	issues := lintArticles(articles, contentDir)
-	writeCheckReport(os.Stdout, issues)
-	if len(issues) > 0 {
-		return fmt.Errorf("%d issue(s) found", len(issues))
+	if projectPaths {
+		for i := range issues {
+			issues[i].File = filepath.ToSlash(filepath.Join(contentRel, issues[i].File))
+		}
 	}
-	return nil
+	for _, it := range siteIssues {
+		base := rootDir
+		if rel, err := filepath.Rel(contentDir, it.File); err == nil && !projectPaths && !strings.HasPrefix(rel, "..") {
+			base = contentDir
+		}
+		if rel, err := filepath.Rel(base, it.File); err == nil {
+			it.File = filepath.ToSlash(rel)
+		}
+		issues = append(issues, it)
+	}
+	return issues, nil
 }
 
 // checkIssue is a single linter finding.
 type checkIssue struct {
-	File    string // relative path under contentDir
-	Kind    string // "missing-title" | "missing-date" | "duplicate-slug" | "orphan-translation-key"
-	Message string
+	File     string // relative path under contentDir (project-relative for output rules)
+	Line     int    // 1-based line number; 0 when the issue concerns the whole file
+	Kind     string // rule ID, e.g. "missing-title" (see checkRules)
+	Severity string // "error" | "warning" | "info"; set by checkRuleSet.apply
+	Message  string
+}
+
+// Check severities. Only errors make `gohan check` fail.
+const (
+	severityError   = "error"
+	severityWarning = "warning"
+	severityInfo    = "info"
+)
+
+// checkRule describes a rule reported by `gohan check`.
+type checkRule struct {
+	ID          string
+	Description string
+	Severity    string // default severity
+	Enabled     bool   // whether the rule runs unless listed in check.disable
+}
+
+// checkRules lists every rule in report order. New rules that run by default
+// should start out with severityWarning so they can be rolled out without
+// failing CI.
+var checkRules = []checkRule{
+	{ID: "missing-title", Description: "Front matter is missing the required title field.", Severity: severityError, Enabled: true},
+	{ID: "missing-date", Description: "Front matter is missing the required date field.", Severity: severityError, Enabled: true},
+	{ID: "duplicate-slug", Description: "Two articles in the same directory resolve to the same slug.", Severity: severityError, Enabled: true},
+	{ID: "orphan-translation-key", Description: "A translation_key is used by only one article.", Severity: severityError, Enabled: true},
+	// schema-violation only reports when config.yaml declares a content_schema,
+	// so it can fail the check from the start.
+	{ID: "schema-violation", Description: "Front matter violates the content_schema of its section.", Severity: severityError, Enabled: true},
+	{ID: "broken-link", Description: "A Markdown link points to a page the site does not generate.", Severity: severityWarning, Enabled: true},
+	{ID: "missing-asset", Description: "An image or file reference points to a file the build does not produce.", Severity: severityWarning, Enabled: true},
+	{ID: "missing-template", Description: "A template named in front matter, by a plugin virtual page, or a standard page template is not defined by the theme.", Severity: severityWarning, Enabled: true},
+	{ID: "template-error", Description: "The theme templates fail to parse, or a page type fails to render.", Severity: severityWarning, Enabled: true},
+	// Output rules run only with --output, against an existing build.
+	{ID: "output-broken-link", Description: "A generated page references a file that is not in the output directory.", Severity: severityError, Enabled: true},
+	{ID: "output-missing-anchor", Description: "A generated page links to a #fragment that names no element on the target page.", Severity: severityError, Enabled: false},
+}
+
+// checkRuleSet is the set of rules enabled by the check section of
+// config.yaml, with their effective severities.
+type checkRuleSet struct {
+	severity map[string]string // enabled rule ID -> severity
+}
+
+// newCheckRuleSet resolves cfg against checkRules. It returns an error when
+// cfg names a rule that does not exist.
+func newCheckRuleSet(cfg model.CheckConfig) (*checkRuleSet, error) {
+	defaults := make(map[string]string, len(checkRules)) // rule ID -> default severity
+	enabled := make(map[string]string, len(checkRules))
+	for _, r := range checkRules {
+		defaults[r.ID] = r.Severity
+		if r.Enabled {
+			enabled[r.ID] = r.Severity
+		}
+	}
+	check := func(field, id string) error {
+		if _, ok := defaults[id]; !ok {
+			return fmt.Errorf("config: %s: unknown check rule %q", field, id)
+		}
+		return nil
+	}
+	for _, id := range cfg.Enable {
+		if err := check("check.enable", id); err != nil {
+			return nil, err
+		}
+		enabled[id] = defaults[id]
+	}
+	for _, id := range cfg.Disable {
+		if err := check("check.disable", id); err != nil {
+			return nil, err
+		}
+		delete(enabled, id)
+	}
+	for id, sev := range cfg.Severity {
+		if err := check("check.severity", id); err != nil {
+			return nil, err
+		}
+		if _, ok := enabled[id]; ok {
+			enabled[id] = sev
+		}
+	}
+	return &checkRuleSet{severity: enabled}, nil
+}
+
+// enabled reports whether the rule with the given ID runs.
+func (rs *checkRuleSet) enabled(id string) bool {
+	_, ok := rs.severity[id]
+	return ok
+}
+
+// apply drops issues of disabled rules and sets the severity of the rest.
+func (rs *checkRuleSet) apply(issues []checkIssue) []checkIssue {
+	kept := issues[:0]
+	for _, it := range issues {
+		sev, ok := rs.severity[it.Kind]
+		if !ok {
+			continue
+		}
+		it.Severity = sev
+		kept = append(kept, it)
+	}
+	return kept
+}
+
+// countSeverities returns the number of issues per severity.
+func countSeverities(issues []checkIssue) (errs, warnings, infos int) {
+	for _, it := range issues {
+		switch it.Severity {
+		case severityError:
+			errs++
+		case severityWarning:
+			warnings++
+		default:
+			infos++
+		}
+	}
+	return errs, warnings, infos
 }
 
-func lintArticles(articles []*model.Article, contentDir string) []checkIssue {
+// lintArticles checks the front matter of articles. cfg.Build.ContentDir
+// must be resolved against the project root.
+func lintArticles(articles []*model.Article, cfg model.Config) []checkIssue {
 	var issues []checkIssue
+	contentDir := cfg.Build.ContentDir
 
 	// Group by directory + slug to detect duplicates.
 	type key struct{ dir, slug string }
@@ -101,12 +341,20 @@ func lintArticles(articles []*model.Article, contentDir string) []checkIssue {
 		}
 
 		slug := a.FrontMatter.Slug
-		if slug == "" {
+		dir := filepath.Dir(rel)
+		base := filepath.Base(a.FilePath)
+		if processor.IsLeafBundle(a, cfg) {
+			// A leaf bundle (posts/my-post/index.md) is published under
+			// its directory name, next to posts/my-post.md. The index.md
+			// of a section is an ordinary article, as in the generator.
+			if slug == "" {
+				slug = filepath.Base(dir)
+			}
+			dir = filepath.Dir(dir)
+		} else if slug == "" {
 			// Fall back to filename without extension, mirroring the generator.
-			base := filepath.Base(a.FilePath)
 			slug = base[:len(base)-len(filepath.Ext(base))]
 		}
-		dir := filepath.Dir(rel)
 		k := key{dir: dir, slug: slug}
 		bySlug[k] = append(bySlug[k], rel)
 
@@ -162,15 +410,55 @@ func writeCheckReport(w io.Writer, issues []checkIssue) {
 		_, _ = fmt.Fprintln(w, "check: no issues found")
 		return
 	}
-	// Stable order: by file, then kind.
+	sortIssues(issues)
+	for _, it := range issues {
+		_, _ = fmt.Fprintf(w, "%s: %s: [%s] %s\n", issueLocation(it), it.Severity, it.Kind, it.Message)
+	}
+	errs, warnings, infos := countSeverities(issues)
+	_, _ = fmt.Fprintf(w, "\ncheck: %d issue(s) (%d error(s), %d warning(s), %d info)\n", len(issues), errs, warnings, infos)
+}
+
+// issueLocation returns "file" or "file:line".
+func issueLocation(it checkIssue) string {
+	if it.Line > 0 {
+		return fmt.Sprintf("%s:%d", it.File, it.Line)
+	}
+	return it.File
+}
+
+// logCheckReport emits one record per issue, at the level matching its
+// severity, followed by a summary record; it is the --log-format=json
+// counterpart of writeCheckReport.
+func logCheckReport(log *cliLog, issues []checkIssue) {
+	sortIssues(issues)
+	for _, it := range issues {
+		args := []any{"file", it.File, "kind", it.Kind, "severity", it.Severity, "message", it.Message}
+		if it.Line > 0 {
+			args = append(args, "line", it.Line)
+		}
+		log.json.Log(context.Background(), slogLevel(it.Severity), "issue", args...)
+	}
+	errs, warnings, infos := countSeverities(issues)
+	log.json.Info("check complete", "issues", len(issues), "errors", errs, "warnings", warnings, "infos", infos)
+}
+
+// slogLevel maps a check severity to a log level.
+func slogLevel(severity string) slog.Level {
+	switch severity {
+	case severityError:
+		return slog.LevelError
+	case severityWarning:
+		return slog.LevelWarn
+	}
+	return slog.LevelInfo
+}
+
+// sortIssues orders issues by file, then kind, so reports are stable.
+func sortIssues(issues []checkIssue) {
 	sort.SliceStable(issues, func(i, j int) bool {
 		if issues[i].File != issues[j].File {
 			return issues[i].File < issues[j].File
 		}
 		return issues[i].Kind < issues[j].Kind
 	})
-	for _, it := range issues {
-		_, _ = fmt.Fprintf(w, "%s: [%s] %s\n", it.File, it.Kind, it.Message)
-	}
-	_, _ = fmt.Fprintf(w, "\ncheck: %d issue(s)\n", len(issues))
 }
@@ -2,6 +2,8 @@ package main
 
 import (
 	"bytes"
+	"encoding/json"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -33,7 +35,7 @@ func TestLintArticles_NoIssues(t *testing.T) {
 			Slug:  "hello",
 		},
 	}}
-	issues := lintArticles(articles, contentDir)
+	issues := lintArticles(articles, model.Config{Build: model.BuildConfig{ContentDir: contentDir}})
 	if len(issues) != 0 {
 		t.Fatalf("expected no issues, got %d: %#v", len(issues), issues)
 	}
@@ -45,7 +47,7 @@ func TestLintArticles_MissingTitleAndDate(t *testing.T) {
 		FilePath:    filepath.Join(contentDir, "posts", "incomplete.md"),
 		FrontMatter: model.FrontMatter{},
 	}}
-	issues := lintArticles(articles, contentDir)
+	issues := lintArticles(articles, model.Config{Build: model.BuildConfig{ContentDir: contentDir}})
 	kinds := map[string]bool{}
 	for _, it := range issues {
 		kinds[it.Kind] = true
@@ -71,7 +73,7 @@ func TestLintArticles_DuplicateSlug(t *testing.T) {
 			FrontMatter: model.FrontMatter{Title: "B", Date: now, Slug: "dup"},
 		},
 	}
-	issues := lintArticles(articles, contentDir)
+	issues := lintArticles(articles, model.Config{Build: model.BuildConfig{ContentDir: contentDir}})
 	found := false
 	for _, it := range issues {
 		if it.Kind == "duplicate-slug" {
@@ -83,6 +85,46 @@ func TestLintArticles_DuplicateSlug(t *testing.T) {
 	}
 }
 
+func TestLintArticles_DuplicateSlug_LeafBundle(t *testing.T) {
+	contentDir := t.TempDir()
+	now := time.Now()
+	articles := []*model.Article{
+		{
+			FilePath:    filepath.Join(contentDir, "posts", "a.md"),
+			FrontMatter: model.FrontMatter{Title: "A", Date: now},
+		},
+		{
+			FilePath:    filepath.Join(contentDir, "posts", "a", "index.md"),
+			FrontMatter: model.FrontMatter{Title: "A bundle", Date: now},
+		},
+	}
+	issues := lintArticles(articles, model.Config{Build: model.BuildConfig{ContentDir: contentDir}})
+	if len(issues) != 1 || issues[0].Kind != "duplicate-slug" {
+		t.Fatalf("expected one duplicate-slug issue, got %#v", issues)
+	}
+}
+
+func TestLintArticles_SectionIndexIsNotBundle(t *testing.T) {
+	contentDir := t.TempDir()
+	now := time.Now()
+	var articles []*model.Article
+	for _, rel := range []string{"posts.md", "posts/index.md", "posts/hello.md"} {
+		p := filepath.Join(contentDir, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte("x"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		articles = append(articles, &model.Article{FilePath: p, FrontMatter: model.FrontMatter{Title: rel, Date: now}})
+	}
+	// posts/index.md sits next to another post, so it is published at
+	// /posts/index/ like the generator does, not at /posts/ over posts.md.
+	if issues := lintArticles(articles, model.Config{Build: model.BuildConfig{ContentDir: contentDir}}); len(issues) != 0 {
+		t.Errorf("expected no issues, got %#v", issues)
+	}
+}
+
 func TestLintArticles_OrphanTranslationKey(t *testing.T) {
 	contentDir := t.TempDir()
 	now := time.Now()
@@ -95,7 +137,7 @@ func TestLintArticles_OrphanTranslationKey(t *testing.T) {
 			TranslationKey: "hello-key",
 		},
 	}}
-	issues := lintArticles(articles, contentDir)
+	issues := lintArticles(articles, model.Config{Build: model.BuildConfig{ContentDir: contentDir}})
 	found := false
 	for _, it := range issues {
 		if it.Kind == "orphan-translation-key" {
@@ -126,7 +168,7 @@ func TestLintArticles_PairedTranslationKey_NoIssue(t *testing.T) {
 			},
 		},
 	}
-	issues := lintArticles(articles, contentDir)
+	issues := lintArticles(articles, model.Config{Build: model.BuildConfig{ContentDir: contentDir}})
 	for _, it := range issues {
 		if it.Kind == "orphan-translation-key" {
 			t.Errorf("paired translation_key flagged as orphan: %#v", it)
@@ -156,6 +198,93 @@ func TestWriteCheckReport_FormatsIssues(t *testing.T) {
 	}
 }
 
+func TestLogCheckReport_JSON(t *testing.T) {
+	var buf bytes.Buffer
+	log, err := newCLILog("json", &buf, io.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	logCheckReport(log, []checkIssue{
+		{File: "posts/b.md", Kind: "missing-date", Severity: severityWarning, Message: "no date"},
+		{File: "posts/a.md", Kind: "missing-title", Severity: severityError, Message: "no title"},
+	})
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected 2 issue records and a summary, got %d lines:\n%s", len(lines), buf.String())
+	}
+	var first, last map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &first); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal([]byte(lines[2]), &last); err != nil {
+		t.Fatal(err)
+	}
+	if first["level"] != "ERROR" || first["file"] != "posts/a.md" || first["kind"] != "missing-title" {
+		t.Errorf("unexpected issue record: %v", first)
+	}
+	if last["msg"] != "check complete" || last["issues"] != float64(2) || last["warnings"] != float64(1) {
+		t.Errorf("unexpected summary record: %v", last)
+	}
+}
+
+func TestNewCheckRuleSet(t *testing.T) {
+	rules, err := newCheckRuleSet(model.CheckConfig{
+		Severity: map[string]string{"missing-date": "warning"},
+		Disable:  []string{"orphan-translation-key"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	issues := rules.apply([]checkIssue{
+		{File: "a.md", Kind: "missing-title"},
+		{File: "a.md", Kind: "missing-date"},
+		{File: "a.md", Kind: "orphan-translation-key"},
+	})
+	if len(issues) != 2 {
+		t.Fatalf("expected disabled rule to be dropped, got %#v", issues)
+	}
+	if issues[0].Severity != severityError || issues[1].Severity != severityWarning {
+		t.Errorf("unexpected severities: %#v", issues)
+	}
+	if errs, warnings, _ := countSeverities(issues); errs != 1 || warnings != 1 {
+		t.Errorf("countSeverities = %d errors, %d warnings, want 1, 1", errs, warnings)
+	}
+}
+
+func TestNewCheckRuleSet_UnknownRule(t *testing.T) {
+	for _, cfg := range []model.CheckConfig{
+		{Enable: []string{"nope"}},
+		{Disable: []string{"nope"}},
+		{Severity: map[string]string{"nope": "info"}},
+	} {
+		if _, err := newCheckRuleSet(cfg); err == nil {
+			t.Errorf("expected error for unknown rule in %+v", cfg)
+		}
+	}
+}
+
+func TestRunCheck_WarningsDoNotFail(t *testing.T) {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "config.yaml"), []byte(
+		"site:\n  title: Test\n  base_url: https://example.com\ncheck:\n  severity:\n    missing-date: warning\n",
+	), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	writeCheckArticle(t, root, "content/posts/a.md", "title: A", "body")
+
+	oldWd, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(oldWd) })
+	if err := os.Chdir(root); err != nil {
+		t.Fatalf("chdir: %v", err)
+	}
+	if err := runCheck([]string{"--format=json"}); err != nil {
+		t.Errorf("expected warnings not to fail the check, got %v", err)
+	}
+	if err := runCheck([]string{"--format=xml"}); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
+
 func TestRunCheck_EndToEnd(t *testing.T) {
 	root := t.TempDir()
 	if err := os.WriteFile(filepath.Join(root, "config.yaml"), []byte(
@@ -181,3 +310,72 @@ func TestRunCheck_EndToEnd(t *testing.T) {
 		t.Errorf("expected issue error, got %v", err)
 	}
 }
+
+func TestLintSource_SchemaViolation(t *testing.T) {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "config.yaml"), []byte(
+		"site:\n  title: Test\n  base_url: https://example.com\n"+
+			"content_schema:\n  posts:\n    fields:\n      difficulty:\n        enum: [easy, hard]\n",
+	), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	writeCheckArticle(t, root, "content/posts/a.md", "title: A\ndate: 2024-01-01\ndifficulty: medium", "body")
+	writeCheckArticle(t, root, "content/pages/b.md", "title: B\ndate: 2024-01-01\ndifficulty: medium", "body")
+
+	cfg := loadCheckConfig(t, root)
+	cfg.Check.Disable = []string{"missing-template", "template-error"}
+	rules, _ := newCheckRuleSet(cfg.Check)
+	issues, err := lintSource(root, cfg, rules, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := checkIssue{File: filepath.Join("posts", "a.md"), Kind: "schema-violation", Message: `difficulty: value "medium" is not one of easy, hard`}
+	if len(issues) != 1 || issues[0] != want {
+		t.Errorf("issues = %+v, want [%+v]", issues, want)
+	}
+}
+
+func TestRunCheck_MachineReadableReportKeepsLogsOffStdout(t *testing.T) {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "config.yaml"), []byte(
+		"site:\n  title: Test\n  base_url: https://example.com\n",
+	), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	writeCheckArticle(t, root, "content/posts/a.md", "title: A\ndate: 2024-01-01\nslug: dup", "body")
+	writeCheckArticle(t, root, "content/posts/b.md", "title: B\ndate: 2024-01-02\nslug: dup", "body")
+
+	oldWd, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(oldWd) })
+	if err := os.Chdir(root); err != nil {
+		t.Fatalf("chdir: %v", err)
+	}
+
+	orig := os.Stdout
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.Stdout = w
+	checkErr := runCheck([]string{"--format=sarif", "--log-format=json"})
+	_ = w.Close()
+	os.Stdout = orig
+	if checkErr == nil {
+		t.Fatal("expected the duplicate slug to fail the check")
+	}
+	var buf bytes.Buffer
+	_, _ = io.Copy(&buf, r)
+
+	dec := json.NewDecoder(&buf)
+	var report map[string]any
+	if err := dec.Decode(&report); err != nil {
+		t.Fatalf("stdout is not a SARIF document: %v", err)
+	}
+	if _, ok := report["runs"]; !ok {
+		t.Errorf("SARIF report has no runs: %v", report)
+	}
+	if dec.More() {
+		rest, _ := io.ReadAll(io.MultiReader(dec.Buffered(), &buf))
+		t.Errorf("stdout has data after the report: %q", rest)
+	}
+}
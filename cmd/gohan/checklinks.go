@@ -0,0 +1,217 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/bmf-san/gohan/internal/generator"
+	"github.com/bmf-san/gohan/internal/linkcheck"
+	"github.com/bmf-san/gohan/internal/model"
+	"github.com/bmf-san/gohan/internal/parser"
+	"github.com/bmf-san/gohan/internal/plugin"
+	"github.com/bmf-san/gohan/internal/processor"
+)
+
+// checkSite assembles the site the way `gohan build --draft --future` does,
+// without rendering any page, so that rules can resolve URLs against it. The
+// directories in cfg.Build must be absolute (see resolveBuildDirs).
+func checkSite(articles []*model.Article, cfg model.Config) (*model.Site, error) {
+	proc := processor.NewSiteProcessor()
+	processed, err := proc.Process(articles, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("process articles: %w", err)
+	}
+	proc.BuildTranslationMap(processed)
+
+	regs, err := processor.LoadLocaleAwareTaxonomyRegistries(cfg.Build.ContentDir, cfg.I18n.Locales, cfg.Taxonomies...)
+	if err != nil {
+		return nil, fmt.Errorf("load taxonomy registries: %w", err)
+	}
+	derived, err := proc.BuildTaxonomyRegistry(processed, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("build taxonomy: %w", err)
+	}
+	taxo := processor.ResolveTaxonomyRegistry(derived, processor.MergeTaxonomyRegistries(regs))
+
+	site := &model.Site{
+		Config:     cfg,
+		Articles:   processed,
+		Tags:       taxo.Tags,
+		Categories: taxo.Categories,
+		Taxonomies: taxo.Taxonomies,
+		Series:     proc.BuildSeries(processed),
+	}
+	if err := plugin.DefaultRegistry().Enrich(site); err != nil {
+		return nil, fmt.Errorf("plugin enrichment: %w", err)
+	}
+	if err := plugin.DefaultRegistry().EnrichVirtual(site); err != nil {
+		return nil, fmt.Errorf("plugin virtual pages: %w", err)
+	}
+	return site, nil
+}
+
+// linkTargets is the set of URL paths (e.g. "/posts/hello/index.html") a
+// build of the site produces: article, listing, taxonomy, archive, and
+// virtual pages, feeds, copied assets and static files, and OGP images.
+type linkTargets map[string]bool
+
+// newLinkTargets collects the outputs a build of site would write.
+func newLinkTargets(site *model.Site) linkTargets {
+	cfg := site.Config
+	outDir := cfg.Build.OutputDir
+	outputs := append(generator.NewHTMLGenerator(outDir, nil, cfg).PlannedOutputs(site), generator.FeedOutputs(outDir, cfg)...)
+	outputs = append(outputs, generator.TaxonomyFeedOutputs(outDir, site, cfg)...)
+	targets := make(linkTargets, len(outputs))
+	for _, p := range outputs {
+		if rel, err := filepath.Rel(outDir, p); err == nil {
+			targets["/"+filepath.ToSlash(rel)] = true
+		}
+	}
+	return targets
+}
+
+// has reports whether the URL path p is served by the site. Directory URLs
+// resolve to their index.html, with or without the trailing slash.
+func (t linkTargets) has(p string) bool {
+	if strings.HasSuffix(p, "/") {
+		return t[p+"index.html"]
+	}
+	return t[p] || t[p+"/index.html"]
+}
+
+// lintLinks reports Markdown links whose target page the site does not
+// generate (broken-link) and images or file links whose target does not exist
+// (missing-asset). Relative references are resolved against the URL of the
+// article they appear in; absolute URLs count as internal when they point at
+// site.base_url.
+func lintLinks(site *model.Site) []checkIssue {
+	cfg := site.Config
+	targets := newLinkTargets(site)
+	base, _ := url.Parse(cfg.Site.BaseURL)
+
+	var issues []checkIssue
+	for _, a := range site.Articles {
+		rel, err := filepath.Rel(cfg.Build.ContentDir, a.FilePath)
+		if err != nil {
+			rel = a.FilePath
+		}
+		pageURL := outputURL(cfg.Build.OutputDir, a.OutputPath)
+		offset := bodyLineOffset(a.FilePath, a.RawContent)
+		for _, l := range parser.ExtractLinks([]byte(a.RawContent)) {
+			target, ok := internalPath(l.Destination, pageURL, base)
+			if !ok || targets.has(target) {
+				continue
+			}
+			it := checkIssue{File: rel, Line: l.Line + offset}
+			switch ext := path.Ext(target); {
+			case l.Image:
+				it.Kind = "missing-asset"
+				it.Message = fmt.Sprintf("image %q: no file at %s", l.Destination, target)
+			case ext != "" && ext != ".html":
+				it.Kind = "missing-asset"
+				it.Message = fmt.Sprintf("link %q: no file at %s", l.Destination, target)
+			default:
+				it.Kind = "broken-link"
+				it.Message = fmt.Sprintf("link %q: no page at %s", l.Destination, target)
+			}
+			issues = append(issues, it)
+		}
+	}
+	return issues
+}
+
+// lintOutput checks the links of the site already built into the output
+// directory (see linkcheck.Check). Issue paths are relative to rootDir.
+func lintOutput(rootDir string, cfg model.Config, rules *checkRuleSet) ([]checkIssue, error) {
+	outDir := filepath.Join(rootDir, cfg.Build.OutputDir)
+	if _, err := os.Stat(outDir); err != nil {
+		return nil, fmt.Errorf("output directory %s not found; run gohan build first", outDir)
+	}
+	problems, err := linkcheck.Check(outDir, linkcheck.Options{
+		BaseURL:     cfg.Site.BaseURL,
+		Anchors:     rules.enabled("output-missing-anchor"),
+		Parallelism: cfg.Build.Parallelism,
+	})
+	if err != nil {
+		return nil, err
+	}
+	issues := make([]checkIssue, 0, len(problems))
+	for _, p := range problems {
+		it := checkIssue{
+			File: filepath.ToSlash(filepath.Join(cfg.Build.OutputDir, filepath.FromSlash(p.Page))),
+			Line: p.Line,
+		}
+		if p.Kind == linkcheck.KindMissingAnchor {
+			it.Kind = "output-missing-anchor"
+			it.Message = fmt.Sprintf("%q: the target page has no element with this id", p.URL)
+		} else {
+			it.Kind = "output-broken-link"
+			it.Message = fmt.Sprintf("%q: no such file in the output directory", p.URL)
+		}
+		issues = append(issues, it)
+	}
+	return issues, nil
+}
+
+// outputURL returns the URL path of the page written to outputPath, e.g.
+// "/posts/hello/" for <outDir>/posts/hello/index.html.
+func outputURL(outDir, outputPath string) string {
+	rel, err := filepath.Rel(outDir, outputPath)
+	if err != nil {
+		return "/"
+	}
+	dir := filepath.ToSlash(filepath.Dir(rel))
+	if dir == "." {
+		return "/"
+	}
+	return "/" + dir + "/"
+}
+
+// internalPath resolves a link destination found on the page at pageURL to a
+// site-root URL path. It returns false for links that leave the site (other
+// hosts, mailto:, etc.) and for fragment- or query-only links.
+func internalPath(dest, pageURL string, base *url.URL) (string, bool) {
+	u, err := url.Parse(dest)
+	if err != nil {
+		return "", false
+	}
+	if u.Scheme != "" || u.Host != "" {
+		if base == nil || u.Host != base.Host || (u.Scheme != "" && u.Scheme != "http" && u.Scheme != "https") {
+			return "", false
+		}
+	}
+	p := u.Path
+	if p == "" {
+		return "", false
+	}
+	if !strings.HasPrefix(p, "/") {
+		joined := path.Join(pageURL, p)
+		if strings.HasSuffix(p, "/") && joined != "/" {
+			joined += "/"
+		}
+		return joined, true
+	}
+	// A site deployed under a sub-path (base_url "https://example.com/blog")
+	// is linked to as /blog/...; outputs are relative to the site root.
+	if base != nil {
+		if prefix := strings.TrimSuffix(base.Path, "/"); prefix != "" && strings.HasPrefix(p, prefix+"/") {
+			p = strings.TrimPrefix(p, prefix)
+		}
+	}
+	return p, true
+}
+
+// bodyLineOffset returns the number of lines preceding body in the file at
+// filePath, i.e. the length of the front matter block, so that line numbers
+// within the Markdown body can be reported as file line numbers.
+func bodyLineOffset(filePath, body string) int {
+	data, err := os.ReadFile(filePath)
+	if err != nil || !strings.HasSuffix(string(data), body) {
+		return 0
+	}
+	return strings.Count(string(data[:len(data)-len(body)]), "\n")
+}
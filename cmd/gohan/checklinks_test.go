@@ -0,0 +1,134 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/bmf-san/gohan/internal/config"
+	"github.com/bmf-san/gohan/internal/model"
+	"github.com/bmf-san/gohan/internal/parser"
+)
+
+func TestInternalPath(t *testing.T) {
+	base, _ := url.Parse("https://example.com/blog")
+	tests := []struct {
+		dest   string
+		want   string
+		wantOK bool
+	}{
+		{"/posts/a/", "/posts/a/", true},
+		{"/blog/posts/a/", "/posts/a/", true},
+		{"../b/#top", "/posts/b/", true},
+		{"img/x.png?v=1", "/posts/hello/img/x.png", true},
+		{"https://example.com/blog/tags/go/", "/tags/go/", true},
+		{"https://other.example/x/", "", false},
+		{"mailto:me@example.com", "", false},
+		{"#section", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := internalPath(tt.dest, "/posts/hello/", base)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("internalPath(%q) = %q, %v; want %q, %v", tt.dest, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestLintLinks(t *testing.T) {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "config.yaml"), []byte(
+		"site:\n  title: Test\n  base_url: https://example.com\n",
+	), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	writeCheckArticle(t, root, "content/posts/a.md", "title: A\ndate: 2024-01-01\ntags: [go]", "\n"+
+		"[b](/posts/b/) [b again](../b) [tag](/tags/go/) [feed](/atom.xml)\n"+
+		"[gone](/posts/nope/)\n"+
+		"![ok](/assets/img/ok.png) ![missing](/assets/img/missing.png)\n"+
+		"[pdf](https://example.com/files/x.pdf) [external](https://other.example/nope/)\n")
+	writeCheckArticle(t, root, "content/posts/b.md", "title: B\ndate: 2024-01-02", "body")
+	if err := os.MkdirAll(filepath.Join(root, "assets", "img"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "assets", "img", "ok.png"), []byte("png"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := config.New(root).Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	resolveBuildDirs(cfg, root)
+	articles, err := parser.NewFileParser().ParseAll(cfg.Build.ContentDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	site, err := checkSite(articles, *cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	issues := lintLinks(site)
+	var got []string
+	for _, it := range issues {
+		if it.File != filepath.Join("posts", "a.md") {
+			t.Errorf("unexpected file %q", it.File)
+		}
+		got = append(got, fmt.Sprintf("%s@%d", it.Kind, it.Line))
+	}
+	sort.Strings(got)
+	// The body starts on line 6, after the five front matter lines.
+	want := []string{"broken-link@8", "missing-asset@10", "missing-asset@9"}
+	if len(got) != len(want) {
+		t.Fatalf("got issues %v, want %v: %#v", got, want, issues)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("got issues %v, want %v", got, want)
+			break
+		}
+	}
+}
+
+func TestLintOutput(t *testing.T) {
+	root := t.TempDir()
+	pages := map[string]string{
+		"public/index.html":         "<a href=\"/posts/a/\">a</a>\n<a href=\"/posts/a/#intro\">intro</a>\n<a href=\"/posts/a/#gone\">gone</a>\n",
+		"public/posts/a/index.html": "<h2 id=\"intro\">Intro</h2>\n<img src=\"missing.png\">\n",
+	}
+	for name, body := range pages {
+		p := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(body), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cfg := model.Config{Build: model.BuildConfig{OutputDir: "public"}}
+
+	rules, _ := newCheckRuleSet(model.CheckConfig{})
+	issues, err := lintOutput(root, cfg, rules)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(issues) != 1 || issues[0].Kind != "output-broken-link" || issues[0].File != "public/posts/a/index.html" || issues[0].Line != 2 {
+		t.Errorf("issues = %+v, want one output-broken-link at public/posts/a/index.html:2", issues)
+	}
+
+	rules, _ = newCheckRuleSet(model.CheckConfig{Enable: []string{"output-missing-anchor"}})
+	issues, err = lintOutput(root, cfg, rules)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(issues) != 2 || issues[0].Kind != "output-missing-anchor" || issues[0].Line != 3 {
+		t.Errorf("issues = %+v, want output-missing-anchor at public/index.html:3 first", issues)
+	}
+
+	if _, err := lintOutput(t.TempDir(), cfg, rules); err == nil {
+		t.Error("expected error for a missing output directory")
+	}
+}
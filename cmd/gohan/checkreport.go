@@ -0,0 +1,193 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// checkReportWriters maps each --format value of `gohan check` to the
+// function that writes the report.
+var checkReportWriters = map[string]func(io.Writer, []checkIssue) error{
+	"text": func(w io.Writer, issues []checkIssue) error {
+		writeCheckReport(w, issues)
+		return nil
+	},
+	"json":   writeCheckJSON,
+	"sarif":  writeCheckSARIF,
+	"github": writeCheckGitHub,
+}
+
+// checkJSONIssue is the JSON form of a checkIssue.
+type checkJSONIssue struct {
+	File     string `json:"file"`
+	Line     int    `json:"line,omitempty"`
+	Rule     string `json:"rule"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+// writeCheckJSON writes issues as a single JSON document with a summary of
+// the counts per severity.
+func writeCheckJSON(w io.Writer, issues []checkIssue) error {
+	sortIssues(issues)
+	type summary struct {
+		Errors   int `json:"errors"`
+		Warnings int `json:"warnings"`
+		Infos    int `json:"infos"`
+	}
+	doc := struct {
+		Issues  []checkJSONIssue `json:"issues"`
+		Summary summary          `json:"summary"`
+	}{Issues: make([]checkJSONIssue, 0, len(issues))}
+	for _, it := range issues {
+		doc.Issues = append(doc.Issues, checkJSONIssue{
+			File:     it.File,
+			Line:     it.Line,
+			Rule:     it.Kind,
+			Severity: it.Severity,
+			Message:  it.Message,
+		})
+	}
+	doc.Summary.Errors, doc.Summary.Warnings, doc.Summary.Infos = countSeverities(issues)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(doc)
+}
+
+// SARIF 2.1.0 subset used by writeCheckSARIF. See
+// https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html.
+type (
+	sarifLog struct {
+		Schema  string     `json:"$schema"`
+		Version string     `json:"version"`
+		Runs    []sarifRun `json:"runs"`
+	}
+	sarifRun struct {
+		Tool    sarifTool     `json:"tool"`
+		Results []sarifResult `json:"results"`
+	}
+	sarifTool struct {
+		Driver sarifDriver `json:"driver"`
+	}
+	sarifDriver struct {
+		Name           string      `json:"name"`
+		Version        string      `json:"version"`
+		InformationURI string      `json:"informationUri"`
+		Rules          []sarifRule `json:"rules"`
+	}
+	sarifRule struct {
+		ID                   string        `json:"id"`
+		ShortDescription     sarifMessage  `json:"shortDescription"`
+		DefaultConfiguration sarifRuleConf `json:"defaultConfiguration"`
+	}
+	sarifRuleConf struct {
+		Level string `json:"level"`
+	}
+	sarifResult struct {
+		RuleID    string          `json:"ruleId"`
+		Level     string          `json:"level"`
+		Message   sarifMessage    `json:"message"`
+		Locations []sarifLocation `json:"locations"`
+	}
+	sarifMessage struct {
+		Text string `json:"text"`
+	}
+	sarifLocation struct {
+		PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+	}
+	sarifPhysicalLocation struct {
+		ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+		Region           *sarifRegion          `json:"region,omitempty"`
+	}
+	sarifArtifactLocation struct {
+		URI string `json:"uri"`
+	}
+	sarifRegion struct {
+		StartLine int `json:"startLine"`
+	}
+)
+
+// sarifLevel maps a check severity to a SARIF result level.
+func sarifLevel(severity string) string {
+	switch severity {
+	case severityError:
+		return "error"
+	case severityWarning:
+		return "warning"
+	}
+	return "note"
+}
+
+// writeCheckSARIF writes issues as a SARIF 2.1.0 log, the format accepted by
+// GitHub code scanning and most CI annotation tools.
+func writeCheckSARIF(w io.Writer, issues []checkIssue) error {
+	sortIssues(issues)
+	driver := sarifDriver{
+		Name:           "gohan",
+		Version:        version,
+		InformationURI: "https://github.com/bmf-san/gohan",
+	}
+	for _, r := range checkRules {
+		driver.Rules = append(driver.Rules, sarifRule{
+			ID:                   r.ID,
+			ShortDescription:     sarifMessage{Text: r.Description},
+			DefaultConfiguration: sarifRuleConf{Level: sarifLevel(r.Severity)},
+		})
+	}
+	run := sarifRun{Tool: sarifTool{Driver: driver}, Results: make([]sarifResult, 0, len(issues))}
+	for _, it := range issues {
+		loc := sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: it.File}}
+		if it.Line > 0 {
+			loc.Region = &sarifRegion{StartLine: it.Line}
+		}
+		run.Results = append(run.Results, sarifResult{
+			RuleID:    it.Kind,
+			Level:     sarifLevel(it.Severity),
+			Message:   sarifMessage{Text: it.Message},
+			Locations: []sarifLocation{{PhysicalLocation: loc}},
+		})
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{run},
+	})
+}
+
+// writeCheckGitHub writes issues as GitHub Actions workflow commands, which
+// show up as annotations on the pull request diff.
+func writeCheckGitHub(w io.Writer, issues []checkIssue) error {
+	sortIssues(issues)
+	for _, it := range issues {
+		cmd := "notice"
+		switch it.Severity {
+		case severityError:
+			cmd = "error"
+		case severityWarning:
+			cmd = "warning"
+		}
+		props := "file=" + escapeGitHubProperty(it.File)
+		if it.Line > 0 {
+			props += fmt.Sprintf(",line=%d", it.Line)
+		}
+		props += ",title=" + escapeGitHubProperty(it.Kind)
+		if _, err := fmt.Fprintf(w, "::%s %s::%s\n", cmd, props, escapeGitHubData(it.Message)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// escapeGitHubData escapes a workflow command message.
+func escapeGitHubData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+// escapeGitHubProperty escapes a workflow command property value.
+func escapeGitHubProperty(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func testCheckIssues() []checkIssue {
+	return []checkIssue{
+		{File: "content/posts/b.md", Line: 7, Kind: "missing-date", Severity: severityWarning, Message: "no date, really"},
+		{File: "content/posts/a.md", Kind: "missing-title", Severity: severityError, Message: "no title"},
+	}
+}
+
+func TestWriteCheckJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeCheckJSON(&buf, testCheckIssues()); err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		Issues  []checkJSONIssue `json:"issues"`
+		Summary struct {
+			Errors   int `json:"errors"`
+			Warnings int `json:"warnings"`
+		} `json:"summary"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+	if len(doc.Issues) != 2 || doc.Issues[0].File != "content/posts/a.md" || doc.Issues[1].Line != 7 {
+		t.Errorf("unexpected issues: %+v", doc.Issues)
+	}
+	if doc.Summary.Errors != 1 || doc.Summary.Warnings != 1 {
+		t.Errorf("unexpected summary: %+v", doc.Summary)
+	}
+}
+
+func TestWriteCheckJSON_NoIssues(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeCheckJSON(&buf, nil); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), `"issues": []`) {
+		t.Errorf("expected empty issues array, got %s", buf.String())
+	}
+}
+
+func TestWriteCheckSARIF(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeCheckSARIF(&buf, testCheckIssues()); err != nil {
+		t.Fatal(err)
+	}
+	var log sarifLog
+	if err := json.Unmarshal(buf.Bytes(), &log); err != nil {
+		t.Fatalf("invalid SARIF: %v\n%s", err, buf.String())
+	}
+	if log.Version != "2.1.0" || len(log.Runs) != 1 {
+		t.Fatalf("unexpected SARIF log: %+v", log)
+	}
+	run := log.Runs[0]
+	if len(run.Tool.Driver.Rules) != len(checkRules) {
+		t.Errorf("expected %d rules, got %d", len(checkRules), len(run.Tool.Driver.Rules))
+	}
+	if len(run.Results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(run.Results))
+	}
+	first, second := run.Results[0], run.Results[1]
+	if first.Level != "error" || first.Locations[0].PhysicalLocation.Region != nil {
+		t.Errorf("unexpected first result: %+v", first)
+	}
+	if second.Level != "warning" || second.Locations[0].PhysicalLocation.Region.StartLine != 7 {
+		t.Errorf("unexpected second result: %+v", second)
+	}
+}
+
+func TestWriteCheckGitHub(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeCheckGitHub(&buf, testCheckIssues()); err != nil {
+		t.Fatal(err)
+	}
+	want := "::error file=content/posts/a.md,title=missing-title::no title\n" +
+		"::warning file=content/posts/b.md,line=7,title=missing-date::no date, really\n"
+	if buf.String() != want {
+		t.Errorf("got:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
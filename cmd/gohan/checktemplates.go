@@ -0,0 +1,130 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bmf-san/gohan/internal/assets"
+	"github.com/bmf-san/gohan/internal/generator"
+	"github.com/bmf-san/gohan/internal/model"
+	gohantemplate "github.com/bmf-san/gohan/internal/template"
+)
+
+// standardTemplates are the templates every build renders as soon as the
+// site has articles, tags, categories, or dated articles.
+var standardTemplates = []string{"index.html", "article.html", "tag.html", "category.html", "archive.html"}
+
+// lintTemplates loads the theme templates from templateDir the way `gohan
+// build` does and reports templates that are missing (missing-template) or
+// fail to parse or execute (template-error). Every page type is dry-rendered
+// once, with the data of the first page a build would render with that
+// template. Issue paths are absolute.
+func lintTemplates(site *model.Site, templateDir string, rules *checkRuleSet) []checkIssue {
+	checkMissing := rules.enabled("missing-template")
+	checkErrors := rules.enabled("template-error")
+	if !checkMissing && !checkErrors {
+		return nil
+	}
+	cfg := site.Config
+
+	if info, err := os.Stat(templateDir); err != nil || !info.IsDir() {
+		return []checkIssue{{File: templateDir, Kind: "missing-template", Message: "theme templates directory not found"}}
+	}
+	var issues []checkIssue
+	// Templates resolve assets through the pipeline, as in `gohan build`. A
+	// bundle with a missing source fails the build; the remaining assets
+	// still resolve for the dry-render.
+	pipeline, err := assets.New(cfg.Build.AssetsDir, cfg.Assets)
+	if err != nil {
+		issues = append(issues, checkIssue{File: cfg.Build.AssetsDir, Kind: "template-error", Message: err.Error()})
+		pipeline, _ = assets.New(cfg.Build.AssetsDir, model.AssetsConfig{Minify: cfg.Assets.Minify, Fingerprint: cfg.Assets.Fingerprint})
+	}
+	engine := gohantemplate.NewEngine()
+	if err := engine.Load(templateDir, pipeline.FuncMap(), cfg.I18n.DefaultLocale); err != nil {
+		return append(issues, checkIssue{File: templateDir, Kind: "template-error", Message: err.Error()})
+	}
+	files := templateFiles(templateDir)
+	fileOf := func(name string) string {
+		if p, ok := files[name]; ok {
+			return p
+		}
+		return filepath.Join(templateDir, name)
+	}
+
+	reported := map[string]bool{} // missing template names already reported for the theme
+	if checkMissing {
+		for _, name := range standardTemplates {
+			if !engine.Has(name) {
+				reported[name] = true
+				issues = append(issues, checkIssue{
+					File:    fileOf(name),
+					Kind:    "missing-template",
+					Message: fmt.Sprintf("standard template %q is not defined", name),
+				})
+			}
+		}
+		// A build skips the series listing pages without series.html, so
+		// PlannedPages does not list them.
+		if len(site.Series) > 0 && !engine.Has("series.html") {
+			reported["series.html"] = true
+			issues = append(issues, checkIssue{
+				File:    fileOf("series.html"),
+				Kind:    "missing-template",
+				Message: fmt.Sprintf("template %q is not defined; the listing pages of %d series are skipped", "series.html", len(site.Series)),
+			})
+		}
+	}
+
+	rendered := map[string]bool{}
+	pages := generator.NewHTMLGenerator(cfg.Build.OutputDir, engine, cfg).PlannedPages(site)
+	for _, p := range pages {
+		if !engine.Has(p.Template) {
+			switch {
+			case !checkMissing:
+			case p.Source != "":
+				issues = append(issues, checkIssue{
+					File:    p.Source,
+					Kind:    "missing-template",
+					Message: fmt.Sprintf("front matter template %q is not defined in %s", p.Template, templateDir),
+				})
+			case !reported[p.Template]:
+				reported[p.Template] = true
+				issues = append(issues, checkIssue{
+					File:    fileOf(p.Template),
+					Kind:    "missing-template",
+					Message: fmt.Sprintf("template %q for %s is not defined", p.Template, outputURL(cfg.Build.OutputDir, p.Path)),
+				})
+			}
+			continue
+		}
+		if !checkErrors || rendered[p.Template] {
+			continue
+		}
+		rendered[p.Template] = true
+		if err := engine.Render(io.Discard, p.Template, p.Data); err != nil {
+			issues = append(issues, checkIssue{
+				File:    fileOf(p.Template),
+				Kind:    "template-error",
+				Message: fmt.Sprintf("dry render of %s: %v", outputURL(cfg.Build.OutputDir, p.Path), err),
+			})
+		}
+	}
+	return issues
+}
+
+// templateFiles maps the base name of every .html file under templateDir,
+// which is the name Engine.Load gives its template, to its path.
+func templateFiles(templateDir string) map[string]string {
+	files := map[string]string{}
+	_ = filepath.WalkDir(templateDir, func(p string, d fs.DirEntry, err error) error {
+		if err == nil && !d.IsDir() && strings.EqualFold(filepath.Ext(p), ".html") {
+			files[filepath.Base(p)] = p
+		}
+		return nil
+	})
+	return files
+}
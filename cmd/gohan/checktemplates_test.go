@@ -0,0 +1,143 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/bmf-san/gohan/internal/config"
+	"github.com/bmf-san/gohan/internal/model"
+)
+
+func loadCheckConfig(t *testing.T, root string) model.Config {
+	t.Helper()
+	cfg, err := config.New(root).Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return *cfg
+}
+
+func TestLintSource_Templates(t *testing.T) {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "config.yaml"), []byte(
+		"site:\n  title: Test\n  base_url: https://example.com\ntheme:\n  dir: theme\n",
+	), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	writeCheckArticle(t, root, "content/posts/a.md", "title: A\ndate: 2024-01-01\ntags: [go]\nseries: Tour", "body")
+	writeCheckArticle(t, root, "content/pages/about.md", "title: About\ndate: 2024-01-02\ntemplate: page.html", "body")
+	templates := map[string]string{
+		"index.html":    `{{range .Articles}}{{.FrontMatter.Title}}{{end}}`,
+		"article.html":  `{{range .Articles}}{{.FrontMatter.Title}}{{end}}`,
+		"tag.html":      `{{.NoSuchField}}`,
+		"category.html": ``,
+	}
+	for name, body := range templates {
+		p := filepath.Join(root, "theme", "templates", name)
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(body), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg := loadCheckConfig(t, root)
+	rules, _ := newCheckRuleSet(cfg.Check)
+	issues, err := lintSource(root, cfg, rules, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, it := range issues {
+		got = append(got, it.Kind+" "+it.File)
+	}
+	sort.Strings(got)
+	want := []string{
+		"missing-template pages/about.md",
+		"missing-template theme/templates/archive.html",
+		"missing-template theme/templates/series.html",
+		"template-error theme/templates/tag.html",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("issues:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	issues, err = lintSource(root, cfg, rules, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, it := range issues {
+		if it.Kind == "missing-template" && strings.HasSuffix(it.File, "about.md") && it.File != "content/pages/about.md" {
+			t.Errorf("project path = %q, want content/pages/about.md", it.File)
+		}
+	}
+}
+
+func TestLintSource_MissingThemeDir(t *testing.T) {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "config.yaml"), []byte("site:\n  title: Test\n  base_url: https://example.com\ntheme:\n  dir: nope\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	writeCheckArticle(t, root, "content/posts/a.md", "title: A\ndate: 2024-01-01", "body")
+
+	cfg := loadCheckConfig(t, root)
+	rules, _ := newCheckRuleSet(cfg.Check)
+	issues, err := lintSource(root, cfg, rules, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(issues) != 1 || issues[0].Kind != "missing-template" || issues[0].File != "nope/templates" {
+		t.Errorf("issues = %+v, want one missing-template for nope/templates", issues)
+	}
+}
+
+func TestLintSource_TemplateAssets(t *testing.T) {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "config.yaml"), []byte(
+		"site:\n  title: Test\n  base_url: https://example.com\ntheme:\n  dir: theme\n"+
+			"assets:\n  fingerprint: true\n  bundles:\n    js/app.js: [js/missing.js]\n",
+	), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	writeCheckArticle(t, root, "content/posts/a.md", "title: A\ndate: 2024-01-01", "body")
+	files := map[string]string{
+		"assets/css/main.css":           "body { margin: 0 }",
+		"theme/templates/index.html":    `{{(asset "css/main.css").URL}}`,
+		"theme/templates/article.html":  `{{(asset "css/missing.css").URL}}`,
+		"theme/templates/tag.html":      ``,
+		"theme/templates/category.html": ``,
+		"theme/templates/archive.html":  ``,
+	}
+	for name, body := range files {
+		p := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(body), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg := loadCheckConfig(t, root)
+	rules, _ := newCheckRuleSet(cfg.Check)
+	issues, err := lintSource(root, cfg, rules, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, it := range issues {
+		got = append(got, it.Kind+" "+it.File)
+	}
+	sort.Strings(got)
+	want := []string{
+		"template-error assets",
+		"template-error theme/templates/article.html",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("issues:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"time"
+
+	"github.com/bmf-san/gohan/internal/processor"
+)
+
+// cliLog routes command output. In text mode (the default) it prints the
+// human-readable lines gohan has always printed; with --log-format=json every
+// message becomes a log/slog JSON record on stdout instead, so CI can parse
+// the output line by line.
+type cliLog struct {
+	json   *slog.Logger // nil in text mode
+	stdout io.Writer
+	stderr io.Writer
+	warns  int // number of Warn calls, reported in summary records
+}
+
+// newCLILog returns a cliLog for format ("text" or "json").
+func newCLILog(format string, stdout, stderr io.Writer) (*cliLog, error) {
+	switch format {
+	case "", "text":
+		return &cliLog{stdout: stdout, stderr: stderr}, nil
+	case "json":
+		return &cliLog{json: slog.New(slog.NewJSONHandler(stdout, nil)), stdout: stdout, stderr: stderr}, nil
+	}
+	return nil, fmt.Errorf("unknown log format %q (want text or json)", format)
+}
+
+// isJSON reports whether records are emitted as JSON.
+func (l *cliLog) isJSON() bool {
+	return l.json != nil
+}
+
+// Info prints text to stdout in text mode, or emits an INFO record with msg
+// and the key-value pairs in args in JSON mode.
+func (l *cliLog) Info(text, msg string, args ...any) {
+	if l.json != nil {
+		l.json.Info(msg, args...)
+		return
+	}
+	_, _ = fmt.Fprintln(l.stdout, text)
+}
+
+// Warn reports a non-fatal problem in the named part of the command. Text
+// mode prints "warn: <scope>: <err>" to stderr. JSON mode emits a WARN record
+// with scope, error, the key-value pairs in args, and — when err identifies
+// an article — its file.
+func (l *cliLog) Warn(scope string, err error, args ...any) {
+	l.warns++
+	if l.json == nil {
+		_, _ = fmt.Fprintf(l.stderr, "warn: %s: %v\n", scope, err)
+		return
+	}
+	attrs := append([]any{"scope", scope, "error", err.Error()}, args...)
+	var te *processor.TaxonomyError
+	if errors.As(err, &te) {
+		attrs = append(attrs, "file", te.FilePath)
+	}
+	l.json.Warn(scope, attrs...)
+}
+
+// Fail emits an ERROR record for the error that aborts the command. Text mode
+// prints nothing: main reports the returned error on stderr.
+func (l *cliLog) Fail(msg string, err error) {
+	if l.json == nil || err == nil {
+		return
+	}
+	l.json.Error(msg, "error", err.Error())
+}
+
+// phase emits a record for a completed build phase. Text mode prints nothing;
+// per-phase timings are shown by --stats instead.
+func (l *cliLog) phase(name string, d time.Duration, err error) {
+	if l.json == nil {
+		return
+	}
+	args := []any{"phase", name, "duration_ms", durationMS(d)}
+	if err != nil {
+		args = append(args, "error", err.Error())
+	}
+	l.json.Info("phase", args...)
+}
+
+// durationMS converts d to fractional milliseconds for JSON records.
+func durationMS(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
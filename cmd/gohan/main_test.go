@@ -2,9 +2,11 @@ package main
 
 import (
 	"bytes"
+	"encoding/json"
 	"io"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -121,6 +123,195 @@ func TestRunBuild_IncrementalAfterFull(t *testing.T) {
 	}
 }
 
+func TestRunBuild_PrunesStaleOutputs(t *testing.T) {
+	tests := []struct {
+		name      string
+		flags     []string
+		wantStale bool
+	}{
+		{"default prunes", nil, false},
+		{"no-prune keeps", []string{"--no-prune"}, true},
+		{"dry-run keeps", []string{"--dry-run"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := copyDir(testdataDir(t), dir); err != nil {
+				t.Fatalf("copyDir: %v", err)
+			}
+			cfgFlag := "--config=" + filepath.Join(dir, "config.yaml")
+			if err := runBuild([]string{cfgFlag, "--output=public"}); err != nil {
+				t.Fatalf("first build: %v", err)
+			}
+
+			// Renaming the slug moves the article to a new output path.
+			post := filepath.Join(dir, "content", "posts", "hello-world.md")
+			data, err := os.ReadFile(post)
+			if err != nil {
+				t.Fatal(err)
+			}
+			data = []byte(strings.Replace(string(data), "slug: hello-world", "slug: renamed", 1))
+			if err := os.WriteFile(post, data, 0o644); err != nil {
+				t.Fatal(err)
+			}
+			if err := runBuild(append([]string{cfgFlag, "--output=public"}, tt.flags...)); err != nil {
+				t.Fatalf("second build: %v", err)
+			}
+
+			_, statErr := os.Stat(filepath.Join(dir, "public", "posts", "hello-world", "index.html"))
+			if gotStale := statErr == nil; gotStale != tt.wantStale {
+				t.Errorf("old page present = %v, want %v", gotStale, tt.wantStale)
+			}
+			if !tt.wantStale {
+				if _, err := os.Stat(filepath.Join(dir, "public", "posts", "hello-world")); !os.IsNotExist(err) {
+					t.Errorf("expected empty directory to be removed, stat err = %v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestRunBuild_Precompress(t *testing.T) {
+	dir := t.TempDir()
+	if err := copyDir(testdataDir(t), dir); err != nil {
+		t.Fatalf("copyDir: %v", err)
+	}
+	cfgPath := filepath.Join(dir, "config.yaml")
+	cfgData, err := os.ReadFile(cfgPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfgData = append(cfgData, "\nbuild:\n  precompress:\n    gzip: true\n    brotli: true\n    min_size: 1\n"...)
+	if err := os.WriteFile(cfgPath, cfgData, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfgFlag := "--config=" + cfgPath
+	if err := runBuild([]string{cfgFlag, "--output=public"}); err != nil {
+		t.Fatalf("first build: %v", err)
+	}
+	page := filepath.Join(dir, "public", "posts", "hello-world", "index.html")
+	for _, p := range []string{page + ".gz", page + ".br", filepath.Join(dir, "public", "sitemap.xml.gz")} {
+		if _, err := os.Stat(p); err != nil {
+			t.Errorf("variant not written: %v", err)
+		}
+	}
+
+	// An unchanged page keeps its variant; a renamed one loses it.
+	marker := []byte("kept")
+	if err := os.WriteFile(page+".gz", marker, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := runBuild([]string{cfgFlag, "--output=public"}); err != nil {
+		t.Fatalf("second build: %v", err)
+	}
+	if data, _ := os.ReadFile(page + ".gz"); string(data) == string(marker) {
+		t.Error("a variant that no longer matches the manifest was kept")
+	}
+	info, err := os.Stat(page + ".br")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := runBuild([]string{cfgFlag, "--output=public"}); err != nil {
+		t.Fatalf("third build: %v", err)
+	}
+	if after, err := os.Stat(page + ".br"); err != nil || !after.ModTime().Equal(info.ModTime()) {
+		t.Errorf("unchanged variant was rewritten: %v", err)
+	}
+
+	post := filepath.Join(dir, "content", "posts", "hello-world.md")
+	data, err := os.ReadFile(post)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data = []byte(strings.Replace(string(data), "slug: hello-world", "slug: renamed", 1))
+	if err := os.WriteFile(post, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := runBuild([]string{cfgFlag, "--output=public"}); err != nil {
+		t.Fatalf("fourth build: %v", err)
+	}
+	if _, err := os.Stat(page + ".br"); !os.IsNotExist(err) {
+		t.Errorf("variant of a removed page not pruned: %v", err)
+	}
+}
+
+func TestRunBuild_LogFormatJSON(t *testing.T) {
+	dir := t.TempDir()
+	if err := copyDir(testdataDir(t), dir); err != nil {
+		t.Fatalf("copyDir: %v", err)
+	}
+	// An unregistered tag produces a taxonomy warning tied to the article.
+	post := filepath.Join(dir, "content", "posts", "hello-world.md")
+	data, err := os.ReadFile(post)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data = []byte(strings.Replace(string(data), "slug: hello-world", "slug: hello-world\ntags: [go]", 1))
+	if err := os.WriteFile(post, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "content", "tags.yaml"), []byte("- name: rust\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	orig := os.Stdout
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.Stdout = w
+	buildErr := runBuild([]string{"--config=" + filepath.Join(dir, "config.yaml"), "--output=public", "--log-format=json", "--stats"})
+	_ = w.Close()
+	os.Stdout = orig
+	if buildErr != nil {
+		t.Fatalf("build: %v", buildErr)
+	}
+	var buf bytes.Buffer
+	_, _ = io.Copy(&buf, r)
+
+	var records []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var rec map[string]any
+		if err := json.Unmarshal([]byte(line), &rec); err != nil {
+			t.Fatalf("line is not JSON: %q: %v", line, err)
+		}
+		records = append(records, rec)
+	}
+
+	var phases int
+	var taxonomyWarn map[string]any
+	for _, rec := range records {
+		switch {
+		case rec["msg"] == "phase":
+			phases++
+		case rec["level"] == "WARN" && rec["scope"] == "taxonomy":
+			taxonomyWarn = rec
+		}
+	}
+	if phases == 0 {
+		t.Error("expected phase records")
+	}
+	if taxonomyWarn == nil {
+		t.Fatalf("expected a taxonomy warning, got %v", records)
+	}
+	if file, _ := taxonomyWarn["file"].(string); file != post {
+		t.Errorf("taxonomy warning file = %q, want %q", file, post)
+	}
+	last := records[len(records)-1]
+	if last["msg"] != "build complete" || last["articles"] != float64(1) || last["warnings"] != float64(1) {
+		t.Errorf("unexpected summary record: %v", last)
+	}
+	if _, ok := last["stats"].(map[string]any); !ok {
+		t.Errorf("expected stats group in summary: %v", last)
+	}
+}
+
+func TestRunBuild_UnknownLogFormat(t *testing.T) {
+	if err := runBuild([]string{"--log-format=xml"}); err == nil {
+		t.Error("expected error for unknown log format")
+	}
+}
+
 // copyDir recursively copies src directory to dst.
 func copyDir(src, dst string) error {
 	return filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
@@ -3,6 +3,7 @@ package main
 import (
 	"flag"
 	"fmt"
+	"os"
 	"path/filepath"
 
 	"github.com/bmf-san/gohan/internal/config"
@@ -14,18 +15,31 @@ func runServe(args []string) error {
 	port := fs.Int("port", 1313, "port to listen on")
 	host := fs.String("host", "127.0.0.1", "host/address to bind")
 	configPath := fs.String("config", "config.yaml", "path to config file")
+	logFmt := fs.String("log-format", "text", "log format: text or json (also used for rebuilds)")
+	precompressed := fs.Bool("precompressed", false, "serve .br/.gz variants written by build.precompress to clients that accept them")
 
 	if err := fs.Parse(args); err != nil {
 		return err
 	}
+	log, err := newCLILog(*logFmt, os.Stdout, os.Stderr)
+	if err != nil {
+		return err
+	}
 
-	// Run an initial full build before starting the server
-	fmt.Println("serve: running initial build...")
-	if err := runBuild([]string{"--full", "--config", *configPath}); err != nil {
-		// Non-fatal: warn but continue so the user can fix content while the server is running
-		fmt.Printf("serve: initial build warning: %v\n", err)
+	// buildFailed reports a failed build once per format: in JSON mode
+	// runBuild has already emitted its "build failed" record, while in text
+	// mode it prints nothing and the error is only returned.
+	buildFailed := func(text string, err error) {
+		if err != nil && !log.isJSON() {
+			_, _ = fmt.Fprintf(log.stderr, "%s: %v\n", text, err)
+		}
 	}
 
+	// Run an initial full build before starting the server. A failure is not
+	// fatal, so the user can fix content while the server is running.
+	log.Info("serve: running initial build...", "initial build")
+	buildFailed("serve: initial build warning", runBuild([]string{"--full", "--config", *configPath, "--log-format", *logFmt}))
+
 	// Determine project root and output directory from config.
 	cfgAbs, err := filepath.Abs(*configPath)
 	if err != nil {
@@ -39,13 +53,18 @@ func runServe(args []string) error {
 		outDir = filepath.Join(rootDir, cfg.Build.OutputDir)
 	}
 
-	// rebuildFn triggers a differential build on file change
+	// rebuildFn triggers a differential build on file change.
 	rebuildFn := func() error {
-		return runBuild([]string{"--config", *configPath})
+		err := runBuild([]string{"--config", *configPath, "--log-format", *logFmt})
+		buildFailed("serve: rebuild failed", err)
+		return err
 	}
 
 	srv := server.NewDevServer(*host, *port, outDir, rebuildFn)
 	srv.RootDir = rootDir // resolve watch dirs relative to project root (M-6)
-	fmt.Printf("serve: listening on http://%s:%d\n", *host, *port)
+	srv.Logger = log.json // nil in text mode
+	srv.Precompressed = *precompressed
+	addr := fmt.Sprintf("http://%s:%d", *host, *port)
+	log.Info("serve: listening on "+addr, "listening", "url", addr)
 	return srv.Start()
 }
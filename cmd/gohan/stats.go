@@ -3,10 +3,13 @@ package main
 import (
 	"fmt"
 	"io"
+	"log/slog"
+	"path/filepath"
 	"sort"
 	"time"
 
 	"github.com/bmf-san/gohan/internal/model"
+	"github.com/bmf-san/gohan/internal/processor"
 )
 
 // phaseTimer accumulates wall-clock durations for named build phases. It is
@@ -14,6 +17,7 @@ import (
 type phaseTimer struct {
 	order    []string
 	duration map[string]time.Duration
+	log      *cliLog // when set, receives an event per completed phase
 }
 
 func newPhaseTimer() *phaseTimer {
@@ -28,7 +32,11 @@ func (p *phaseTimer) Phase(name string, fn func() error) error {
 	}
 	t0 := time.Now()
 	err := fn()
-	p.duration[name] += time.Since(t0)
+	d := time.Since(t0)
+	p.duration[name] += d
+	if p.log != nil {
+		p.log.phase(name, d, err)
+	}
 	return err
 }
 
@@ -42,6 +50,22 @@ func (p *phaseTimer) writeStats(w io.Writer, total time.Duration) {
 	_, _ = fmt.Fprintf(w, "  %-12s %v\n", "total:", total.Round(time.Millisecond))
 }
 
+// statsAttr returns the phase timings as a "stats" group for JSON records,
+// in milliseconds. cache may be nil (e.g. in a dry run).
+func (p *phaseTimer) statsAttr(total time.Duration, cache *processor.RenderCache) slog.Attr {
+	attrs := make([]any, 0, len(p.order)+3)
+	phases := make([]any, 0, len(p.order))
+	for _, name := range p.order {
+		phases = append(phases, slog.Float64(name, durationMS(p.duration[name])))
+	}
+	attrs = append(attrs, slog.Group("phases_ms", phases...), slog.Float64("total_ms", durationMS(total)))
+	if cache != nil {
+		hits, misses := cache.Stats()
+		attrs = append(attrs, slog.Group("cache", "hits", hits, "misses", misses))
+	}
+	return slog.Group("stats", attrs...)
+}
+
 // writeExplain reports the reason for the current build's scope of work to w.
 // `forceFull` indicates that a full rebuild was forced (CLI flag, missing
 // manifest, or config hash change). `changeSet` lists incremental changes
@@ -68,6 +92,31 @@ func writeExplain(w io.Writer, forceFull bool, fullReason string, changeSet *mod
 	printList(w, "deleted", changeSet.DeletedFiles)
 }
 
+// explainAttr returns the information printed by writeExplain as an
+// "explain" group for JSON records.
+func explainAttr(forceFull bool, fullReason string, changeSet *model.ChangeSet) slog.Attr {
+	if forceFull {
+		if fullReason == "" {
+			fullReason = "full build forced"
+		}
+		return slog.Group("explain", "full", true, "reason", fullReason)
+	}
+	attrs := []any{slog.Bool("full", false)}
+	if changeSet != nil {
+		attrs = append(attrs,
+			slog.Any("added", sortedCopy(changeSet.AddedFiles)),
+			slog.Any("modified", sortedCopy(changeSet.ModifiedFiles)),
+			slog.Any("deleted", sortedCopy(changeSet.DeletedFiles)))
+	}
+	return slog.Group("explain", attrs...)
+}
+
+func sortedCopy(files []string) []string {
+	sorted := append([]string{}, files...)
+	sort.Strings(sorted)
+	return sorted
+}
+
 func printList(w io.Writer, label string, files []string) {
 	if len(files) == 0 {
 		return
@@ -79,3 +128,28 @@ func printList(w io.Writer, label string, files []string) {
 		_, _ = fmt.Fprintf(w, "    %s\n", f)
 	}
 }
+
+// writePruneList prints stale output files relative to outDir under a header
+// such as "pruned (2):". Nothing is printed for an empty list.
+func writePruneList(w io.Writer, label, outDir string, paths []string) {
+	if len(paths) == 0 {
+		return
+	}
+	_, _ = fmt.Fprintf(w, "%s (%d):\n", label, len(paths))
+	for _, p := range relOutputs(outDir, paths) {
+		_, _ = fmt.Fprintf(w, "  %s\n", p)
+	}
+}
+
+// relOutputs returns paths relative to outDir with forward slashes. Paths
+// outside outDir are returned unchanged.
+func relOutputs(outDir string, paths []string) []string {
+	rels := make([]string, len(paths))
+	for i, p := range paths {
+		if rel, err := filepath.Rel(outDir, p); err == nil {
+			p = filepath.ToSlash(rel)
+		}
+		rels[i] = p
+	}
+	return rels
+}
@@ -5,7 +5,9 @@ go 1.26.4
 require gopkg.in/yaml.v3 v3.0.1
 
 require (
+	github.com/BurntSushi/toml v1.6.0
 	github.com/alecthomas/chroma/v2 v2.27.0
+	github.com/andybalholm/brotli v1.2.6
 	github.com/fsnotify/fsnotify v1.10.1
 	github.com/yuin/goldmark v1.8.2
 	golang.org/x/image v0.43.0
@@ -0,0 +1,2 @@
+// Package assets minifies, bundles, and fingerprints the CSS and JS files of the assets directory and resolves asset URLs for templates.
+package assets
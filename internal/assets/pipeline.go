@@ -0,0 +1,240 @@
+package assets
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/bmf-san/gohan/internal/minify"
+	"github.com/bmf-san/gohan/internal/model"
+)
+
+// URLPrefix is the URL path the assets directory is published under.
+const URLPrefix = "/assets/"
+
+// Asset is a published asset as returned by the asset template function.
+type Asset struct {
+	// URL is the site-root URL path of the asset, e.g.
+	// "/assets/css/main.3f9a1c2b.css" when fingerprinting is enabled.
+	URL string
+	// Integrity is the subresource integrity hash of the published file,
+	// e.g. "sha384-...", for the integrity attribute of <link> and <script>.
+	Integrity string
+}
+
+// output is a CSS or JS file produced by the pipeline.
+type output struct {
+	name        string // slash-separated path relative to the assets directory
+	fingerprint string // fingerprinted name; "" when fingerprinting is disabled
+	data        []byte
+	integrity   string
+	// rewrite is set when data differs from the source file of the same name,
+	// which the verbatim copy of the assets directory would otherwise publish.
+	rewrite bool
+}
+
+// Pipeline holds the processed CSS and JS files of an assets directory.
+// Other files are published unchanged; their integrity hash is computed on
+// first use. Pipeline is safe for concurrent use.
+type Pipeline struct {
+	dir     string
+	outputs map[string]*output // keyed by name
+
+	mu    sync.Mutex
+	other map[string]Asset // verbatim files resolved so far, keyed by name
+}
+
+// New processes the CSS and JS files in dir as configured by cfg: each file
+// and each bundle is minified when cfg.Minify is set and given a
+// content-hashed name when cfg.Fingerprint is set. A missing dir yields an
+// empty Pipeline; a missing bundle source is an error.
+func New(dir string, cfg model.AssetsConfig) (*Pipeline, error) {
+	p := &Pipeline{dir: dir, outputs: make(map[string]*output), other: make(map[string]Asset)}
+	err := filepath.WalkDir(dir, func(file string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !isPipelineFile(file) {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, file)
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		p.add(filepath.ToSlash(rel), data, false, cfg)
+		return nil
+	})
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("assets: %w", err)
+	}
+
+	names := make([]string, 0, len(cfg.Bundles))
+	for name := range cfg.Bundles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		// Bundles are written below the output assets directory and read
+		// from the assets directory; neither may escape it.
+		if !filepath.IsLocal(filepath.FromSlash(name)) {
+			return nil, fmt.Errorf("assets: bundle %s: name is not a path inside the assets directory", name)
+		}
+		sep := "\n"
+		if strings.ToLower(path.Ext(name)) == ".js" {
+			// A file ending without a semicolon must not run into the next.
+			sep = "\n;\n"
+		}
+		var parts []string
+		for _, src := range cfg.Bundles[name] {
+			if !filepath.IsLocal(filepath.FromSlash(src)) {
+				return nil, fmt.Errorf("assets: bundle %s: source %q is not a path inside the assets directory", name, src)
+			}
+			data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(src)))
+			if err != nil {
+				return nil, fmt.Errorf("assets: bundle %s: %w", name, err)
+			}
+			parts = append(parts, string(data))
+		}
+		p.add(path.Clean(name), []byte(strings.Join(parts, sep)), true, cfg)
+	}
+	return p, nil
+}
+
+// add records the output name with the source content data.
+func (p *Pipeline) add(name string, data []byte, bundle bool, cfg model.AssetsConfig) {
+	o := &output{name: name, data: data, rewrite: bundle}
+	if cfg.Minify {
+		if strings.ToLower(path.Ext(name)) == ".css" {
+			o.data = minify.CSS(data)
+		} else {
+			o.data = minify.JS(data)
+		}
+		o.rewrite = true
+	}
+	if cfg.Fingerprint {
+		sum := sha256.Sum256(o.data)
+		ext := path.Ext(name)
+		o.fingerprint = strings.TrimSuffix(name, ext) + "." + hex.EncodeToString(sum[:4]) + ext
+	}
+	o.integrity = integrity(o.data)
+	p.outputs[name] = o
+}
+
+// Asset resolves name, a path relative to the assets directory such as
+// "css/main.css", to its published URL and integrity hash. It is registered
+// as the asset template function.
+func (p *Pipeline) Asset(name string) (Asset, error) {
+	name = path.Clean(strings.TrimPrefix(name, "/"))
+	if o, ok := p.outputs[name]; ok {
+		u := o.name
+		if o.fingerprint != "" {
+			u = o.fingerprint
+		}
+		return Asset{URL: URLPrefix + u, Integrity: o.integrity}, nil
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if a, ok := p.other[name]; ok {
+		return a, nil
+	}
+	if strings.HasPrefix(name, "../") || name == ".." {
+		return Asset{}, fmt.Errorf("asset %q not found", name)
+	}
+	data, err := os.ReadFile(filepath.Join(p.dir, filepath.FromSlash(name)))
+	if err != nil {
+		return Asset{}, fmt.Errorf("asset %q not found in %s", name, p.dir)
+	}
+	a := Asset{URL: URLPrefix + name, Integrity: integrity(data)}
+	p.other[name] = a
+	return a, nil
+}
+
+// FuncMap returns the template functions backed by p, for
+// template.Engine.Load.
+func (p *Pipeline) FuncMap() template.FuncMap {
+	return template.FuncMap{"asset": p.Asset}
+}
+
+// Write writes the outputs that differ from a verbatim copy of the assets
+// directory into assetsOutDir (typically public/assets): minified files,
+// bundles, and fingerprinted copies. Call it after copying the directory.
+func (p *Pipeline) Write(assetsOutDir string) error {
+	for _, o := range p.sortedOutputs() {
+		for _, name := range o.writtenNames() {
+			dst := filepath.Join(assetsOutDir, filepath.FromSlash(name))
+			if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+				return err
+			}
+			if err := os.WriteFile(dst, o.data, 0o644); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Outputs returns the paths under assetsOutDir that Write writes and the
+// verbatim copy does not: bundles and fingerprinted copies.
+func (p *Pipeline) Outputs(assetsOutDir string) []string {
+	var out []string
+	for _, o := range p.sortedOutputs() {
+		for _, name := range o.writtenNames() {
+			if _, err := os.Stat(filepath.Join(p.dir, filepath.FromSlash(name))); err == nil {
+				continue // also published by the verbatim copy
+			}
+			out = append(out, filepath.Join(assetsOutDir, filepath.FromSlash(name)))
+		}
+	}
+	return out
+}
+
+func (p *Pipeline) sortedOutputs() []*output {
+	out := make([]*output, 0, len(p.outputs))
+	for _, o := range p.outputs {
+		out = append(out, o)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].name < out[j].name })
+	return out
+}
+
+// writtenNames returns the names Write publishes o under.
+func (o *output) writtenNames() []string {
+	var names []string
+	if o.rewrite {
+		names = append(names, o.name)
+	}
+	if o.fingerprint != "" {
+		names = append(names, o.fingerprint)
+	}
+	return names
+}
+
+// isPipelineFile reports whether file is a CSS or JS file.
+func isPipelineFile(file string) bool {
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".css", ".js":
+		return true
+	}
+	return false
+}
+
+// integrity returns the SHA-384 subresource integrity hash of data.
+func integrity(data []byte) string {
+	sum := sha512.Sum384(data)
+	return "sha384-" + base64.StdEncoding.EncodeToString(sum[:])
+}
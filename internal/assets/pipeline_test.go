@@ -0,0 +1,153 @@
+package assets
+
+import (
+	"bytes"
+	"html/template"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/bmf-san/gohan/internal/model"
+)
+
+func writeAssets(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestPipeline_MinifyFingerprintBundle(t *testing.T) {
+	dir := writeAssets(t, map[string]string{
+		"css/main.css":  "body {\n  margin: 0;\n}\n",
+		"js/a.js":       "var a = 1 // one\n",
+		"js/b.js":       "var b = 2\n",
+		"images/x.png":  "png",
+		"css/print.css": "a { color: black }",
+	})
+	p, err := New(dir, model.AssetsConfig{
+		Minify:      true,
+		Fingerprint: true,
+		Bundles:     map[string][]string{"js/app.js": {"js/a.js", "js/b.js"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	css, err := p.Asset("css/main.css")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !regexp.MustCompile(`^/assets/css/main\.[0-9a-f]{8}\.css$`).MatchString(css.URL) {
+		t.Errorf("css URL: got %q, want a fingerprinted name", css.URL)
+	}
+	if want := integrity([]byte("body{margin:0}")); css.Integrity != want {
+		t.Errorf("css Integrity: got %q, want %q", css.Integrity, want)
+	}
+	img, err := p.Asset("/images/x.png")
+	if err != nil || img.URL != "/assets/images/x.png" || img.Integrity != integrity([]byte("png")) {
+		t.Errorf("image: got %+v, %v", img, err)
+	}
+	if _, err := p.Asset("missing.css"); err == nil {
+		t.Error("expected error for a missing asset")
+	}
+
+	out := t.TempDir()
+	if err := p.Write(out); err != nil {
+		t.Fatal(err)
+	}
+	app, err := p.Asset("js/app.js")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"js/app.js", strings.TrimPrefix(app.URL, URLPrefix)} {
+		data, err := os.ReadFile(filepath.Join(out, filepath.FromSlash(name)))
+		if err != nil || string(data) != "var a=1;var b=2" {
+			t.Errorf("%s: got %q, %v", name, data, err)
+		}
+	}
+	if data, _ := os.ReadFile(filepath.Join(out, "css", "main.css")); string(data) != "body{margin:0}" {
+		t.Errorf("css/main.css not minified in place: %q", data)
+	}
+
+	// Outputs lists what the verbatim copy of dir does not publish.
+	outputs := p.Outputs(out)
+	if len(outputs) != 6 { // app.js and five fingerprinted files
+		t.Errorf("Outputs: got %v", outputs)
+	}
+	for _, o := range outputs {
+		if o == filepath.Join(out, "css", "main.css") {
+			t.Errorf("Outputs lists %s, which the verbatim copy publishes", o)
+		}
+	}
+}
+
+func TestPipeline_Disabled(t *testing.T) {
+	dir := writeAssets(t, map[string]string{"css/main.css": "body { margin: 0 }"})
+	p, err := New(dir, model.AssetsConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, err := p.Asset("css/main.css")
+	if err != nil || a.URL != "/assets/css/main.css" || a.Integrity != integrity([]byte("body { margin: 0 }")) {
+		t.Errorf("Asset: got %+v, %v", a, err)
+	}
+	out := t.TempDir()
+	if err := p.Write(out); err != nil {
+		t.Fatal(err)
+	}
+	if entries, _ := os.ReadDir(out); len(entries) != 0 || len(p.Outputs(out)) != 0 {
+		t.Error("nothing should be written when the pipeline does not change any file")
+	}
+}
+
+func TestPipeline_BundleOutsideAssetsDir(t *testing.T) {
+	dir := writeAssets(t, map[string]string{"js/a.js": "a()"})
+	for name, srcs := range map[string][]string{
+		"../app.js":   {"js/a.js"},
+		"/tmp/app.js": {"js/a.js"},
+		"js/app.js":   {"../outside.js"},
+	} {
+		if _, err := New(dir, model.AssetsConfig{Bundles: map[string][]string{name: srcs}}); err == nil || !strings.Contains(err.Error(), "inside the assets directory") {
+			t.Errorf("%s: %v: expected an error, got %v", name, srcs, err)
+		}
+	}
+}
+
+func TestPipeline_MissingBundleSource(t *testing.T) {
+	dir := writeAssets(t, nil)
+	if _, err := New(dir, model.AssetsConfig{Bundles: map[string][]string{"js/app.js": {"js/missing.js"}}}); err == nil {
+		t.Error("expected error for a missing bundle source")
+	}
+	if _, err := New(filepath.Join(dir, "nope"), model.AssetsConfig{}); err != nil {
+		t.Errorf("missing assets directory: %v", err)
+	}
+}
+
+func TestPipeline_FuncMap(t *testing.T) {
+	dir := writeAssets(t, map[string]string{"js/app.js": "run()"})
+	p, err := New(dir, model.AssetsConfig{Fingerprint: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := template.Must(template.New("").Funcs(p.FuncMap()).Parse(
+		`{{with asset "js/app.js"}}<script src="{{.URL}}" integrity="{{.Integrity}}"></script>{{end}}`))
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, nil); err != nil {
+		t.Fatal(err)
+	}
+	a, _ := p.Asset("js/app.js")
+	if want := `<script src="` + a.URL + `" integrity="` + a.Integrity + `"></script>`; buf.String() != want {
+		t.Errorf("got %s, want %s", buf.String(), want)
+	}
+}
@@ -5,7 +5,10 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
+	"regexp"
+	"strings"
 
 	"gopkg.in/yaml.v3"
 
@@ -22,8 +25,22 @@ const (
 	defaultLanguage       = "en"
 	defaultHighlightTheme = "github"
 	defaultGitHubBranch   = "main"
+	defaultImageSizes     = "100vw"
+	defaultImageQuality   = 80
+	defaultPrecompressMin = 1024
+	defaultTermTemplate   = "term.html"
+	defaultTaxonomyIndex  = "taxonomy.html"
 )
 
+// taxonomyNamePattern matches the names of user-defined taxonomies, which are
+// used as URL path segments and registry file names.
+var taxonomyNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// reservedTaxonomyNames are the top-level output directories of other pages.
+var reservedTaxonomyNames = map[string]bool{
+	"posts": true, "archives": true, "page": true, "series": true, "assets": true,
+}
+
 // Loader reads and validates the gohan project configuration.
 type Loader struct {
 	rootDir string
@@ -90,6 +107,26 @@ func applyDefaults(cfg *model.Config) {
 	if cfg.Site.GitHubBranch == "" {
 		cfg.Site.GitHubBranch = defaultGitHubBranch
 	}
+	if len(cfg.Images.Widths) == 0 {
+		cfg.Images.Widths = []int{480, 960, 1440}
+	}
+	if cfg.Images.Sizes == "" {
+		cfg.Images.Sizes = defaultImageSizes
+	}
+	if cfg.Images.Quality == 0 {
+		cfg.Images.Quality = defaultImageQuality
+	}
+	if cfg.Build.Precompress.MinSize == 0 {
+		cfg.Build.Precompress.MinSize = defaultPrecompressMin
+	}
+	for i := range cfg.Taxonomies {
+		if cfg.Taxonomies[i].Template == "" {
+			cfg.Taxonomies[i].Template = defaultTermTemplate
+		}
+		if cfg.Taxonomies[i].IndexTemplate == "" {
+			cfg.Taxonomies[i].IndexTemplate = defaultTaxonomyIndex
+		}
+	}
 	// i18n: when locales are configured, default_locale falls back to site.language.
 	if len(cfg.I18n.Locales) > 0 && cfg.I18n.DefaultLocale == "" {
 		cfg.I18n.DefaultLocale = cfg.Site.Language
@@ -104,5 +141,121 @@ func validate(cfg *model.Config) error {
 	if cfg.Site.BaseURL == "" {
 		return errors.New("config: site.base_url is required")
 	}
+	for rule, sev := range cfg.Check.Severity {
+		switch sev {
+		case "error", "warning", "info":
+		default:
+			return fmt.Errorf("config: check.severity.%s: unknown severity %q (want error, warning, or info)", rule, sev)
+		}
+	}
+	if cfg.Related.Count < 0 {
+		return fmt.Errorf("config: related.count: must not be negative, got %d", cfg.Related.Count)
+	}
+	if cfg.Related.RecencyHalfLife < 0 {
+		return fmt.Errorf("config: related.recency_half_life: must not be negative, got %d", cfg.Related.RecencyHalfLife)
+	}
+	for signal, w := range cfg.Related.Weights {
+		if _, ok := model.RelatedSignals[signal]; !ok {
+			return fmt.Errorf("config: related.weights.%s: unknown signal (want tags, categories, content, or recency)", signal)
+		}
+		if w < 0 {
+			return fmt.Errorf("config: related.weights.%s: must not be negative, got %g", signal, w)
+		}
+	}
+	if err := validateTaxonomies(cfg); err != nil {
+		return err
+	}
+	if cfg.Build.Precompress.MinSize < 0 {
+		return fmt.Errorf("config: build.precompress.min_size: must not be negative, got %d", cfg.Build.Precompress.MinSize)
+	}
+	for _, w := range cfg.Images.Widths {
+		if w <= 0 {
+			return fmt.Errorf("config: images.widths: width must be positive, got %d", w)
+		}
+	}
+	if cfg.Images.Quality < 1 || cfg.Images.Quality > 100 {
+		return fmt.Errorf("config: images.quality: must be between 1 and 100, got %d", cfg.Images.Quality)
+	}
+	for out, srcs := range cfg.Assets.Bundles {
+		ext := strings.ToLower(path.Ext(out))
+		if ext != ".css" && ext != ".js" {
+			return fmt.Errorf("config: assets.bundles.%s: bundle must be a .css or .js file", out)
+		}
+		if !filepath.IsLocal(filepath.FromSlash(out)) {
+			return fmt.Errorf("config: assets.bundles.%s: bundle must be a path inside the assets directory", out)
+		}
+		if len(srcs) == 0 {
+			return fmt.Errorf("config: assets.bundles.%s: no source files", out)
+		}
+		for _, src := range srcs {
+			if !filepath.IsLocal(filepath.FromSlash(src)) {
+				return fmt.Errorf("config: assets.bundles.%s: source %q is not a path inside the assets directory", out, src)
+			}
+			if strings.ToLower(path.Ext(src)) != ext {
+				return fmt.Errorf("config: assets.bundles.%s: source %q is not a %s file", out, src, ext)
+			}
+		}
+	}
+	for section, s := range cfg.ContentSchema {
+		for field, fs := range s.Fields {
+			if err := validateFieldSchema(fs, field); err != nil {
+				return fmt.Errorf("config: content_schema.%s.fields.%s: %w", section, field, err)
+			}
+		}
+	}
+	return nil
+}
+
+// validateTaxonomies checks the names of the user-defined taxonomies: each
+// must be a unique URL path segment that no built-in front matter field, other
+// page type, or locale directory uses.
+func validateTaxonomies(cfg *model.Config) error {
+	seen := make(map[string]bool, len(cfg.Taxonomies))
+	for i, tc := range cfg.Taxonomies {
+		switch {
+		case tc.Name == "":
+			return fmt.Errorf("config: taxonomies[%d].name is required", i)
+		case !taxonomyNamePattern.MatchString(tc.Name):
+			return fmt.Errorf("config: taxonomies[%d].name: %q must be lowercase letters, digits, '-', or '_'", i, tc.Name)
+		case seen[tc.Name]:
+			return fmt.Errorf("config: taxonomies[%d].name: %q is declared twice", i, tc.Name)
+		case model.BuiltinFieldTypes[tc.Name] != "":
+			return fmt.Errorf("config: taxonomies[%d].name: %q is a built-in front matter field", i, tc.Name)
+		case reservedTaxonomyNames[tc.Name]:
+			return fmt.Errorf("config: taxonomies[%d].name: %q is reserved for other pages", i, tc.Name)
+		}
+		for _, loc := range cfg.I18n.Locales {
+			if tc.Name == loc {
+				return fmt.Errorf("config: taxonomies[%d].name: %q is a locale", i, tc.Name)
+			}
+		}
+		seen[tc.Name] = true
+	}
+	return nil
+}
+
+// validateFieldSchema checks the constraints declared for one front matter
+// field.
+func validateFieldSchema(fs model.FieldSchema, field string) error {
+	switch fs.Type {
+	case "", "string", "int", "float", "bool", "date", "list", "map":
+	default:
+		return fmt.Errorf("unknown type %q (want string, int, float, bool, date, list, or map)", fs.Type)
+	}
+	typ := fs.Type
+	if builtin, ok := model.BuiltinFieldTypes[field]; ok {
+		if typ != "" && typ != builtin {
+			return fmt.Errorf("type %q does not match the built-in field type %q", typ, builtin)
+		}
+		typ = builtin
+	}
+	if (typ == "date" || typ == "map") && (len(fs.Enum) > 0 || fs.Pattern != "") {
+		return fmt.Errorf("enum and pattern do not apply to type %q", typ)
+	}
+	if fs.Pattern != "" {
+		if _, err := regexp.Compile(fs.Pattern); err != nil {
+			return fmt.Errorf("pattern: %w", err)
+		}
+	}
 	return nil
 }
@@ -3,9 +3,11 @@ package config_test
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/bmf-san/gohan/internal/config"
+	"github.com/bmf-san/gohan/internal/model"
 )
 
 // writeConfig writes content to config.yaml inside dir and returns dir.
@@ -90,6 +92,12 @@ site:
 	if cfg.Site.GitHubBranch != "main" {
 		t.Errorf("site.github_branch default: got %q, want \"main\"", cfg.Site.GitHubBranch)
 	}
+	if len(cfg.Images.Widths) != 3 || cfg.Images.Sizes != "100vw" || cfg.Images.Quality != 80 {
+		t.Errorf("images defaults: got %+v", cfg.Images)
+	}
+	if cfg.Build.Precompress.MinSize != 1024 {
+		t.Errorf("precompress.min_size default: got %d, want 1024", cfg.Build.Precompress.MinSize)
+	}
 }
 
 func TestLoad_MissingTitle(t *testing.T) {
@@ -118,6 +126,23 @@ site:
 	}
 }
 
+func TestLoad_InvalidCheckSeverity(t *testing.T) {
+	dir := t.TempDir()
+	writeConfig(t, dir, `
+site:
+  title: "My Blog"
+  base_url: "https://example.com"
+check:
+  severity:
+    missing-date: fatal
+`)
+
+	_, err := config.New(dir).Load()
+	if err == nil {
+		t.Fatal("expected error for unknown check severity, got nil")
+	}
+}
+
 func TestLoad_FileNotFound(t *testing.T) {
 	dir := t.TempDir() // no config.yaml written
 
@@ -265,3 +290,135 @@ theme:
 		t.Fatalf("params.nav.primary length: got %d, want 2", len(primary))
 	}
 }
+
+func TestLoad_ContentSchema(t *testing.T) {
+	dir := t.TempDir()
+	writeConfig(t, dir, `
+site:
+  title: Test
+  base_url: https://example.com
+content_schema:
+  posts:
+    fields:
+      description:
+        required: true
+      difficulty:
+        type: string
+        enum: [beginner, advanced]
+`)
+	cfg, err := config.New(dir).Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	f := cfg.ContentSchema["posts"].Fields["difficulty"]
+	if f.Type != "string" || len(f.Enum) != 2 || !cfg.ContentSchema["posts"].Fields["description"].Required {
+		t.Errorf("ContentSchema = %+v", cfg.ContentSchema)
+	}
+}
+
+func TestLoad_ContentSchemaInvalid(t *testing.T) {
+	for _, field := range []string{
+		"rating: {type: number}",
+		"title: {type: int}",
+		"date: {enum: [a]}",
+		"slug: {pattern: '['}",
+	} {
+		dir := t.TempDir()
+		writeConfig(t, dir, "site:\n  title: Test\n  base_url: https://example.com\ncontent_schema:\n  posts:\n    fields:\n      "+field+"\n")
+		if _, err := config.New(dir).Load(); err == nil || !strings.Contains(err.Error(), "content_schema.posts.fields.") {
+			t.Errorf("%s: expected content_schema error, got %v", field, err)
+		}
+	}
+}
+
+func TestLoad_ImagesInvalid(t *testing.T) {
+	for _, images := range []string{
+		"widths: [480, 0]",
+		"quality: 101",
+	} {
+		dir := t.TempDir()
+		writeConfig(t, dir, "site:\n  title: Test\n  base_url: https://example.com\nimages:\n  "+images+"\n")
+		if _, err := config.New(dir).Load(); err == nil || !strings.Contains(err.Error(), "images.") {
+			t.Errorf("%s: expected images error, got %v", images, err)
+		}
+	}
+}
+
+func TestLoad_PrecompressMinSizeInvalid(t *testing.T) {
+	dir := t.TempDir()
+	writeConfig(t, dir, "site:\n  title: Test\n  base_url: https://example.com\nbuild:\n  precompress:\n    min_size: -1\n")
+	if _, err := config.New(dir).Load(); err == nil || !strings.Contains(err.Error(), "precompress.min_size") {
+		t.Errorf("expected precompress.min_size error, got %v", err)
+	}
+}
+
+func TestLoad_RelatedInvalid(t *testing.T) {
+	for _, related := range []string{
+		"count: -1",
+		"recency_half_life: -30",
+		"weights:\n    tags: -1",
+		"weights:\n    views: 1",
+	} {
+		dir := t.TempDir()
+		writeConfig(t, dir, "site:\n  title: Test\n  base_url: https://example.com\nrelated:\n  "+related+"\n")
+		if _, err := config.New(dir).Load(); err == nil || !strings.Contains(err.Error(), "related.") {
+			t.Errorf("%s: expected related error, got %v", related, err)
+		}
+	}
+}
+
+func TestLoad_Taxonomies(t *testing.T) {
+	dir := t.TempDir()
+	writeConfig(t, dir, "site:\n  title: Test\n  base_url: https://example.com\ntaxonomies:\n  - name: authors\n    feed: true\n  - name: difficulty\n    template: level.html\n")
+	cfg, err := config.New(dir).Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	want := []model.TaxonomyConfig{
+		{Name: "authors", Template: "term.html", IndexTemplate: "taxonomy.html", Feed: true},
+		{Name: "difficulty", Template: "level.html", IndexTemplate: "taxonomy.html"},
+	}
+	if len(cfg.Taxonomies) != len(want) {
+		t.Fatalf("Taxonomies = %+v, want %+v", cfg.Taxonomies, want)
+	}
+	for i := range want {
+		if cfg.Taxonomies[i] != want[i] {
+			t.Errorf("Taxonomies[%d] = %+v, want %+v", i, cfg.Taxonomies[i], want[i])
+		}
+	}
+}
+
+func TestLoad_TaxonomiesInvalid(t *testing.T) {
+	for _, taxonomies := range []string{
+		"- feed: true",
+		"- name: Authors",
+		"- name: my/authors",
+		"- name: authors\n  - name: authors",
+		"- name: tags",
+		"- name: author",
+		"- name: archives",
+		"- name: ja",
+	} {
+		dir := t.TempDir()
+		writeConfig(t, dir, "site:\n  title: Test\n  base_url: https://example.com\ni18n:\n  locales: [en, ja]\ntaxonomies:\n  "+taxonomies+"\n")
+		if _, err := config.New(dir).Load(); err == nil || !strings.Contains(err.Error(), "taxonomies[") {
+			t.Errorf("%s: expected taxonomies error, got %v", taxonomies, err)
+		}
+	}
+}
+
+func TestLoad_AssetBundlesInvalid(t *testing.T) {
+	for _, bundles := range []string{
+		"css/main.scss: [a.scss]",
+		"css/main.css: []",
+		"js/app.js: [a.js, b.css]",
+		"../escape.css: [a.css]",
+		"css/main.css: [../../secret.css]",
+	} {
+		dir := t.TempDir()
+		writeConfig(t, dir, "site:\n  title: Test\n  base_url: https://example.com\nassets:\n  bundles:\n    "+bundles+"\n")
+		if _, err := config.New(dir).Load(); err == nil || !strings.Contains(err.Error(), "assets.bundles.") {
+			t.Errorf("%s: expected assets.bundles error, got %v", bundles, err)
+		}
+	}
+}
@@ -4,8 +4,11 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"mime"
 	"os"
 	"path/filepath"
+	"slices"
+	"sort"
 	"time"
 
 	"github.com/bmf-san/gohan/internal/model"
@@ -14,13 +17,19 @@ import (
 const (
 	cacheManifestFile = "manifest.json"
 	configHashKey     = "__config__"
+	themeHashKey      = "__theme__"
 	manifestVersion   = "1"
 )
 
+// ManifestPath returns the path of the build manifest inside cacheDir.
+func ManifestPath(cacheDir string) string {
+	return filepath.Join(cacheDir, cacheManifestFile)
+}
+
 // ReadManifest loads the BuildManifest from cacheDir/manifest.json.
 // Returns nil (no error) when the file does not exist yet.
 func ReadManifest(cacheDir string) (*model.BuildManifest, error) {
-	path := filepath.Join(cacheDir, cacheManifestFile)
+	path := ManifestPath(cacheDir)
 	data, err := os.ReadFile(path)
 	if errors.Is(err, os.ErrNotExist) {
 		return nil, nil
@@ -47,7 +56,7 @@ func WriteManifest(cacheDir string, m *model.BuildManifest) error {
 	if err != nil {
 		return fmt.Errorf("marshal manifest: %w", err)
 	}
-	path := filepath.Join(cacheDir, cacheManifestFile)
+	path := ManifestPath(cacheDir)
 	if err := writeAtomicFile(path, data, 0644); err != nil {
 		return fmt.Errorf("write manifest: %w", err)
 	}
@@ -84,11 +93,30 @@ func writeAtomicFile(path string, data []byte, perm os.FileMode) error {
 	return nil
 }
 
-// ClearCache removes all files under cacheDir.
-func ClearCache(cacheDir string) error {
-	if err := os.RemoveAll(cacheDir); err != nil {
+// ClearCache removes all files under cacheDir except the top-level entries
+// named in keep, which lets content-addressed caches survive a full rebuild.
+func ClearCache(cacheDir string, keep ...string) error {
+	if len(keep) == 0 {
+		if err := os.RemoveAll(cacheDir); err != nil {
+			return fmt.Errorf("clear cache: %w", err)
+		}
+		return nil
+	}
+	entries, err := os.ReadDir(cacheDir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
 		return fmt.Errorf("clear cache: %w", err)
 	}
+	for _, e := range entries {
+		if slices.Contains(keep, e.Name()) {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(cacheDir, e.Name())); err != nil {
+			return fmt.Errorf("clear cache: %w", err)
+		}
+	}
 	return nil
 }
 
@@ -103,6 +131,95 @@ func CheckConfigChange(manifest *model.BuildManifest, currentConfigHash string)
 	return !ok || stored != currentConfigHash
 }
 
+// CheckThemeChange returns true when the theme digest stored in manifest
+// differs from currentThemeHash. Template edits do not touch content files, so
+// the caller must force a full rebuild when this reports a change. A nil
+// manifest, or one written before theme hashes were recorded, is treated as
+// changed.
+func CheckThemeChange(manifest *model.BuildManifest, currentThemeHash string) bool {
+	if manifest == nil || manifest.FileHashes == nil {
+		return true
+	}
+	stored, ok := manifest.FileHashes[themeHashKey]
+	return !ok || stored != currentThemeHash
+}
+
+// SetThemeHash records themeHash in m so the next build can detect theme
+// changes via CheckThemeChange.
+func SetThemeHash(m *model.BuildManifest, themeHash string) {
+	if m.FileHashes == nil {
+		m.FileHashes = make(map[string]string)
+	}
+	m.FileHashes[themeHashKey] = themeHash
+}
+
+// CollectOutputFiles stats and hashes each of paths and returns one
+// OutputFile per existing file, with Path relative to outDir (forward
+// slashes) and ContentType derived from the file extension. Files that do
+// not exist are skipped; entries are sorted by Path.
+func CollectOutputFiles(outDir string, paths []string) ([]model.OutputFile, error) {
+	seen := make(map[string]bool, len(paths))
+	files := make([]model.OutputFile, 0, len(paths))
+	for _, p := range paths {
+		rel, err := filepath.Rel(outDir, p)
+		if err != nil {
+			return nil, fmt.Errorf("output path %s: %w", p, err)
+		}
+		rel = filepath.ToSlash(rel)
+		if seen[rel] {
+			continue
+		}
+		seen[rel] = true
+		info, err := os.Stat(p)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("stat output %s: %w", p, err)
+		}
+		h, err := hashFile(p)
+		if err != nil {
+			return nil, fmt.Errorf("hash output %s: %w", p, err)
+		}
+		ct := mime.TypeByExtension(filepath.Ext(p))
+		if ct == "" {
+			ct = "application/octet-stream"
+		}
+		files = append(files, model.OutputFile{
+			Path:         rel,
+			Hash:         h,
+			Size:         info.Size(),
+			LastModified: info.ModTime().UTC(),
+			ContentType:  ct,
+		})
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Path < files[j].Path })
+	return files, nil
+}
+
+// OutputUnchanged returns a function reporting whether the file at an
+// absolute path under outDir is recorded in previous (the OutputFiles of
+// the last build's manifest) with the hash it has now. Files are hashed on
+// demand; the function is safe for concurrent use.
+func OutputUnchanged(outDir string, previous []model.OutputFile) func(path string) bool {
+	recorded := make(map[string]string, len(previous))
+	for _, f := range previous {
+		recorded[f.Path] = f.Hash
+	}
+	return func(path string) bool {
+		rel, err := filepath.Rel(outDir, path)
+		if err != nil {
+			return false
+		}
+		want, ok := recorded[filepath.ToSlash(rel)]
+		if !ok {
+			return false
+		}
+		h, err := hashFile(path)
+		return err == nil && h == want
+	}
+}
+
 // NewManifest returns a fresh BuildManifest stamped with currentConfigHash.
 func NewManifest(configHash string) *model.BuildManifest {
 	return &model.BuildManifest{
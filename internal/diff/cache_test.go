@@ -3,6 +3,7 @@ package diff
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -59,6 +60,31 @@ func TestClearCache(t *testing.T) {
 	}
 }
 
+func TestClearCache_Keep(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{filepath.Join("render", "ab.json"), "manifest.json"} {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("{}"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := ClearCache(dir, "render"); err != nil {
+		t.Fatalf("ClearCache: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "manifest.json")); !os.IsNotExist(err) {
+		t.Error("expected manifest.json removed")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "render", "ab.json")); err != nil {
+		t.Errorf("expected kept entry to survive: %v", err)
+	}
+	if err := ClearCache(filepath.Join(dir, "missing"), "render"); err != nil {
+		t.Errorf("ClearCache on missing dir: %v", err)
+	}
+}
+
 func TestCheckConfigChange_NilManifest(t *testing.T) {
 	if !CheckConfigChange(nil, "hash") {
 		t.Error("expected true for nil")
@@ -88,3 +114,75 @@ func TestNewManifest(t *testing.T) {
 		t.Errorf("config hash: %v", m.FileHashes)
 	}
 }
+
+func TestCheckThemeChange(t *testing.T) {
+	if !CheckThemeChange(nil, "h") {
+		t.Error("expected true for nil manifest")
+	}
+	m := NewManifest("cfg")
+	if !CheckThemeChange(m, "h") {
+		t.Error("expected true when no theme hash is recorded")
+	}
+	SetThemeHash(m, "h")
+	if CheckThemeChange(m, "h") {
+		t.Error("expected false for identical theme hash")
+	}
+	if !CheckThemeChange(m, "other") {
+		t.Error("expected true for different theme hash")
+	}
+}
+
+func TestCollectOutputFiles(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "posts"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	index := filepath.Join(dir, "index.html")
+	post := filepath.Join(dir, "posts", "a.xml")
+	for _, p := range []string{index, post} {
+		if err := os.WriteFile(p, []byte("x"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	got, err := CollectOutputFiles(dir, []string{post, index, index, filepath.Join(dir, "missing.html")})
+	if err != nil {
+		t.Fatalf("CollectOutputFiles: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected 2 entries (deduplicated, missing skipped), got %d: %+v", len(got), got)
+	}
+	if got[0].Path != "index.html" || got[1].Path != "posts/a.xml" {
+		t.Errorf("paths: got %q, %q", got[0].Path, got[1].Path)
+	}
+	if !strings.HasPrefix(got[0].ContentType, "text/html") {
+		t.Errorf("ContentType: got %q", got[0].ContentType)
+	}
+	if got[0].Size != 1 || got[0].Hash == "" {
+		t.Errorf("size/hash not recorded: %+v", got[0])
+	}
+}
+
+func TestOutputUnchanged(t *testing.T) {
+	dir := t.TempDir()
+	same := filepath.Join(dir, "same.html")
+	edited := filepath.Join(dir, "edited.html")
+	added := filepath.Join(dir, "added.html")
+	for _, p := range []string{same, edited, added} {
+		if err := os.WriteFile(p, []byte("v1"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	previous, err := CollectOutputFiles(dir, []string{same, edited})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(edited, []byte("v2"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	unchanged := OutputUnchanged(dir, previous)
+	for path, want := range map[string]bool{same: true, edited: false, added: false, filepath.Join(dir, "missing.html"): false} {
+		if got := unchanged(path); got != want {
+			t.Errorf("%s: got %v, want %v", filepath.Base(path), got, want)
+		}
+	}
+}
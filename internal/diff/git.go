@@ -3,10 +3,12 @@ package diff
 import (
 	"crypto/sha256"
 	"encoding/hex"
+	"fmt"
 	"io"
 	"io/fs"
 	"os"
 	"path/filepath"
+	"sort"
 
 	"github.com/bmf-san/gohan/internal/model"
 )
@@ -49,7 +51,7 @@ func (g *GitDiffEngine) Detect(manifest *model.BuildManifest) (*model.ChangeSet,
 		}
 	}
 	for path := range manifest.FileHashes {
-		if path == configHashKey {
+		if path == configHashKey || path == themeHashKey {
 			continue // sentinel key — not a real content file
 		}
 		if _, ok := current[path]; !ok {
@@ -59,6 +61,40 @@ func (g *GitDiffEngine) Detect(manifest *model.BuildManifest) (*model.ChangeSet,
 	return cs, nil
 }
 
+// Snapshot returns the SHA-256 hex digest of every file under rootDir keyed
+// by its path relative to rootDir, in the form Detect compares against
+// BuildManifest.FileHashes.
+func (g *GitDiffEngine) Snapshot() (map[string]string, error) {
+	return hashAllFiles(g.rootDir)
+}
+
+// UpdateHashes returns a copy of the content hashes in prev (sentinel keys
+// dropped) with the files in cs, which must be relative to rootDir, re-hashed
+// or removed. It lets the next manifest be written without hashing files that
+// did not change.
+func UpdateHashes(rootDir string, prev map[string]string, cs *model.ChangeSet) (map[string]string, error) {
+	hashes := make(map[string]string, len(prev))
+	for path, h := range prev {
+		if path == configHashKey || path == themeHashKey {
+			continue
+		}
+		hashes[path] = h
+	}
+	for _, path := range cs.DeletedFiles {
+		delete(hashes, path)
+	}
+	for _, group := range [][]string{cs.AddedFiles, cs.ModifiedFiles} {
+		for _, path := range group {
+			h, err := hashFile(filepath.Join(rootDir, path))
+			if err != nil {
+				return nil, err
+			}
+			hashes[path] = h
+		}
+	}
+	return hashes, nil
+}
+
 // Hash returns the SHA-256 hex digest of the file at filePath.
 func (g *GitDiffEngine) Hash(filePath string) (string, error) {
 	return hashFile(filePath)
@@ -85,6 +121,26 @@ func hashAllFiles(rootDir string) (map[string]string, error) {
 	return result, err
 }
 
+// HashDir returns a single SHA-256 hex digest covering the relative path and
+// content of every file under dir. The digest changes when any file is added,
+// removed, renamed, or modified.
+func HashDir(dir string) (string, error) {
+	hashes, err := hashAllFiles(dir)
+	if err != nil {
+		return "", err
+	}
+	paths := make([]string, 0, len(hashes))
+	for p := range hashes {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	h := sha256.New()
+	for _, p := range paths {
+		_, _ = fmt.Fprintf(h, "%s\x00%s\n", filepath.ToSlash(p), hashes[p])
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
 // hashFile returns the SHA-256 hex digest of the file at path.
 func hashFile(path string) (string, error) {
 	f, err := os.Open(path)
@@ -141,3 +141,101 @@ func TestGitDiffEngine_Hash_Missing(t *testing.T) {
 		t.Error("expected error for missing file")
 	}
 }
+
+func TestHashDir(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a.html"), []byte("A"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	h1, err := HashDir(dir)
+	if err != nil {
+		t.Fatalf("HashDir: %v", err)
+	}
+	h2, _ := HashDir(dir)
+	if h1 != h2 {
+		t.Error("expected stable digest")
+	}
+	if err := os.WriteFile(filepath.Join(dir, "a.html"), []byte("B"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	h3, _ := HashDir(dir)
+	if h3 == h1 {
+		t.Error("expected digest to change after modification")
+	}
+	if err := os.Rename(filepath.Join(dir, "a.html"), filepath.Join(dir, "b.html")); err != nil {
+		t.Fatal(err)
+	}
+	h4, _ := HashDir(dir)
+	if h4 == h3 {
+		t.Error("expected digest to change after rename")
+	}
+}
+
+func TestDetect_IgnoresThemeSentinel(t *testing.T) {
+	dir := t.TempDir()
+	manifest := &model.BuildManifest{FileHashes: map[string]string{themeHashKey: "x", configHashKey: "y"}}
+	cs, err := NewGitDiffEngine(dir).Detect(manifest)
+	if err != nil {
+		t.Fatalf("Detect: %v", err)
+	}
+	if len(cs.DeletedFiles) != 0 {
+		t.Errorf("sentinel keys reported as deleted: %v", cs.DeletedFiles)
+	}
+}
+
+func TestGitDiffEngine_Snapshot(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "posts"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"tags.yaml", filepath.Join("posts", "draft.md")} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(name), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	got, err := NewGitDiffEngine(dir).Snapshot()
+	if err != nil {
+		t.Fatalf("Snapshot: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected 2 entries, got %v", got)
+	}
+	cs, err := NewGitDiffEngine(dir).Detect(&model.BuildManifest{FileHashes: got})
+	if err != nil {
+		t.Fatalf("Detect: %v", err)
+	}
+	if len(cs.AddedFiles)+len(cs.ModifiedFiles)+len(cs.DeletedFiles) != 0 {
+		t.Errorf("expected no changes against a snapshot, got %+v", cs)
+	}
+}
+
+func TestUpdateHashes(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"a.md", "c.md"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	prev := map[string]string{configHashKey: "cfg", "a.md": "stale", "b.md": "gone", "keep.md": "same"}
+	cs := &model.ChangeSet{ModifiedFiles: []string{"a.md"}, AddedFiles: []string{"c.md"}, DeletedFiles: []string{"b.md"}}
+	got, err := UpdateHashes(dir, prev, cs)
+	if err != nil {
+		t.Fatalf("UpdateHashes: %v", err)
+	}
+	want := map[string]string{
+		"a.md":    fileHash(t, filepath.Join(dir, "a.md")),
+		"c.md":    fileHash(t, filepath.Join(dir, "c.md")),
+		"keep.md": "same",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("%s: got %q, want %q", k, got[k], v)
+		}
+	}
+	if prev["a.md"] != "stale" {
+		t.Error("UpdateHashes modified its input")
+	}
+}
@@ -0,0 +1,93 @@
+package diff
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/bmf-san/gohan/internal/model"
+)
+
+// LoadGitInfo reads the history of every file under rootDir from the git
+// repository containing it with a single `git log` call and returns it keyed
+// by path relative to rootDir (forward slashes). Files that were never
+// committed are absent from the map. In a shallow clone FirstCommitDate is the
+// oldest commit available locally.
+func LoadGitInfo(rootDir string) (map[string]*model.GitInfo, error) {
+	out, err := runGit(rootDir, "-c", "core.quotepath=off", "log", "-z",
+		"--name-only", "--relative", "--no-renames",
+		"--format=\x1e%H\x1f%an\x1f%ae\x1f%aI", "--", ".")
+	if err != nil {
+		return nil, err
+	}
+	infos := make(map[string]*model.GitInfo)
+	// git log lists commits newest first: the first commit seen for a file is
+	// its last change and the final one is the commit that added it. With -z
+	// the header and each file name end in NUL, and the file names start on a
+	// new line, so names containing newlines are kept intact.
+	for _, record := range strings.Split(string(out), "\x1e") {
+		header, files, _ := strings.Cut(record, "\x00")
+		files = strings.TrimPrefix(files, "\n")
+		fields := strings.Split(header, "\x1f")
+		if len(fields) != 4 {
+			continue
+		}
+		date, err := time.Parse(time.RFC3339, fields[3])
+		if err != nil {
+			continue
+		}
+		for _, f := range strings.Split(files, "\x00") {
+			if f == "" {
+				continue
+			}
+			f = filepath.ToSlash(f)
+			if info, ok := infos[f]; ok {
+				info.FirstCommitDate = date
+				continue
+			}
+			infos[f] = &model.GitInfo{
+				Hash:            fields[0],
+				AbbreviatedHash: fields[0][:min(7, len(fields[0]))],
+				AuthorName:      fields[1],
+				AuthorEmail:     fields[2],
+				FirstCommitDate: date,
+				LastCommitDate:  date,
+			}
+		}
+	}
+	return infos, nil
+}
+
+// GitInfoDigests returns a digest of each entry of infos, keyed like infos, in
+// the form BuildManifest.GitInfo records. The digest changes when a new commit
+// touches the file or its history is rewritten.
+func GitInfoDigests(infos map[string]*model.GitInfo) map[string]string {
+	digests := make(map[string]string, len(infos))
+	for path, info := range infos {
+		digests[path] = info.Hash + " " + info.FirstCommitDate.UTC().Format(time.RFC3339)
+	}
+	return digests
+}
+
+// MarkGitInfoChanges adds to cs.ModifiedFiles each of paths (content-relative,
+// forward slashes) whose digest in current differs from the one recorded in
+// manifest. Git-derived dates are not part of the file contents, so without
+// this a commit that leaves the contents unchanged would leave the article's
+// pages with stale dates. Paths cs already lists are skipped.
+func MarkGitInfoChanges(cs *model.ChangeSet, manifest *model.BuildManifest, current map[string]string, paths []string) {
+	var prev map[string]string
+	if manifest != nil {
+		prev = manifest.GitInfo
+	}
+	for _, p := range paths {
+		if prev[p] == current[p] {
+			continue
+		}
+		rel := filepath.FromSlash(p)
+		if slices.Contains(cs.AddedFiles, rel) || slices.Contains(cs.ModifiedFiles, rel) {
+			continue
+		}
+		cs.ModifiedFiles = append(cs.ModifiedFiles, rel)
+	}
+}
@@ -0,0 +1,98 @@
+package diff
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bmf-san/gohan/internal/model"
+)
+
+func TestLoadGitInfo(t *testing.T) {
+	content := initRepo(t)
+	if err := os.WriteFile(filepath.Join(content, "a.md"), []byte("v2"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	root := filepath.Dir(content)
+	git(t, root, "commit", "-q", "-am", "update a",
+		"--date=2030-01-02T03:04:05Z", "--author=Second <second@example.com>")
+	odd := "new\nline.md"
+	if err := os.WriteFile(filepath.Join(content, odd), []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	git(t, root, "add", "-A")
+	git(t, root, "commit", "-q", "-m", "add odd name")
+	if err := os.WriteFile(filepath.Join(content, "untracked.md"), []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	infos, err := LoadGitInfo(content)
+	if err != nil {
+		t.Fatalf("LoadGitInfo: %v", err)
+	}
+	a, ok := infos["a.md"]
+	if !ok {
+		t.Fatalf("missing a.md in %v", infos)
+	}
+	if a.AuthorName != "Second" || a.AuthorEmail != "second@example.com" {
+		t.Errorf("author: got %q <%s>", a.AuthorName, a.AuthorEmail)
+	}
+	if got := a.LastCommitDate.UTC().Format("2006-01-02"); got != "2030-01-02" {
+		t.Errorf("LastCommitDate: got %s", got)
+	}
+	if !a.FirstCommitDate.Before(a.LastCommitDate) {
+		t.Errorf("FirstCommitDate %v should precede LastCommitDate %v", a.FirstCommitDate, a.LastCommitDate)
+	}
+	if len(a.Hash) != 40 || a.AbbreviatedHash != a.Hash[:7] {
+		t.Errorf("hash: %q / %q", a.Hash, a.AbbreviatedHash)
+	}
+	b := infos["b.md"]
+	if b == nil || b.AuthorName != "test" || !b.FirstCommitDate.Equal(b.LastCommitDate) {
+		t.Errorf("b.md: %+v", b)
+	}
+	if _, ok := infos[odd]; !ok {
+		t.Errorf("missing %q in %v", odd, infos)
+	}
+	if _, ok := infos["untracked.md"]; ok {
+		t.Error("untracked file should have no git info")
+	}
+}
+
+func TestMarkGitInfoChanges(t *testing.T) {
+	content := initRepo(t)
+	root := filepath.Dir(content)
+	// a.md is built from an uncommitted edit, then committed unchanged: its
+	// contents match the last build but its git dates do not.
+	if err := os.WriteFile(filepath.Join(content, "a.md"), []byte("v2"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	before, err := LoadGitInfo(content)
+	if err != nil {
+		t.Fatalf("LoadGitInfo: %v", err)
+	}
+	manifest := &model.BuildManifest{GitInfo: GitInfoDigests(before)}
+	git(t, root, "commit", "-q", "-am", "update a", "--date=2030-01-02T03:04:05Z")
+	after, err := LoadGitInfo(content)
+	if err != nil {
+		t.Fatalf("LoadGitInfo: %v", err)
+	}
+
+	cs := &model.ChangeSet{}
+	MarkGitInfoChanges(cs, manifest, GitInfoDigests(after), []string{"a.md", "b.md"})
+	if len(cs.ModifiedFiles) != 1 || cs.ModifiedFiles[0] != "a.md" {
+		t.Errorf("ModifiedFiles: got %v, want [a.md]", cs.ModifiedFiles)
+	}
+
+	cs = &model.ChangeSet{ModifiedFiles: []string{"a.md"}}
+	MarkGitInfoChanges(cs, manifest, GitInfoDigests(after), []string{"a.md", "b.md"})
+	if len(cs.ModifiedFiles) != 1 {
+		t.Errorf("a.md listed twice: %v", cs.ModifiedFiles)
+	}
+
+	// A manifest written without git info marks every article.
+	cs = &model.ChangeSet{}
+	MarkGitInfoChanges(cs, &model.BuildManifest{}, GitInfoDigests(after), []string{"a.md", "b.md"})
+	if len(cs.ModifiedFiles) != 2 {
+		t.Errorf("ModifiedFiles: got %v, want both articles", cs.ModifiedFiles)
+	}
+}
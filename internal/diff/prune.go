@@ -0,0 +1,66 @@
+package diff
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/bmf-san/gohan/internal/model"
+)
+
+// StaleOutputs returns the absolute paths of files recorded in previous (the
+// OutputFiles of the last build's manifest) that are not among current, the
+// absolute paths produced by this build. These are the pages, OGP images, and
+// copies left behind by deleted or renamed articles and vanished taxonomies.
+// Recorded paths that would resolve outside outDir are ignored so that a
+// tampered manifest cannot delete arbitrary files. The result is sorted.
+func StaleOutputs(outDir string, previous []model.OutputFile, current []string) []string {
+	keep := make(map[string]bool, len(current))
+	for _, p := range current {
+		keep[filepath.Clean(p)] = true
+	}
+	var stale []string
+	seen := make(map[string]bool)
+	for _, f := range previous {
+		rel := filepath.FromSlash(f.Path)
+		if !filepath.IsLocal(rel) {
+			continue
+		}
+		abs := filepath.Join(outDir, rel)
+		if keep[abs] || seen[abs] {
+			continue
+		}
+		seen[abs] = true
+		stale = append(stale, abs)
+	}
+	sort.Strings(stale)
+	return stale
+}
+
+// PruneOutputs deletes stale files under outDir and then removes any parent
+// directories the deletions left empty, stopping at outDir itself. Files that
+// no longer exist are skipped. It returns the paths actually removed.
+func PruneOutputs(outDir string, stale []string) ([]string, error) {
+	root := filepath.Clean(outDir)
+	var removed []string
+	var errs []error
+	for _, p := range stale {
+		if err := os.Remove(p); err != nil {
+			if !errors.Is(err, os.ErrNotExist) {
+				errs = append(errs, fmt.Errorf("prune %s: %w", p, err))
+			}
+			continue
+		}
+		removed = append(removed, p)
+		for dir := filepath.Dir(p); dir != root && strings.HasPrefix(dir, root+string(filepath.Separator)); dir = filepath.Dir(dir) {
+			// os.Remove fails on non-empty directories, which ends the walk.
+			if os.Remove(dir) != nil {
+				break
+			}
+		}
+	}
+	return removed, errors.Join(errs...)
+}
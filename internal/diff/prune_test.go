@@ -0,0 +1,62 @@
+package diff
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bmf-san/gohan/internal/model"
+)
+
+func TestStaleOutputs(t *testing.T) {
+	outDir := t.TempDir()
+	previous := []model.OutputFile{
+		{Path: "index.html"},
+		{Path: "posts/old/index.html"},
+		{Path: "ogp/old.png"},
+		{Path: "ogp/old.png"},
+		{Path: "../outside.html"},
+		{Path: "/etc/passwd"},
+	}
+	current := []string{filepath.Join(outDir, "index.html"), filepath.Join(outDir, "posts", "new", "index.html")}
+	got := StaleOutputs(outDir, previous, current)
+	want := []string{filepath.Join(outDir, "ogp", "old.png"), filepath.Join(outDir, "posts", "old", "index.html")}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("got[%d] = %s, want %s", i, got[i], want[i])
+		}
+	}
+}
+
+func TestPruneOutputs(t *testing.T) {
+	outDir := t.TempDir()
+	stale := filepath.Join(outDir, "posts", "old", "index.html")
+	kept := filepath.Join(outDir, "posts", "new", "index.html")
+	for _, p := range []string{stale, kept} {
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte("x"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	removed, err := PruneOutputs(outDir, []string{stale, filepath.Join(outDir, "missing.html")})
+	if err != nil {
+		t.Fatalf("PruneOutputs: %v", err)
+	}
+	if len(removed) != 1 || removed[0] != stale {
+		t.Errorf("removed: got %v, want [%s]", removed, stale)
+	}
+	if _, err := os.Stat(filepath.Join(outDir, "posts", "old")); !os.IsNotExist(err) {
+		t.Errorf("expected empty directory posts/old to be removed, stat err = %v", err)
+	}
+	if _, err := os.Stat(kept); err != nil {
+		t.Errorf("unrelated output removed: %v", err)
+	}
+	if _, err := os.Stat(outDir); err != nil {
+		t.Errorf("output directory removed: %v", err)
+	}
+}
@@ -0,0 +1,145 @@
+package diff
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/bmf-san/gohan/internal/model"
+)
+
+// RepoDiffEngine implements DiffEngine on top of the git repository that
+// contains rootDir. Instead of hashing every file, Detect asks git which
+// files under rootDir differ between manifest.LastCommit and the working tree
+// and which files are untracked, and hashes only those, so the cost grows
+// with the number of changed files rather than the size of the site. This
+// matters in CI, where a fresh checkout makes every modification time useless.
+//
+// Detect falls back to hashing every file (GitDiffEngine) when the manifest
+// records no commit or the commit is not available locally, e.g. in a shallow
+// clone.
+type RepoDiffEngine struct {
+	rootDir string
+	hasher  *GitDiffEngine
+}
+
+// NewRepoDiffEngine returns a RepoDiffEngine rooted at rootDir. It returns an
+// error when git is not installed or rootDir is not inside a git work tree;
+// callers should use NewGitDiffEngine instead in that case.
+func NewRepoDiffEngine(rootDir string) (*RepoDiffEngine, error) {
+	if _, err := runGit(rootDir, "rev-parse", "--is-inside-work-tree"); err != nil {
+		return nil, err
+	}
+	return &RepoDiffEngine{rootDir: rootDir, hasher: NewGitDiffEngine(rootDir)}, nil
+}
+
+// Detect returns the files under rootDir that changed since the build
+// recorded in manifest. Files reported by git are compared with the hashes in
+// manifest.FileHashes, so a file whose content matches the last build is not
+// reported even if git lists it.
+func (r *RepoDiffEngine) Detect(manifest *model.BuildManifest) (*model.ChangeSet, error) {
+	if manifest == nil || manifest.LastCommit == "" {
+		return r.hasher.Detect(manifest)
+	}
+	if _, err := runGit(r.rootDir, "cat-file", "-e", manifest.LastCommit+"^{commit}"); err != nil {
+		return r.hasher.Detect(manifest)
+	}
+	candidates, err := r.changedSince(manifest.LastCommit)
+	if err != nil {
+		return nil, err
+	}
+
+	cs := &model.ChangeSet{}
+	for _, rel := range candidates {
+		prev, known := manifest.FileHashes[rel]
+		h, err := hashFile(filepath.Join(r.rootDir, rel))
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+			if known {
+				cs.DeletedFiles = append(cs.DeletedFiles, rel)
+			}
+		case err != nil:
+			return nil, err
+		case !known:
+			cs.AddedFiles = append(cs.AddedFiles, rel)
+		case prev != h:
+			cs.ModifiedFiles = append(cs.ModifiedFiles, rel)
+		}
+	}
+	return cs, nil
+}
+
+// changedSince returns the sorted, deduplicated paths (relative to rootDir)
+// of tracked files that differ between commit and the working tree, plus all
+// untracked files, including ignored ones.
+func (r *RepoDiffEngine) changedSince(commit string) ([]string, error) {
+	diffOut, err := runGit(r.rootDir, "diff", "--name-only", "--no-renames", "--relative", "-z", commit, "--", ".")
+	if err != nil {
+		return nil, err
+	}
+	untracked, err := runGit(r.rootDir, "ls-files", "--others", "-z", "--", ".")
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool)
+	var paths []string
+	for _, out := range [][]byte{diffOut, untracked} {
+		for _, p := range strings.Split(string(out), "\x00") {
+			if p == "" || seen[p] {
+				continue
+			}
+			seen[p] = true
+			paths = append(paths, filepath.FromSlash(p))
+		}
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// CleanCommit returns the HEAD commit when the files under rootDir exactly
+// match it (no modified, untracked, or ignored files), and "" otherwise. Only
+// a clean commit can be stored as BuildManifest.LastCommit: uncommitted edits
+// that were built and later reverted would otherwise go unnoticed.
+func (r *RepoDiffEngine) CleanCommit() (string, error) {
+	status, err := runGit(r.rootDir, "status", "--porcelain", "-z", "--untracked-files=all", "--ignored", "--", ".")
+	if err != nil {
+		return "", err
+	}
+	if len(status) > 0 {
+		return "", nil
+	}
+	head, err := runGit(r.rootDir, "rev-parse", "--verify", "HEAD^{commit}")
+	if err != nil {
+		// An unborn branch has no commit yet.
+		return "", nil
+	}
+	return strings.TrimSpace(string(head)), nil
+}
+
+// Snapshot returns the SHA-256 hex digest of every file under rootDir; see
+// GitDiffEngine.Snapshot.
+func (r *RepoDiffEngine) Snapshot() (map[string]string, error) {
+	return r.hasher.Snapshot()
+}
+
+// Hash returns the SHA-256 hex digest of the file at filePath.
+func (r *RepoDiffEngine) Hash(filePath string) (string, error) {
+	return hashFile(filePath)
+}
+
+// runGit runs git with args in dir and returns its standard output.
+func runGit(dir string, args ...string) ([]byte, error) {
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("git %s: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.Bytes(), nil
+}
@@ -0,0 +1,153 @@
+package diff
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/bmf-san/gohan/internal/model"
+)
+
+// initRepo creates a git repository with a committed content/ directory
+// holding a.md and b.md and returns the content directory.
+func initRepo(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	root := t.TempDir()
+	content := filepath.Join(root, "content")
+	if err := os.MkdirAll(content, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"a.md", "b.md"} {
+		if err := os.WriteFile(filepath.Join(content, name), []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	git(t, root, "init", "-q")
+	git(t, root, "add", "-A")
+	git(t, root, "commit", "-q", "-m", "init")
+	return content
+}
+
+func git(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", append([]string{"-C", dir, "-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %s: %v: %s", strings.Join(args, " "), err, out)
+	}
+}
+
+// builtManifest returns the manifest a build of content at its clean HEAD
+// would write.
+func builtManifest(t *testing.T, r *RepoDiffEngine) *model.BuildManifest {
+	t.Helper()
+	hashes, err := r.Snapshot()
+	if err != nil {
+		t.Fatalf("Snapshot: %v", err)
+	}
+	commit, err := r.CleanCommit()
+	if err != nil || commit == "" {
+		t.Fatalf("CleanCommit: %q, %v", commit, err)
+	}
+	return &model.BuildManifest{FileHashes: hashes, LastCommit: commit}
+}
+
+func TestNewRepoDiffEngine_NotARepo(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	if _, err := NewRepoDiffEngine(t.TempDir()); err == nil {
+		t.Error("expected error outside a git work tree")
+	}
+}
+
+func TestRepoDiffEngine_Detect(t *testing.T) {
+	content := initRepo(t)
+	r, err := NewRepoDiffEngine(content)
+	if err != nil {
+		t.Fatalf("NewRepoDiffEngine: %v", err)
+	}
+	m := builtManifest(t, r)
+
+	// Modify a tracked file, delete another, and add an untracked one.
+	if err := os.WriteFile(filepath.Join(content, "a.md"), []byte("changed"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(filepath.Join(content, "b.md")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(content, "c.md"), []byte("new"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cs, err := r.Detect(m)
+	if err != nil {
+		t.Fatalf("Detect: %v", err)
+	}
+	check := func(name string, got []string, want ...string) {
+		t.Helper()
+		sort.Strings(got)
+		if strings.Join(got, ",") != strings.Join(want, ",") {
+			t.Errorf("%s: got %v, want %v", name, got, want)
+		}
+	}
+	check("added", cs.AddedFiles, "c.md")
+	check("modified", cs.ModifiedFiles, "a.md")
+	check("deleted", cs.DeletedFiles, "b.md")
+
+	if commit, err := r.CleanCommit(); err != nil || commit != "" {
+		t.Errorf("CleanCommit on a dirty tree: got %q, %v; want empty", commit, err)
+	}
+}
+
+func TestRepoDiffEngine_Detect_SkipsContentMatchingManifest(t *testing.T) {
+	content := initRepo(t)
+	r, err := NewRepoDiffEngine(content)
+	if err != nil {
+		t.Fatalf("NewRepoDiffEngine: %v", err)
+	}
+	m := builtManifest(t, r)
+
+	// A later commit touches a.md but the built content already matches it.
+	if err := os.WriteFile(filepath.Join(content, "a.md"), []byte("v2"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	m.FileHashes["a.md"] = fileHash(t, filepath.Join(content, "a.md"))
+	git(t, content, "commit", "-q", "-am", "v2")
+
+	cs, err := r.Detect(m)
+	if err != nil {
+		t.Fatalf("Detect: %v", err)
+	}
+	if len(cs.AddedFiles)+len(cs.ModifiedFiles)+len(cs.DeletedFiles) != 0 {
+		t.Errorf("expected no changes, got %+v", cs)
+	}
+}
+
+func TestRepoDiffEngine_Detect_FallsBackToHashing(t *testing.T) {
+	content := initRepo(t)
+	r, err := NewRepoDiffEngine(content)
+	if err != nil {
+		t.Fatalf("NewRepoDiffEngine: %v", err)
+	}
+	hashes, err := r.Snapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(content, "a.md"), []byte("changed"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for _, commit := range []string{"", "0123456789abcdef0123456789abcdef01234567"} {
+		cs, err := r.Detect(&model.BuildManifest{FileHashes: hashes, LastCommit: commit})
+		if err != nil {
+			t.Fatalf("Detect(%q): %v", commit, err)
+		}
+		if len(cs.ModifiedFiles) != 1 || cs.ModifiedFiles[0] != "a.md" {
+			t.Errorf("Detect(%q): ModifiedFiles = %v, want [a.md]", commit, cs.ModifiedFiles)
+		}
+	}
+}
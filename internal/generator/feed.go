@@ -7,6 +7,7 @@ import (
 	"os"
 	"path/filepath"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/bmf-san/gohan/internal/model"
@@ -90,7 +91,7 @@ func GenerateFeeds(outDir, baseURL, siteTitle string, articles []*model.Processe
 	// write per-locale feeds under their locale subdirectory.
 	if len(cfg.I18n.Locales) > 0 {
 		rootArticles := filterFeedArticles(sorted, cfg.I18n.DefaultLocale)
-		if err := writeRSS(outDir, baseURL, siteTitle, rootArticles); err != nil {
+		if err := writeRSS(outDir, baseURL, siteTitle, rootArticles, cfg); err != nil {
 			return err
 		}
 		if err := writeAtom(outDir, baseURL, siteTitle, rootArticles, cfg); err != nil {
@@ -114,7 +115,7 @@ func GenerateFeeds(outDir, baseURL, siteTitle string, articles []*model.Processe
 			} else {
 				channelURL = "/" + loc + "/"
 			}
-			if err := writeRSSWithChannelURL(locDir, baseURL, channelURL, siteTitle, locArticles); err != nil {
+			if err := writeRSSWithChannelURL(locDir, baseURL, channelURL, siteTitle, locArticles, cfg); err != nil {
 				return err
 			}
 			if err := writeAtomWithChannelURL(locDir, baseURL, channelURL, siteTitle, locArticles, cfg); err != nil {
@@ -124,18 +125,96 @@ func GenerateFeeds(outDir, baseURL, siteTitle string, articles []*model.Processe
 		return nil
 	}
 
-	if err := writeRSS(outDir, baseURL, siteTitle, sorted); err != nil {
+	if err := writeRSS(outDir, baseURL, siteTitle, sorted, cfg); err != nil {
 		return err
 	}
 	return writeAtom(outDir, baseURL, siteTitle, sorted, cfg)
 }
 
-func writeRSS(outDir, baseURL, title string, articles []*model.ProcessedArticle) error {
+// FeedOutputs returns the paths under outDir written by GenerateSitemap,
+// GenerateFeeds, and GenerateSearchIndex for cfg: the root sitemap.xml,
+// feed.xml, atom.xml, and search-index.json plus the per-locale feed and
+// search-index files for each non-default locale. With search.full_text it
+// also returns the full-text shards listed by the search indexes currently in
+// outDir, which depend on the indexed text.
+func FeedOutputs(outDir string, cfg model.Config) []string {
+	paths := []string{
+		filepath.Join(outDir, "sitemap.xml"),
+		filepath.Join(outDir, "feed.xml"),
+		filepath.Join(outDir, "atom.xml"),
+		filepath.Join(outDir, "search-index.json"),
+	}
+	for _, loc := range cfg.I18n.Locales {
+		if loc == cfg.I18n.DefaultLocale {
+			continue
+		}
+		paths = append(paths,
+			filepath.Join(outDir, loc, "feed.xml"),
+			filepath.Join(outDir, loc, "atom.xml"),
+			filepath.Join(outDir, loc, "search-index.json"),
+		)
+	}
+	if cfg.Search.FullText {
+		var shards []string
+		for _, p := range paths {
+			if filepath.Base(p) == "search-index.json" {
+				shards = append(shards, searchShardOutputs(p)...)
+			}
+		}
+		paths = append(paths, shards...)
+	}
+	return paths
+}
+
+// GenerateTaxonomyFeeds writes feed.xml (RSS 2.0) and atom.xml (Atom 1.0)
+// next to the listing of every term of each user-defined taxonomy of cfg
+// with feed enabled, e.g. authors/alice/feed.xml. The channel title is
+// "{siteTitle} - {term}". baseURL must not have a trailing slash.
+func GenerateTaxonomyFeeds(outDir, baseURL, siteTitle string, site *model.Site, cfg model.Config) error {
+	for _, p := range termPages(site, cfg) {
+		if !p.taxonomy.Feed {
+			continue
+		}
+		basePath, baseURLPath := termPaths(p, cfg)
+		dir := filepath.Join(outDir, basePath)
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return err
+		}
+		channelURL := baseURL + baseURLPath + "/"
+		title := siteTitle + " - " + p.term.Name
+		if err := writeRSSWithChannelURL(dir, baseURL, channelURL, title, p.articles, cfg); err != nil {
+			return err
+		}
+		if err := writeAtomWithChannelURL(dir, baseURL, channelURL, title, p.articles, cfg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// TaxonomyFeedOutputs returns the paths under outDir written by
+// GenerateTaxonomyFeeds for site.
+func TaxonomyFeedOutputs(outDir string, site *model.Site, cfg model.Config) []string {
+	var paths []string
+	for _, p := range termPages(site, cfg) {
+		if !p.taxonomy.Feed {
+			continue
+		}
+		basePath, _ := termPaths(p, cfg)
+		paths = append(paths,
+			filepath.Join(outDir, basePath, "feed.xml"),
+			filepath.Join(outDir, basePath, "atom.xml"),
+		)
+	}
+	return paths
+}
+
+func writeRSS(outDir, baseURL, title string, articles []*model.ProcessedArticle, cfg model.Config) error {
 	// channel URL must have a trailing slash (consistent with writeAtom).
-	return writeRSSWithChannelURL(outDir, baseURL, baseURL+"/", title, articles)
+	return writeRSSWithChannelURL(outDir, baseURL, baseURL+"/", title, articles, cfg)
 }
 
-func writeRSSWithChannelURL(outDir, itemBaseURL, channelURL, title string, articles []*model.ProcessedArticle) error {
+func writeRSSWithChannelURL(outDir, itemBaseURL, channelURL, title string, articles []*model.ProcessedArticle, cfg model.Config) error {
 	now := time.Now().UTC().Format(time.RFC1123Z)
 	ch := rssChannel{
 		Title:       title,
@@ -157,7 +236,7 @@ func writeRSSWithChannelURL(outDir, itemBaseURL, channelURL, title string, artic
 		})
 	}
 	root := rssRoot{Version: "2.0", Channel: ch}
-	return writeXML(filepath.Join(outDir, "feed.xml"), root)
+	return writeXML(filepath.Join(outDir, "feed.xml"), root, cfg.Build.MinifyXML)
 }
 
 func writeAtom(outDir, baseURL, title string, articles []*model.ProcessedArticle, cfg model.Config) error {
@@ -165,13 +244,17 @@ func writeAtom(outDir, baseURL, title string, articles []*model.ProcessedArticle
 }
 
 func writeAtomWithChannelURL(outDir, itemBaseURL, channelURL, title string, articles []*model.ProcessedArticle, cfg model.Config) error {
-	updated := time.Now().UTC().Format(time.RFC3339)
+	// The feed was last updated when its most recently updated entry was.
+	var latest time.Time
 	for _, a := range articles {
-		if !a.FrontMatter.Date.IsZero() {
-			updated = a.FrontMatter.Date.UTC().Format(time.RFC3339)
-			break
+		if !a.FrontMatter.Date.IsZero() && articleLastMod(a).After(latest) {
+			latest = articleLastMod(a)
 		}
 	}
+	updated := time.Now().UTC().Format(time.RFC3339)
+	if !latest.IsZero() {
+		updated = latest.UTC().Format(time.RFC3339)
+	}
 	// Author may be a string (typical), int, or other scalar; coerce via fmt
 	// since Theme.Params now accepts arbitrary YAML-typed values.
 	var author string
@@ -199,11 +282,24 @@ func writeAtomWithChannelURL(outDir, itemBaseURL, channelURL, title string, arti
 			ID:      link,
 			Title:   a.FrontMatter.Title,
 			Link:    atomLink{Rel: "alternate", Type: "text/html", Href: link},
-			Updated: a.FrontMatter.Date.UTC().Format(time.RFC3339),
+			Updated: articleLastMod(a).UTC().Format(time.RFC3339),
 			Summary: a.Summary,
 		})
 	}
-	return writeXML(filepath.Join(outDir, "atom.xml"), feed)
+	return writeXML(filepath.Join(outDir, "atom.xml"), feed, cfg.Build.MinifyXML)
+}
+
+// articleLastMod returns the date reported as an article's sitemap <lastmod>
+// and Atom <updated>: the lastmod front matter, else the last commit date when build.git_info is
+// enabled, else the publication date. The result is zero when none is known.
+func articleLastMod(a *model.ProcessedArticle) time.Time {
+	if !a.FrontMatter.LastMod.IsZero() {
+		return a.FrontMatter.LastMod
+	}
+	if a.GitInfo != nil && !a.GitInfo.LastCommitDate.IsZero() {
+		return a.GitInfo.LastCommitDate
+	}
+	return a.FrontMatter.Date
 }
 
 // articleLink returns the full URL for an article.
@@ -220,13 +316,20 @@ func articleLink(baseURL string, a *model.ProcessedArticle) string {
 	return baseURL + "/posts/" + s + "/"
 }
 
-func writeXML(path string, v interface{}) error {
+// writeXML encodes v to path, indented unless minify is set.
+func writeXML(path string, v interface{}, minify bool) error {
 	var buf bytes.Buffer
-	if _, err := buf.WriteString(xml.Header); err != nil {
+	header := xml.Header
+	if minify {
+		header = strings.TrimSuffix(header, "\n")
+	}
+	if _, err := buf.WriteString(header); err != nil {
 		return err
 	}
 	enc := xml.NewEncoder(&buf)
-	enc.Indent("", "  ")
+	if !minify {
+		enc.Indent("", "  ")
+	}
 	if err := enc.Encode(v); err != nil {
 		return err
 	}
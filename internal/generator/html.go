@@ -13,8 +13,11 @@ import (
 	"sync"
 	"time"
 
+	"github.com/bmf-san/gohan/internal/assets"
 	"github.com/bmf-san/gohan/internal/mermaid"
+	"github.com/bmf-san/gohan/internal/minify"
 	"github.com/bmf-san/gohan/internal/model"
+	"github.com/bmf-san/gohan/internal/processor"
 	gohantemplate "github.com/bmf-san/gohan/internal/template"
 )
 
@@ -23,6 +26,40 @@ type HTMLGenerator struct {
 	outDir string
 	engine gohantemplate.TemplateEngine
 	cfg    model.Config
+	graph  *model.DependencyGraph // set via SetDependencyGraph; enables incremental renders
+	// prevGraph is the previous build's graph, set via SetPreviousDependencyGraph.
+	prevGraph *model.DependencyGraph
+	outputs   []string // every file the last Generate call produced; see Outputs
+	// imageCacheDir holds resized images between builds; set via
+	// SetImageCacheDir. "" disables the cache.
+	imageCacheDir string
+	assets        *assets.Pipeline // set via SetAssetPipeline; nil copies assets verbatim
+	warnFunc      warnFunc         // set via SetWarnFunc; nil prints to the standard logger
+}
+
+// warnFunc reports a non-fatal problem found in the named part of a build.
+// args are key-value pairs locating it, such as "file" and the article path.
+type warnFunc func(scope string, err error, args ...any)
+
+// SetWarnFunc routes the warnings Generate and PlannedPages report, such as
+// a listing_slugs entry that matches no article, to warn, so that they follow
+// the command's log format. Without it they are printed by the standard log
+// package.
+func (g *HTMLGenerator) SetWarnFunc(warn func(scope string, err error, args ...any)) {
+	g.warnFunc = warn
+}
+
+// warn reports a non-fatal problem through the function set by SetWarnFunc.
+func (g *HTMLGenerator) warn(scope string, err error, args ...any) {
+	if g.warnFunc != nil {
+		g.warnFunc(scope, err, args...)
+		return
+	}
+	msg := fmt.Sprintf("[warn] %s: %v", scope, err)
+	for i := 0; i+1 < len(args); i += 2 {
+		msg += fmt.Sprintf(" %v=%v", args[i], args[i+1])
+	}
+	log.Print(msg)
 }
 
 // NewHTMLGenerator returns an HTMLGenerator that writes to outDir.
@@ -30,24 +67,48 @@ func NewHTMLGenerator(outDir string, engine gohantemplate.TemplateEngine, cfg mo
 	return &HTMLGenerator{outDir: outDir, engine: engine, cfg: cfg}
 }
 
+// SetAssetPipeline makes Generate publish the minified, bundled, and
+// fingerprinted files of p in addition to the verbatim copy of the assets
+// directory. Pass the pipeline whose asset function the templates use.
+func (g *HTMLGenerator) SetAssetPipeline(p *assets.Pipeline) {
+	g.assets = p
+}
+
 // writeJob describes a single page to render.
 type writeJob struct {
 	path string
 	tmpl string
 	data *model.Site
+	// deps lists the dependency-graph node paths (article FilePaths and
+	// tag/category/archive nodes) this page renders data from. nil marks a
+	// page that depends on the whole site, such as index listings and
+	// virtual pages.
+	deps []string
+	// source is the FilePath of the article an article page renders; "" for
+	// listings and virtual pages.
+	source string
 }
 
-// Generate writes all HTML pages and copies static assets.
-// Generate writes all HTML pages for site into g.outDir.
-// changeSet is forwarded to the OGP image generator only; HTML pages are always
-// fully regenerated regardless of changeSet.
+// Generate writes all HTML pages for site into g.outDir and copies static
+// assets. When changeSet is non-nil and a dependency graph has been attached
+// via SetDependencyGraph, only the article pages and the tag, category,
+// archive, and index listings impacted by the changed files are re-rendered.
+// Pages whose output file is missing are always written. changeSet is also
+// forwarded to the OGP image generator.
 func (g *HTMLGenerator) Generate(site *model.Site, changeSet *model.ChangeSet) error {
 	parallelism := g.cfg.Build.Parallelism
 	if parallelism <= 0 {
 		parallelism = 1
 	}
 
+	if len(site.Series) > 0 && !g.hasTemplate(seriesTemplate) {
+		g.warn("series", fmt.Errorf("template %q is not defined; skipping the listing pages of %d series", seriesTemplate, len(site.Series)))
+	}
 	jobs := g.buildJobs(site)
+	g.outputs = g.plannedOutputs(site, jobs)
+	if dirty, ok := g.impactedNodes(changeSet); ok {
+		jobs = selectDirtyJobs(jobs, dirty)
+	}
 	sem := make(chan struct{}, parallelism)
 	errc := make(chan error, len(jobs))
 	var wg sync.WaitGroup
@@ -76,6 +137,12 @@ func (g *HTMLGenerator) Generate(site *model.Site, changeSet *model.ChangeSet) e
 		return errors.Join(errs...)
 	}
 
+	for _, c := range g.resourceCopies(site) {
+		if err := copyFile(c.src, c.dst); err != nil {
+			return fmt.Errorf("copy bundle resource: %w", err)
+		}
+	}
+
 	if g.cfg.Build.AssetsDir != "" {
 		if err := CopyDir(g.cfg.Build.AssetsDir, filepath.Join(g.outDir, "assets")); err != nil {
 			if !os.IsNotExist(err) {
@@ -83,6 +150,11 @@ func (g *HTMLGenerator) Generate(site *model.Site, changeSet *model.ChangeSet) e
 			}
 		}
 	}
+	if g.assets != nil {
+		if err := g.assets.Write(filepath.Join(g.outDir, "assets")); err != nil {
+			return fmt.Errorf("write processed assets: %w", err)
+		}
+	}
 
 	if g.cfg.Build.StaticDir != "" {
 		if err := CopyDir(g.cfg.Build.StaticDir, g.outDir); err != nil {
@@ -92,6 +164,10 @@ func (g *HTMLGenerator) Generate(site *model.Site, changeSet *model.ChangeSet) e
 		}
 	}
 
+	if err := g.writeImageVariants(site, parallelism); err != nil {
+		return fmt.Errorf("resize images: %w", err)
+	}
+
 	if g.cfg.OGP.Enabled {
 		ogpGen := NewOGPGenerator(g.outDir, g.cfg.Build.ContentDir, g.cfg.OGP)
 		if err := ogpGen.Generate(site, changeSet); err != nil {
@@ -102,6 +178,70 @@ func (g *HTMLGenerator) Generate(site *model.Site, changeSet *model.ChangeSet) e
 	return nil
 }
 
+// Outputs returns the absolute paths of every file the last Generate call
+// produced for the site: all HTML pages (including those skipped because they
+// were unaffected by the change set), page bundle resources, copied and
+// processed assets, static files, resized image variants, and OGP images.
+// Sitemap, feed, and search-index files are listed by FeedOutputs, and the
+// feeds of user-defined taxonomy terms by TaxonomyFeedOutputs.
+func (g *HTMLGenerator) Outputs() []string {
+	return append([]string(nil), g.outputs...)
+}
+
+// PlannedOutputs returns the paths Generate would produce for site, in the
+// same form as Outputs, without rendering or writing anything.
+func (g *HTMLGenerator) PlannedOutputs(site *model.Site) []string {
+	return g.plannedOutputs(site, g.buildJobs(site))
+}
+
+// PlannedPage is an HTML page Generate would render.
+type PlannedPage struct {
+	Path     string      // absolute output path
+	Template string      // template name, e.g. "article.html" or a front matter template
+	Source   string      // FilePath of the article for article pages; "" for listings and virtual pages
+	Data     *model.Site // the data the template is executed with
+}
+
+// PlannedPages returns every HTML page Generate would render for site, in
+// render order, without rendering or writing anything. It lets callers such
+// as `gohan check` validate templates against the data a build would use.
+func (g *HTMLGenerator) PlannedPages(site *model.Site) []PlannedPage {
+	jobs := g.buildJobs(site)
+	pages := make([]PlannedPage, 0, len(jobs))
+	for _, j := range jobs {
+		pages = append(pages, PlannedPage{Path: j.path, Template: j.tmpl, Source: j.source, Data: j.data})
+	}
+	return pages
+}
+
+func (g *HTMLGenerator) plannedOutputs(site *model.Site, jobs []writeJob) []string {
+	out := make([]string, 0, len(jobs))
+	for _, j := range jobs {
+		out = append(out, j.path)
+	}
+	for _, c := range g.resourceCopies(site) {
+		out = append(out, c.dst)
+	}
+	if g.cfg.Build.AssetsDir != "" {
+		out = append(out, listCopies(g.cfg.Build.AssetsDir, filepath.Join(g.outDir, "assets"))...)
+	}
+	if g.assets != nil {
+		out = append(out, g.assets.Outputs(filepath.Join(g.outDir, "assets"))...)
+	}
+	if g.cfg.Build.StaticDir != "" {
+		out = append(out, listCopies(g.cfg.Build.StaticDir, g.outDir)...)
+	}
+	for _, v := range ImageVariants(site) {
+		out = append(out, imageVariantPath(g.outDir, v))
+	}
+	if g.cfg.OGP.Enabled {
+		for _, a := range site.Articles {
+			out = append(out, ogpImagePath(g.outDir, a))
+		}
+	}
+	return out
+}
+
 func (g *HTMLGenerator) buildJobs(site *model.Site) []writeJob {
 	var jobs []writeJob
 	perPage := g.cfg.Build.PerPage
@@ -156,6 +296,26 @@ func (g *HTMLGenerator) buildJobs(site *model.Site) []writeJob {
 		articleLocaleBases[""] = localeTaxonomyBase(site, site.Articles)
 	}
 
+	// Translation siblings keyed by TranslationKey, used to record the
+	// dependencies of each article page's language switcher.
+	byTranslationKey := map[string][]*model.ProcessedArticle{}
+	for _, a := range site.Articles {
+		if a.FrontMatter.TranslationKey != "" {
+			byTranslationKey[a.FrontMatter.TranslationKey] = append(byTranslationKey[a.FrontMatter.TranslationKey], a)
+		}
+	}
+
+	// Related articles are scored across the whole site once, not per page.
+	related := computeRelated(site.Articles, g.cfg.Related, g.warn)
+
+	// Series listing URLs, and the place of each part in its series.
+	for _, s := range site.Series {
+		_, baseURLPath := seriesPaths(s, g.cfg)
+		s.URL = baseURLPath + "/"
+	}
+	positions := seriesPositions(site.Series)
+	adjacent := processor.AdjacentArticles(site.Articles)
+
 	// Article pages: use pre-computed output path and respect FrontMatter.Template.
 	for _, a := range site.Articles {
 		a := a
@@ -170,19 +330,44 @@ func (g *HTMLGenerator) buildJobs(site *model.Site) []writeJob {
 		}
 		d := siteFor(base, []*model.ProcessedArticle{a})
 		d.CurrentLocale = a.Locale
-		d.RelatedArticles = relatedArticles(site.Articles, a, 5)
+		d.RelatedArticles = related.articles[a]
+		d.PrevArticle = adjacent[a].Prev
+		d.NextArticle = adjacent[a].Next
 		// Resolve listing_slugs into Site.ListingArticles.
 		// base.Articles is already filtered to a.Locale, so the resolution is
 		// locale-aware: each locale's listing page finds only its own locale's
 		// articles, even when en/picks.md and ja/picks.md share the same slug list.
 		if len(a.FrontMatter.ListingSlugs) > 0 {
-			d.ListingArticles = resolveListingSlugs(a.FrontMatter.ListingSlugs, a.Locale, a.FilePath, base.Articles)
+			d.ListingArticles = g.resolveListingSlugs(a.FrontMatter.ListingSlugs, a.Locale, a.FilePath, base.Articles)
 		}
+		deps := articleDeps(a, d.RelatedArticles, d.ListingArticles, byTranslationKey[a.FrontMatter.TranslationKey])
+		if related.scoredByContent[a] {
+			// Content scores use term weights of the whole locale, so any
+			// article's edit can change which articles are listed.
+			deps = append(deps, processor.RelatedNode)
+		}
+		if p, ok := positions[a]; ok {
+			setSeries(d, p)
+			// The series navigation lists every part, so the page depends on
+			// the series node, which every part's change impacts.
+			deps = append(deps, processor.SeriesNode(p.series.Name))
+		}
+		// The previous and next articles link to the page node of a, so
+		// their edits, moves, and removals re-render it.
+		deps = append(deps, processor.PageNode(a.ContentPath))
 		jobs = append(jobs, writeJob{
-			path: articlePath,
-			tmpl: tmplName,
-			data: d,
+			path:   articlePath,
+			tmpl:   tmplName,
+			data:   d,
+			deps:   deps,
+			source: a.FilePath,
 		})
+		// listing_slugs may name articles that do not exist yet or were just
+		// removed, so curated listing pages depend on the whole site, as do
+		// pages whose related front matter names a missing article.
+		if len(a.FrontMatter.ListingSlugs) > 0 || related.unresolved[a] {
+			jobs[len(jobs)-1].deps = nil
+		}
 	}
 
 	// Tag pages (paginated) — locale-aware when i18n is active
@@ -215,7 +400,7 @@ func (g *HTMLGenerator) buildJobs(site *model.Site) []writeJob {
 					baseURLPath = "/" + locale + "/tags/" + tagNorm(t.Name)
 				}
 				t.Translations = taxonomyTranslationsFor(tagTranslations, taxonomyTranslationKey(t), locale)
-				jobs = append(jobs, paginatedJobs(site, filtered, g.outDir, "tag.html", basePath, baseURLPath, perPage, locale, &t)...)
+				jobs = append(jobs, withDeps(paginatedJobs(site, filtered, g.outDir, "tag.html", basePath, baseURLPath, perPage, locale, &t), processor.TagNode(t.Name))...)
 			}
 		}
 	} else {
@@ -236,7 +421,7 @@ func (g *HTMLGenerator) buildJobs(site *model.Site) []writeJob {
 			basePath := filepath.Join("tags", tagNorm(t.Name))
 			baseURLPath := "/tags/" + tagNorm(t.Name)
 			t.Translations = taxonomyTranslationsFor(tagTranslations, taxonomyTranslationKey(t), "")
-			jobs = append(jobs, paginatedJobs(site, filtered, g.outDir, "tag.html", basePath, baseURLPath, perPage, "", &t)...)
+			jobs = append(jobs, withDeps(paginatedJobs(site, filtered, g.outDir, "tag.html", basePath, baseURLPath, perPage, "", &t), processor.TagNode(t.Name))...)
 		}
 	}
 
@@ -270,7 +455,7 @@ func (g *HTMLGenerator) buildJobs(site *model.Site) []writeJob {
 					baseURLPath = "/" + locale + "/categories/" + tagNorm(c.Name)
 				}
 				c.Translations = taxonomyTranslationsFor(categoryTranslations, taxonomyTranslationKey(c), locale)
-				jobs = append(jobs, paginatedJobs(site, filtered, g.outDir, "category.html", basePath, baseURLPath, perPage, locale, &c)...)
+				jobs = append(jobs, withDeps(paginatedJobs(site, filtered, g.outDir, "category.html", basePath, baseURLPath, perPage, locale, &c), processor.CategoryNode(c.Name))...)
 			}
 		}
 	} else {
@@ -291,10 +476,27 @@ func (g *HTMLGenerator) buildJobs(site *model.Site) []writeJob {
 			basePath := filepath.Join("categories", tagNorm(c.Name))
 			baseURLPath := "/categories/" + tagNorm(c.Name)
 			c.Translations = taxonomyTranslationsFor(categoryTranslations, taxonomyTranslationKey(c), "")
-			jobs = append(jobs, paginatedJobs(site, filtered, g.outDir, "category.html", basePath, baseURLPath, perPage, "", &c)...)
+			jobs = append(jobs, withDeps(paginatedJobs(site, filtered, g.outDir, "category.html", basePath, baseURLPath, perPage, "", &c), processor.CategoryNode(c.Name))...)
+		}
+	}
+
+	// Series pages (paginated), listing the parts in reading order. Each
+	// series already belongs to a single locale. Themes without a series
+	// template get no listing pages rather than a failed build.
+	if g.hasTemplate(seriesTemplate) {
+		for _, s := range site.Series {
+			basePath, baseURLPath := seriesPaths(s, g.cfg)
+			seriesJobs := paginatedJobs(site, s.Articles, g.outDir, seriesTemplate, basePath, baseURLPath, perPage, s.Locale, nil)
+			for i := range seriesJobs {
+				seriesJobs[i].data.CurrentSeries = s
+			}
+			jobs = append(jobs, withDeps(seriesJobs, processor.SeriesNode(s.Name))...)
 		}
 	}
 
+	// User-defined taxonomy term pages (paginated) and index pages.
+	jobs = append(jobs, g.taxonomyJobs(site)...)
+
 	// Archive pages — locale-aware when i18n is active.
 	// Articles with a zero date are skipped to avoid generating archives/0001/01/.
 	type ym struct {
@@ -336,7 +538,7 @@ func (g *HTMLGenerator) buildJobs(site *model.Site) []writeJob {
 					baseURLPath = fmt.Sprintf("/%s/archives/%04d/%02d", archivePrefix, k.year, int(k.month))
 					archivePath = fmt.Sprintf("/%s/archives/%04d/%02d/", archivePrefix, k.year, int(k.month))
 				}
-				jobs = append(jobs, paginatedArchiveJobs(site, as, g.outDir, basePath, baseURLPath, perPage, locale, archivePath, true)...)
+				jobs = append(jobs, withDeps(paginatedArchiveJobs(site, as, g.outDir, basePath, baseURLPath, perPage, locale, archivePath, true), processor.ArchiveNode(k.year))...)
 			}
 
 			for year, articles := range yearArchives {
@@ -353,7 +555,7 @@ func (g *HTMLGenerator) buildJobs(site *model.Site) []writeJob {
 					baseURLPath = fmt.Sprintf("/%s/archives/%04d", archivePrefix, y)
 					archivePath = fmt.Sprintf("/%s/archives/%04d/", archivePrefix, y)
 				}
-				jobs = append(jobs, paginatedArchiveJobs(site, as, g.outDir, basePath, baseURLPath, perPage, locale, archivePath, false)...)
+				jobs = append(jobs, withDeps(paginatedArchiveJobs(site, as, g.outDir, basePath, baseURLPath, perPage, locale, archivePath, false), processor.ArchiveNode(y))...)
 			}
 		}
 	} else {
@@ -374,7 +576,7 @@ func (g *HTMLGenerator) buildJobs(site *model.Site) []writeJob {
 			basePath := filepath.Join("archives", fmt.Sprintf("%04d", k.year), fmt.Sprintf("%02d", int(k.month)))
 			baseURLPath := fmt.Sprintf("/archives/%04d/%02d", k.year, int(k.month))
 			archivePath := fmt.Sprintf("/archives/%04d/%02d/", k.year, int(k.month))
-			jobs = append(jobs, paginatedArchiveJobs(site, as, g.outDir, basePath, baseURLPath, perPage, "", archivePath, true)...)
+			jobs = append(jobs, withDeps(paginatedArchiveJobs(site, as, g.outDir, basePath, baseURLPath, perPage, "", archivePath, true), processor.ArchiveNode(k.year))...)
 		}
 
 		yearArchives := map[int][]*model.ProcessedArticle{}
@@ -392,7 +594,7 @@ func (g *HTMLGenerator) buildJobs(site *model.Site) []writeJob {
 			basePath := filepath.Join("archives", fmt.Sprintf("%04d", y))
 			baseURLPath := fmt.Sprintf("/archives/%04d", y)
 			archivePath := fmt.Sprintf("/archives/%04d/", y)
-			jobs = append(jobs, paginatedArchiveJobs(site, as, g.outDir, basePath, baseURLPath, perPage, "", archivePath, false)...)
+			jobs = append(jobs, withDeps(paginatedArchiveJobs(site, as, g.outDir, basePath, baseURLPath, perPage, "", archivePath, false), processor.ArchiveNode(y))...)
 		}
 	}
 
@@ -421,11 +623,22 @@ func (g *HTMLGenerator) buildJobs(site *model.Site) []writeJob {
 	return jobs
 }
 
+// seriesTemplate is the template of the series listing pages.
+const seriesTemplate = "series.html"
+
+// hasTemplate reports whether the template engine defines name. Engines that
+// cannot tell are assumed to define every template.
+func (g *HTMLGenerator) hasTemplate(name string) bool {
+	e, ok := g.engine.(interface{ Has(name string) bool })
+	return !ok || e.Has(name)
+}
+
 // TaxonomyURLs returns the canonical URL paths (with trailing slash) for all
 // page-1 taxonomy and archive listing pages generated by this site.
 // Pagination sub-pages (/page/N/) are intentionally excluded.
 // Returned paths are relative to the site root, e.g. "/tags/go/",
-// "/categories/architecture/", "/archives/2024/", "/archives/2024/01/".
+// "/categories/architecture/", "/archives/2024/", "/archives/2024/01/",
+// and "/authors/" and "/authors/alice/" for a user-defined taxonomy.
 func TaxonomyURLs(site *model.Site, cfg model.Config) []string {
 	var urls []string
 
@@ -528,6 +741,13 @@ func TaxonomyURLs(site *model.Site, cfg model.Config) []string {
 		}
 	}
 
+	// Series belong to a single locale each.
+	for _, s := range site.Series {
+		_, baseURLPath := seriesPaths(s, cfg)
+		urls = append(urls, baseURLPath+"/")
+	}
+	urls = append(urls, termURLs(site, cfg)...)
+
 	sort.Strings(urls)
 	return urls
 }
@@ -679,12 +899,37 @@ func (g *HTMLGenerator) writePage(path, tmplName string, data *model.Site) error
 	if bytes.Contains(pageBytes, []byte(mermaid.MermaidMarker)) {
 		pageBytes = mermaid.InjectScript(pageBytes)
 	}
+	if g.cfg.Build.MinifyHTML {
+		pageBytes = minify.HTML(pageBytes)
+	}
 	if err := writeFileAtomic(path, pageBytes, 0o644); err != nil {
 		return fmt.Errorf("write %s: %w", path, err)
 	}
 	return nil
 }
 
+// resourceCopy is a page bundle resource and the path it is copied to.
+type resourceCopy struct {
+	src, dst string
+}
+
+// resourceCopies lists the bundle resources of every article, each copied
+// next to the article's index.html. They are copied on every Generate, like
+// assets, because changes to them are not tracked by the dependency graph.
+func (g *HTMLGenerator) resourceCopies(site *model.Site) []resourceCopy {
+	var copies []resourceCopy
+	for _, a := range site.Articles {
+		if len(a.Resources) == 0 {
+			continue
+		}
+		dir := filepath.Dir(articleOutputPath(a, g.outDir, g.cfg))
+		for _, r := range a.Resources {
+			copies = append(copies, resourceCopy{src: r.SourcePath, dst: filepath.Join(dir, filepath.FromSlash(r.Name))})
+		}
+	}
+	return copies
+}
+
 // CopyDir recursively copies all files from srcDir into dstDir.
 func CopyDir(srcDir, dstDir string) error {
 	return filepath.WalkDir(srcDir, func(path string, d fs.DirEntry, err error) error {
@@ -703,6 +948,25 @@ func CopyDir(srcDir, dstDir string) error {
 	})
 }
 
+// listCopies returns the destination paths CopyDir(srcDir, dstDir) would
+// write. A missing or unreadable srcDir yields no paths.
+func listCopies(srcDir, dstDir string) []string {
+	var out []string
+	_ = filepath.WalkDir(srcDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		if rel, relErr := filepath.Rel(srcDir, path); relErr == nil {
+			out = append(out, filepath.Join(dstDir, rel))
+		}
+		return nil
+	})
+	return out
+}
+
 func copyFile(src, dst string) error {
 	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
 		return err
@@ -868,6 +1132,8 @@ func siteFor(base *model.Site, articles []*model.ProcessedArticle) *model.Site {
 		Tags:         base.Tags,
 		Categories:   base.Categories,
 		ArchiveYears: base.ArchiveYears,
+		Taxonomies:   base.Taxonomies,
+		Series:       base.Series,
 		SiteData:     base.SiteData,
 	}
 }
@@ -921,7 +1187,9 @@ func localeTaxonomyBase(base *model.Site, articles []*model.ProcessedArticle) *m
 		Articles:     articles,
 		Tags:         tags,
 		Categories:   cats,
+		Taxonomies:   localeTerms(base, articles),
 		ArchiveYears: archiveYears(articles),
+		Series:       seriesIn(base.Series, articles),
 		SiteData:     base.SiteData,
 	}
 }
@@ -962,32 +1230,6 @@ func sortByDateDesc(articles []*model.ProcessedArticle) {
 	})
 }
 
-// relatedArticles returns up to n articles that share at least one category
-// with a (same locale), excluding a itself, sorted newest-first.
-func relatedArticles(all []*model.ProcessedArticle, a *model.ProcessedArticle, n int) []*model.ProcessedArticle {
-	catSet := make(map[string]bool, len(a.FrontMatter.Categories))
-	for _, c := range a.FrontMatter.Categories {
-		catSet[c] = true
-	}
-	var related []*model.ProcessedArticle
-	for _, candidate := range all {
-		if candidate == a || candidate.Locale != a.Locale {
-			continue
-		}
-		for _, c := range candidate.FrontMatter.Categories {
-			if catSet[c] {
-				related = append(related, candidate)
-				break
-			}
-		}
-	}
-	sortByDateDesc(related)
-	if len(related) > n {
-		related = related[:n]
-	}
-	return related
-}
-
 // articleOutputPath returns the absolute filesystem path for an article page.
 // When a.OutputPath is a valid relative path under cfg.Build.OutputDir, it is
 // translated to an absolute path under outDir.  Otherwise (e.g. in tests that
@@ -1014,13 +1256,13 @@ func articleOutputPath(a *model.ProcessedArticle, outDir string, cfg model.Confi
 }
 
 // resolveListingSlugs looks up each slug in slugs within src and returns the
-// matched articles in declared order.  Unknown slugs are skipped with a warning
-// log message.
+// matched articles in declared order.  Unknown slugs are skipped with a
+// warning.
 //
 // src must be pre-filtered to the locale of the owning article.  This makes the
 // resolution locale-aware: if en/picks.md and ja/picks.md declare the same slug
 // list, the EN page receives EN articles and the JA page receives JA articles.
-func resolveListingSlugs(slugs []string, locale, filePath string, src []*model.ProcessedArticle) []*model.ProcessedArticle {
+func (g *HTMLGenerator) resolveListingSlugs(slugs []string, locale, filePath string, src []*model.ProcessedArticle) []*model.ProcessedArticle {
 	bySlug := make(map[string]*model.ProcessedArticle, len(src))
 	for _, pa := range src {
 		bySlug[pa.FrontMatter.Slug] = pa
@@ -1030,7 +1272,7 @@ func resolveListingSlugs(slugs []string, locale, filePath string, src []*model.P
 		if pa, found := bySlug[slug]; found {
 			result = append(result, pa)
 		} else {
-			log.Printf("[warn] listing_slugs: slug %q not found (locale=%q file=%s)", slug, locale, filePath)
+			g.warn("listing_slugs", fmt.Errorf("slug %q not found (locale=%q)", slug, locale), "file", filePath)
 		}
 	}
 	return result
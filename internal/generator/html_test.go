@@ -10,6 +10,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/bmf-san/gohan/internal/assets"
 	"github.com/bmf-san/gohan/internal/model"
 )
 
@@ -49,6 +50,15 @@ func (c *captureEngine) Render(w io.Writer, name string, data *model.Site) error
 	return err
 }
 
+// indentedEngine renders a fixed, indented page for every template.
+type indentedEngine struct{}
+
+func (indentedEngine) Load(_ string, _ htmltemplate.FuncMap, _ string) error { return nil }
+func (indentedEngine) Render(w io.Writer, _ string, _ *model.Site) error {
+	_, err := io.WriteString(w, "<html>\n  <body>\n    <!-- main -->\n    <p>Hello\n      <em>world</em></p>\n    <pre>  a\n  b</pre>\n  </body>\n</html>\n")
+	return err
+}
+
 func makeSite() *model.Site {
 	now := time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC)
 	return &model.Site{
@@ -83,6 +93,24 @@ func TestGenerate_WritesExpectedFiles(t *testing.T) {
 	}
 }
 
+func TestGenerate_WarnFunc(t *testing.T) {
+	site := makeSite()
+	site.Articles[0].FilePath = "content/picks.md"
+	site.Articles[0].FrontMatter.ListingSlugs = []string{"hello-world", "missing"}
+	g := NewHTMLGenerator(t.TempDir(), &mockEngine{}, model.Config{Build: model.BuildConfig{Parallelism: 2}})
+	var got []string
+	g.SetWarnFunc(func(scope string, err error, args ...any) {
+		got = append(got, fmt.Sprint(scope, ": ", err, " ", args))
+	})
+	if err := g.Generate(site, nil); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	want := `listing_slugs: slug "missing" not found (locale="") [file content/picks.md]`
+	if len(got) != 1 || got[0] != want {
+		t.Errorf("warnings = %q, want [%q]", got, want)
+	}
+}
+
 func TestGenerate_SlugifiesTitle(t *testing.T) {
 	outDir := t.TempDir()
 	g := NewHTMLGenerator(outDir, &mockEngine{}, model.Config{Build: model.BuildConfig{Parallelism: 1}})
@@ -648,75 +676,6 @@ func TestSortByDateDesc(t *testing.T) {
 	}
 }
 
-func TestRelatedArticles(t *testing.T) {
-	t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
-	t1 := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
-	t2 := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
-
-	target := &model.ProcessedArticle{Article: model.Article{FrontMatter: model.FrontMatter{
-		Title: "Target", Slug: "target", Categories: []string{"go", "web"}, Date: t1,
-	}}, Locale: "en"}
-	same1 := &model.ProcessedArticle{Article: model.Article{FrontMatter: model.FrontMatter{
-		Title: "Same1", Slug: "same1", Categories: []string{"go"}, Date: t2,
-	}}, Locale: "en"}
-	same2 := &model.ProcessedArticle{Article: model.Article{FrontMatter: model.FrontMatter{
-		Title: "Same2", Slug: "same2", Categories: []string{"web"}, Date: t0,
-	}}, Locale: "en"}
-	diffLocale := &model.ProcessedArticle{Article: model.Article{FrontMatter: model.FrontMatter{
-		Title: "JA", Slug: "ja", Categories: []string{"go"}, Date: t2,
-	}}, Locale: "ja"}
-	unrelated := &model.ProcessedArticle{Article: model.Article{FrontMatter: model.FrontMatter{
-		Title: "Unrelated", Slug: "unrelated", Categories: []string{"rust"}, Date: t2,
-	}}, Locale: "en"}
-
-	all := []*model.ProcessedArticle{target, same1, same2, diffLocale, unrelated}
-
-	t.Run("returns same-locale same-category articles sorted newest first", func(t *testing.T) {
-		got := relatedArticles(all, target, 5)
-		if len(got) != 2 {
-			t.Fatalf("expected 2 related, got %d", len(got))
-		}
-		if got[0].FrontMatter.Title != "Same1" {
-			t.Errorf("expected Same1 first (newer), got %q", got[0].FrontMatter.Title)
-		}
-		if got[1].FrontMatter.Title != "Same2" {
-			t.Errorf("expected Same2 second (older), got %q", got[1].FrontMatter.Title)
-		}
-	})
-
-	t.Run("excludes the target article itself", func(t *testing.T) {
-		got := relatedArticles(all, target, 5)
-		for _, a := range got {
-			if a == target {
-				t.Error("target article should not appear in related")
-			}
-		}
-	})
-
-	t.Run("excludes different locale", func(t *testing.T) {
-		got := relatedArticles(all, target, 5)
-		for _, a := range got {
-			if a.Locale != target.Locale {
-				t.Errorf("got article with locale %q, expected %q", a.Locale, target.Locale)
-			}
-		}
-	})
-
-	t.Run("respects n limit", func(t *testing.T) {
-		got := relatedArticles(all, target, 1)
-		if len(got) != 1 {
-			t.Fatalf("expected 1 with n=1, got %d", len(got))
-		}
-	})
-
-	t.Run("returns empty when no categories match", func(t *testing.T) {
-		got := relatedArticles(all, unrelated, 5)
-		if len(got) != 0 {
-			t.Errorf("expected 0 related for unrelated article, got %d", len(got))
-		}
-	})
-}
-
 func TestGenerate_SkipsDateZeroArchive(t *testing.T) {
 	outDir := t.TempDir()
 	site := &model.Site{
@@ -918,6 +877,34 @@ func TestArchiveYears_Empty(t *testing.T) {
 	}
 }
 
+func TestBuildJobs_PrevNextArticle(t *testing.T) {
+	site := makeSiteI18n()
+	older := &model.ProcessedArticle{
+		Article: model.Article{FrontMatter: model.FrontMatter{
+			Title: "Older EN", Slug: "older-en", Date: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		}},
+		Locale: "en",
+	}
+	site.Articles = append(site.Articles, older)
+	g := NewHTMLGenerator(t.TempDir(), &mockEngine{}, site.Config)
+
+	pages := map[string]*model.Site{}
+	for _, j := range g.buildJobs(site) {
+		if j.tmpl == "article.html" {
+			pages[j.data.Articles[0].FrontMatter.Slug] = j.data
+		}
+	}
+	if d := pages["hello-en"]; d.PrevArticle != older || d.NextArticle != nil {
+		t.Errorf("hello-en: got prev=%v next=%v, want prev=older-en next=nil", d.PrevArticle, d.NextArticle)
+	}
+	if d := pages["older-en"]; d.PrevArticle != nil || d.NextArticle != site.Articles[0] {
+		t.Errorf("older-en: got prev=%v next=%v, want prev=nil next=hello-en", d.PrevArticle, d.NextArticle)
+	}
+	if d := pages["hello-ja"]; d.PrevArticle != nil || d.NextArticle != nil {
+		t.Errorf("hello-ja: got prev=%v next=%v, want none across locales", d.PrevArticle, d.NextArticle)
+	}
+}
+
 func TestLocaleTaxonomyBase_SetsArchiveYears(t *testing.T) {
 	base := &model.Site{Config: model.Config{}}
 	articles := []*model.ProcessedArticle{
@@ -1118,3 +1105,118 @@ func TestLocaleTaxonomyBase_NoFallback(t *testing.T) {
 		t.Errorf("JA localeTaxonomyBase: expected 2 tags, got %d: %v", len(jaBase.Tags), jaBase.Tags)
 	}
 }
+
+func TestHTMLGenerator_PlannedPages(t *testing.T) {
+	site := makeSite()
+	site.Articles[0].FilePath = "/content/posts/hello.md"
+	site.Articles[0].FrontMatter.Template = "custom.html"
+	site.VirtualPages = []*model.VirtualPage{{OutputPath: "books/index.html", Template: "books.html"}}
+	engine := &mockEngine{}
+	pages := NewHTMLGenerator("/out", engine, site.Config).PlannedPages(site)
+
+	byTmpl := map[string]PlannedPage{}
+	for _, p := range pages {
+		if _, ok := byTmpl[p.Template]; !ok {
+			byTmpl[p.Template] = p
+		}
+	}
+	for _, name := range []string{"index.html", "custom.html", "tag.html", "category.html", "archive.html", "books.html"} {
+		if _, ok := byTmpl[name]; !ok {
+			t.Errorf("no planned page for %s", name)
+		}
+	}
+	if p := byTmpl["custom.html"]; p.Source != "/content/posts/hello.md" || p.Path != filepath.Join("/out", "posts", "hello-world", "index.html") || len(p.Data.Articles) != 1 {
+		t.Errorf("article page = %+v", p)
+	}
+	if p := byTmpl["books.html"]; p.Source != "" || p.Path != filepath.Join("/out", "books", "index.html") {
+		t.Errorf("virtual page = %+v", p)
+	}
+	if len(engine.calls) != 0 {
+		t.Errorf("PlannedPages rendered %v", engine.calls)
+	}
+}
+
+func TestGenerate_AssetPipeline(t *testing.T) {
+	assetsDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(assetsDir, "main.css"), []byte("body {\n  margin: 0;\n}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := model.Config{Build: model.BuildConfig{Parallelism: 2, AssetsDir: assetsDir}}
+	pipeline, err := assets.New(assetsDir, model.AssetsConfig{Minify: true, Fingerprint: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, err := pipeline.Asset("main.css")
+	if err != nil {
+		t.Fatal(err)
+	}
+	outDir := t.TempDir()
+	g := NewHTMLGenerator(outDir, &mockEngine{}, cfg)
+	g.SetAssetPipeline(pipeline)
+	site := makeSite()
+	if err := g.Generate(site, nil); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	for _, name := range []string{"main.css", filepath.Base(a.URL)} {
+		data, err := os.ReadFile(filepath.Join(outDir, "assets", name))
+		if err != nil || string(data) != "body{margin:0}" {
+			t.Errorf("assets/%s: got %q, %v", name, data, err)
+		}
+	}
+	fingerprinted := filepath.Join(outDir, "assets", filepath.Base(a.URL))
+	found := false
+	for _, p := range g.PlannedOutputs(site) {
+		found = found || p == fingerprinted
+	}
+	if !found {
+		t.Errorf("PlannedOutputs does not list %s", fingerprinted)
+	}
+}
+
+func TestGenerate_CopiesBundleResources(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "cover.png")
+	if err := os.WriteFile(src, []byte("png"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	site := makeSite()
+	site.Articles[0].Resources = []model.Resource{{Name: "images/cover.png", SourcePath: src}}
+	outDir := t.TempDir()
+	g := NewHTMLGenerator(outDir, &mockEngine{}, model.Config{Build: model.BuildConfig{Parallelism: 2}})
+	if err := g.Generate(site, nil); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	dst := filepath.Join(outDir, "posts", "hello-world", "images", "cover.png")
+	if data, err := os.ReadFile(dst); err != nil || string(data) != "png" {
+		t.Errorf("bundle resource not copied: %v", err)
+	}
+	found := false
+	for _, p := range g.PlannedOutputs(site) {
+		found = found || p == dst
+	}
+	if !found {
+		t.Errorf("PlannedOutputs does not list %s", dst)
+	}
+}
+
+func TestGenerate_MinifyHTML(t *testing.T) {
+	for _, minify := range []bool{false, true} {
+		outDir := t.TempDir()
+		g := NewHTMLGenerator(outDir, indentedEngine{}, model.Config{
+			Build: model.BuildConfig{Parallelism: 2, MinifyHTML: minify},
+		})
+		if err := g.Generate(makeSite(), nil); err != nil {
+			t.Fatalf("Generate: %v", err)
+		}
+		data, err := os.ReadFile(filepath.Join(outDir, "index.html"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := "<html>\n  <body>\n    <!-- main -->\n    <p>Hello\n      <em>world</em></p>\n    <pre>  a\n  b</pre>\n  </body>\n</html>\n"
+		if minify {
+			want = "<html><body><p>Hello <em>world</em></p><pre>  a\n  b</pre></body></html>"
+		}
+		if string(data) != want {
+			t.Errorf("minify=%v: got %q, want %q", minify, data, want)
+		}
+	}
+}
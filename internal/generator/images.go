@@ -0,0 +1,73 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/bmf-san/gohan/internal/imageproc"
+	"github.com/bmf-san/gohan/internal/model"
+)
+
+// SetImageCacheDir makes Generate keep resized images in dir (typically
+// .gohan/cache/images) and reuse them while their source is unchanged. Without
+// it every variant is resized on every build.
+func (g *HTMLGenerator) SetImageCacheDir(dir string) {
+	g.imageCacheDir = dir
+}
+
+// ImageVariants returns the responsive image variants of every article in
+// site, each listed once.
+func ImageVariants(site *model.Site) []model.ImageVariant {
+	var out []model.ImageVariant
+	seen := make(map[string]bool)
+	for _, a := range site.Articles {
+		for _, v := range a.ImageVariants {
+			if !seen[v.URL] {
+				seen[v.URL] = true
+				out = append(out, v)
+			}
+		}
+	}
+	return out
+}
+
+// imageVariantPath returns the output path of v.
+func imageVariantPath(outDir string, v model.ImageVariant) string {
+	return filepath.Join(outDir, filepath.FromSlash(strings.TrimPrefix(v.URL, "/")))
+}
+
+// writeImageVariants resizes and writes every image variant of site. Like
+// bundle resources, variants are written on every Generate because changes to
+// their source images are not tracked by the dependency graph; the image
+// cache keeps that cheap.
+func (g *HTMLGenerator) writeImageVariants(site *model.Site, parallelism int) error {
+	variants := ImageVariants(site)
+	errs := make([]error, len(variants))
+	sem := make(chan struct{}, parallelism)
+	var wg sync.WaitGroup
+	for i, v := range variants {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, v model.ImageVariant) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			dst := imageVariantPath(g.outDir, v)
+			data, err := imageproc.Resize(v, g.cfg.Images.Quality, g.imageCacheDir)
+			if err == nil {
+				err = os.MkdirAll(filepath.Dir(dst), 0o755)
+			}
+			if err == nil {
+				err = writeFileAtomic(dst, data, 0o644)
+			}
+			if err != nil {
+				errs[i] = fmt.Errorf("image %s: %w", v.URL, err)
+			}
+		}(i, v)
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
@@ -0,0 +1,65 @@
+package generator
+
+import (
+	"bytes"
+	"image"
+	"image/jpeg"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bmf-san/gohan/internal/imageproc"
+	"github.com/bmf-san/gohan/internal/model"
+)
+
+func TestGenerate_WritesImageVariants(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "cover.jpg")
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 800, 600)), nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(src, buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	img, err := imageproc.NewPlanner(model.ImagesConfig{Widths: []int{400}}).Plan(src, "/posts/hello-world/cover.jpg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	site := makeSite()
+	// The same image embedded twice is listed, and written, once.
+	site.Articles[0].ImageVariants = append(img.Variants, img.Variants...)
+
+	outDir := t.TempDir()
+	cacheDir := t.TempDir()
+	g := NewHTMLGenerator(outDir, &mockEngine{}, model.Config{
+		Build:  model.BuildConfig{Parallelism: 2},
+		Images: model.ImagesConfig{Enabled: true, Quality: 80},
+	})
+	g.SetImageCacheDir(cacheDir)
+	if err := g.Generate(site, nil); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	dst := filepath.Join(outDir, "posts", "hello-world", "cover-400w.jpg")
+	f, err := os.Open(dst)
+	if err != nil {
+		t.Fatalf("variant not written: %v", err)
+	}
+	cfg, format, err := image.DecodeConfig(f)
+	_ = f.Close()
+	if err != nil || format != "jpeg" || cfg.Width != 400 || cfg.Height != 300 {
+		t.Errorf("variant: got %s %dx%d (%v), want jpeg 400x300", format, cfg.Width, cfg.Height, err)
+	}
+	if entries, _ := os.ReadDir(cacheDir); len(entries) == 0 {
+		t.Error("variant not stored in the image cache")
+	}
+	if n := len(ImageVariants(site)); n != 1 {
+		t.Errorf("ImageVariants: got %d variants, want 1", n)
+	}
+	found := false
+	for _, p := range g.PlannedOutputs(site) {
+		found = found || p == dst
+	}
+	if !found {
+		t.Errorf("PlannedOutputs does not list %s", dst)
+	}
+}
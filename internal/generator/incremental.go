@@ -0,0 +1,242 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bmf-san/gohan/internal/model"
+	"github.com/bmf-san/gohan/internal/processor"
+)
+
+// SetDependencyGraph attaches the site's dependency graph (as returned by
+// processor.SiteProcessor.BuildDependencyGraph) to the generator. When a graph
+// is set and Generate receives a non-nil ChangeSet, only the pages affected by
+// the changed files are re-rendered; otherwise every page is regenerated.
+func (g *HTMLGenerator) SetDependencyGraph(graph *model.DependencyGraph) {
+	g.graph = graph
+}
+
+// SetPreviousDependencyGraph attaches the dependency graph recorded by the
+// previous build (see processor.GraphFromDependencyMap). With it, deletions,
+// articles moving between tags, categories, or archives, and articles
+// entering or leaving the build through draft/future filtering are rendered
+// incrementally instead of forcing a full rebuild.
+func (g *HTMLGenerator) SetPreviousDependencyGraph(graph *model.DependencyGraph) {
+	g.prevGraph = graph
+}
+
+// impactedNodes returns the set of dependency-graph node paths affected by
+// changeSet. ok is false when the change cannot be rendered incrementally and
+// every page must be regenerated instead:
+//   - no graph or no change set is available (full build);
+//   - a changed file is not an article in either graph (e.g. tags.yaml);
+//   - a tag, category, or archive appeared or disappeared, which changes the
+//     sidebar of every page. Without a previous graph this is approximated by
+//     a taxonomy referenced only by changed articles, and any deletion forces
+//     a full rebuild because the deleted article's listings are unknown.
+func (g *HTMLGenerator) impactedNodes(changeSet *model.ChangeSet) (dirty map[string]bool, ok bool) {
+	if changeSet == nil || g.graph == nil {
+		return nil, false
+	}
+	if g.prevGraph == nil && len(changeSet.DeletedFiles) > 0 {
+		return nil, false
+	}
+
+	dirty = make(map[string]bool)
+	changed := make(map[string]bool)
+	var files []string
+	files = append(files, changeSet.AddedFiles...)
+	files = append(files, changeSet.ModifiedFiles...)
+	files = append(files, changeSet.DeletedFiles...)
+	for _, rel := range files {
+		path := rel
+		if g.cfg.Build.ContentDir != "" && !filepath.IsAbs(rel) {
+			path = filepath.Join(g.cfg.Build.ContentDir, rel)
+		}
+		if !isArticleNode(g.graph, path) && !isArticleNode(g.prevGraph, path) {
+			// A Markdown file absent from both builds (a draft, a future-dated
+			// or excluded post) has no rendered page to update.
+			if g.prevGraph != nil && isMarkdown(path) {
+				continue
+			}
+			return nil, false
+		}
+		changed[path] = true
+	}
+
+	if g.prevGraph != nil {
+		diff, err := processor.CalculateDiff(g.prevGraph, g.graph)
+		if err != nil {
+			return nil, false
+		}
+		for _, p := range append(diff.AddedFiles, diff.DeletedFiles...) {
+			if isPageNode(g.graph, p) || isPageNode(g.prevGraph, p) {
+				// A page node comes and goes with its article's neighbours.
+				continue
+			}
+			if !isArticleNode(g.graph, p) && !isArticleNode(g.prevGraph, p) {
+				return nil, false
+			}
+			// Articles entering or leaving the build without a content change
+			// (draft or future-date filtering) are treated like edits.
+			changed[p] = true
+		}
+	}
+
+	for path := range changed {
+		markImpact(dirty, g.graph, path)
+		markImpact(dirty, g.prevGraph, path)
+		// Articles sharing a tag or category the changed article no longer
+		// belongs to may have listed it as related.
+		for _, tax := range droppedTaxonomies(g.prevGraph, g.graph, path) {
+			for _, p := range processor.CalculateImpact(g.prevGraph, tax) {
+				dirty[p] = true
+			}
+		}
+	}
+
+	// Any article change can shift the content scores of related articles.
+	if len(changed) > 0 {
+		dirty[processor.RelatedNode] = true
+	}
+
+	if g.prevGraph == nil {
+		for p := range dirty {
+			n, found := g.graph.Nodes[p]
+			if !found || n.Type == model.NodeTypeArticle || n.Type == model.NodeTypePage {
+				continue
+			}
+			onlyChanged := true
+			for _, dependent := range n.Dependents {
+				if !changed[dependent] {
+					onlyChanged = false
+					break
+				}
+			}
+			if onlyChanged {
+				return nil, false
+			}
+		}
+	}
+	return dirty, true
+}
+
+// isArticleNode reports whether path is an article node in graph.
+func isArticleNode(graph *model.DependencyGraph, path string) bool {
+	if graph == nil {
+		return false
+	}
+	n, ok := graph.Nodes[path]
+	return ok && n.Type == model.NodeTypeArticle
+}
+
+// isPageNode reports whether path is an article page node in graph.
+func isPageNode(graph *model.DependencyGraph, path string) bool {
+	if graph == nil {
+		return false
+	}
+	n, ok := graph.Nodes[path]
+	return ok && n.Type == model.NodeTypePage
+}
+
+// isMarkdown reports whether path has a Markdown extension recognised by the
+// content parser.
+func isMarkdown(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	return ext == ".md" || ext == ".markdown"
+}
+
+// markImpact adds the nodes impacted by a change to path in graph to dirty:
+// path itself, anything that transitively depends on it, and the listing
+// nodes (tags, categories, series, archive) it appears on.
+func markImpact(dirty map[string]bool, graph *model.DependencyGraph, path string) {
+	if graph == nil {
+		return
+	}
+	if _, ok := graph.Nodes[path]; !ok {
+		return
+	}
+	for _, p := range processor.CalculateImpact(graph, path) {
+		dirty[p] = true
+		if n, found := graph.Nodes[p]; found {
+			for _, dep := range n.Dependencies {
+				dirty[dep] = true
+			}
+		}
+	}
+}
+
+// droppedTaxonomies returns the tag, category, term, and series nodes article path
+// belonged to in prev but no longer belongs to in cur (all of them when it
+// left the build).
+func droppedTaxonomies(prev, cur *model.DependencyGraph, path string) []string {
+	if prev == nil {
+		return nil
+	}
+	old, ok := prev.Nodes[path]
+	if !ok {
+		return nil
+	}
+	now := map[string]bool{}
+	if n, found := cur.Nodes[path]; found {
+		for _, d := range n.Dependencies {
+			now[d] = true
+		}
+	}
+	var out []string
+	for _, d := range old.Dependencies {
+		if n, found := prev.Nodes[d]; found && (n.Type == model.NodeTypeTag || n.Type == model.NodeTypeCategory || n.Type == model.NodeTypeSeries || n.Type == model.NodeTypeTerm) && !now[d] {
+			out = append(out, d)
+		}
+	}
+	return out
+}
+
+// selectDirtyJobs filters jobs down to the pages that must be re-rendered for
+// the impacted node set dirty. A job is kept when its output file does not
+// exist yet, when any of its deps is impacted, or — for pages that depend on
+// the whole site (nil deps) — when anything changed at all.
+func selectDirtyJobs(jobs []writeJob, dirty map[string]bool) []writeJob {
+	var out []writeJob
+	for _, j := range jobs {
+		if _, err := os.Stat(j.path); err != nil {
+			out = append(out, j)
+			continue
+		}
+		if j.deps == nil {
+			if len(dirty) > 0 {
+				out = append(out, j)
+			}
+			continue
+		}
+		for _, d := range j.deps {
+			if dirty[d] {
+				out = append(out, j)
+				break
+			}
+		}
+	}
+	return out
+}
+
+// withDeps sets deps on every job in jobs and returns the slice.
+func withDeps(jobs []writeJob, deps ...string) []writeJob {
+	for i := range jobs {
+		jobs[i].deps = deps
+	}
+	return jobs
+}
+
+// articleDeps returns the dependency-graph node paths an article page renders
+// data from: the article itself, its related articles, its listing_slugs
+// articles, and its translations (for the language switcher).
+func articleDeps(a *model.ProcessedArticle, related, listing, translations []*model.ProcessedArticle) []string {
+	deps := []string{a.FilePath}
+	for _, group := range [][]*model.ProcessedArticle{related, listing, translations} {
+		for _, o := range group {
+			deps = append(deps, o.FilePath)
+		}
+	}
+	return deps
+}
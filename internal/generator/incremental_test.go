@@ -0,0 +1,406 @@
+package generator
+
+import (
+	"html/template"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bmf-san/gohan/internal/model"
+	"github.com/bmf-san/gohan/internal/processor"
+)
+
+// makeIncrementalSite returns a site with four articles rooted at contentDir:
+// a and b share tag "go" and category "tech"; c and d share tag "rust" and
+// category "life" and were both published in May 2023.
+func makeIncrementalSite(contentDir string) *model.Site {
+	mk := func(name, title string, tags, cats []string, date time.Time) *model.ProcessedArticle {
+		return &model.ProcessedArticle{Article: model.Article{
+			FilePath: filepath.Join(contentDir, name),
+			FrontMatter: model.FrontMatter{
+				Title: title, Slug: title, Tags: tags, Categories: cats, Date: date,
+			},
+		}, ContentPath: name}
+	}
+	return &model.Site{
+		Articles: []*model.ProcessedArticle{
+			mk("a.md", "a", []string{"go"}, []string{"tech"}, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)),
+			mk("b.md", "b", []string{"go"}, []string{"tech"}, time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)),
+			mk("c.md", "c", []string{"rust"}, []string{"life"}, time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC)),
+			mk("d.md", "d", []string{"rust"}, []string{"life"}, time.Date(2023, 5, 2, 0, 0, 0, 0, time.UTC)),
+		},
+		Tags:       []model.Taxonomy{{Name: "go"}, {Name: "rust"}},
+		Categories: []model.Taxonomy{{Name: "tech"}, {Name: "life"}},
+	}
+}
+
+func newIncrementalGenerator(t *testing.T, site *model.Site, contentDir string) (*HTMLGenerator, *mockEngine, string) {
+	t.Helper()
+	outDir := t.TempDir()
+	cfg := model.Config{Build: model.BuildConfig{Parallelism: 2, ContentDir: contentDir}}
+	eng := &mockEngine{}
+	g := NewHTMLGenerator(outDir, eng, cfg)
+	graph, err := processor.NewSiteProcessor().BuildDependencyGraph(site.Articles)
+	if err != nil {
+		t.Fatalf("BuildDependencyGraph: %v", err)
+	}
+	g.SetDependencyGraph(graph)
+	if err := g.Generate(site, nil); err != nil {
+		t.Fatalf("initial Generate: %v", err)
+	}
+	eng.calls = nil
+	return g, eng, outDir
+}
+
+func TestGenerate_Incremental_RendersOnlyImpactedPages(t *testing.T) {
+	contentDir := t.TempDir()
+	site := makeIncrementalSite(contentDir)
+	g, eng, _ := newIncrementalGenerator(t, site, contentDir)
+
+	cs := &model.ChangeSet{ModifiedFiles: []string{"c.md"}}
+	if err := g.Generate(site, cs); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	got := append([]string(nil), eng.calls...)
+	sort.Strings(got)
+	// c's article page, d's article page (c is listed as related and is its
+	// previous article), the rust tag and life category pages, the 2023/05
+	// and 2023 archives, and the index.
+	want := []string{"archive.html", "archive.html", "article.html", "article.html", "category.html", "index.html", "tag.html"}
+	if len(got) != len(want) {
+		t.Fatalf("rendered templates: got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("rendered templates: got %v, want %v", got, want)
+		}
+	}
+}
+
+func TestGenerate_Incremental_NoChanges(t *testing.T) {
+	contentDir := t.TempDir()
+	site := makeIncrementalSite(contentDir)
+	g, eng, _ := newIncrementalGenerator(t, site, contentDir)
+
+	if err := g.Generate(site, &model.ChangeSet{}); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if len(eng.calls) != 0 {
+		t.Errorf("expected no renders for an empty change set, got %v", eng.calls)
+	}
+}
+
+func TestGenerate_Incremental_RelatedArticlePagesRerendered(t *testing.T) {
+	contentDir := t.TempDir()
+	site := makeIncrementalSite(contentDir)
+	g, eng, _ := newIncrementalGenerator(t, site, contentDir)
+
+	// b shares category "tech" with a, so a's page lists b as related.
+	if err := g.Generate(site, &model.ChangeSet{ModifiedFiles: []string{"b.md"}}); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	articles := 0
+	for _, c := range eng.calls {
+		if c == "article.html" {
+			articles++
+		}
+	}
+	if articles != 2 {
+		t.Errorf("article renders: got %d, want 2 (b and its related a)", articles)
+	}
+}
+
+func TestGenerate_Incremental_ContentRelatedPagesRerendered(t *testing.T) {
+	contentDir := t.TempDir()
+	site := makeIncrementalSite(contentDir)
+	for _, a := range site.Articles {
+		a.HTMLContent = "<p>" + template.HTML(a.FrontMatter.Title) + " notes</p>"
+	}
+
+	// articleRenders edits b's body and returns how many article pages the
+	// incremental build re-renders with the related weights of cfg.
+	articleRenders := func(t *testing.T, cfg model.RelatedConfig) int {
+		t.Helper()
+		g, eng, _ := newIncrementalGenerator(t, site, contentDir)
+		g.cfg.Related = cfg
+		site.Articles[1].HTMLContent = "<p>b notes about c</p>"
+		if err := g.Generate(site, &model.ChangeSet{ModifiedFiles: []string{"b.md"}}); err != nil {
+			t.Fatalf("Generate: %v", err)
+		}
+		n := 0
+		for _, c := range eng.calls {
+			if c == "article.html" {
+				n++
+			}
+		}
+		return n
+	}
+
+	// By default the content signal is off, so c and d, which share nothing
+	// with b, are left alone: only b and a, which lists b, are re-rendered.
+	if n := articleRenders(t, model.RelatedConfig{}); n != 2 {
+		t.Errorf("default config: article renders = %d, want 2 (b and its related a)", n)
+	}
+	// With it, editing b's body changes the term weights every content score
+	// uses, so every article page is re-rendered.
+	if n := articleRenders(t, model.RelatedConfig{Weights: map[string]float64{"content": 1}}); n != 4 {
+		t.Errorf("content signal: article renders = %d, want all 4", n)
+	}
+}
+
+func TestGenerate_Incremental_SeriesPagesRerendered(t *testing.T) {
+	contentDir := t.TempDir()
+	site := makeIncrementalSite(contentDir)
+	// a and c share no tag or category, only the series.
+	site.Articles[0].FrontMatter.Series = "intro"
+	site.Articles[2].FrontMatter.Series = "intro"
+	site.Series = processor.NewSiteProcessor().BuildSeries(site.Articles)
+	g, eng, _ := newIncrementalGenerator(t, site, contentDir)
+
+	if err := g.Generate(site, &model.ChangeSet{ModifiedFiles: []string{"c.md"}}); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	articles, series := 0, 0
+	for _, c := range eng.calls {
+		switch c {
+		case "article.html":
+			articles++
+		case "series.html":
+			series++
+		}
+	}
+	if articles != 3 || series != 1 {
+		t.Errorf("renders: got %d article and %d series pages, want 3 (c, its related d, and its series part a) and 1", articles, series)
+	}
+}
+
+func TestGenerate_Incremental_TermPagesRerendered(t *testing.T) {
+	contentDir := t.TempDir()
+	site := makeIncrementalSite(contentDir)
+	// a and c share no tag or category, only the author alice.
+	site.Articles[0].Taxonomies = map[string][]string{"authors": {"alice"}}
+	site.Articles[1].Taxonomies = map[string][]string{"authors": {"bob"}}
+	site.Articles[2].Taxonomies = map[string][]string{"authors": {"alice"}}
+	site.Taxonomies = map[string][]model.Taxonomy{"authors": {{Name: "alice"}, {Name: "bob"}}}
+	cfg := model.Config{
+		Build:      model.BuildConfig{Parallelism: 2, ContentDir: contentDir},
+		Taxonomies: []model.TaxonomyConfig{{Name: "authors", Template: "term.html", IndexTemplate: "taxonomy.html"}},
+	}
+	eng := &mockEngine{}
+	g := NewHTMLGenerator(t.TempDir(), eng, cfg)
+	graph, err := processor.NewSiteProcessor().BuildDependencyGraph(site.Articles)
+	if err != nil {
+		t.Fatalf("BuildDependencyGraph: %v", err)
+	}
+	g.SetDependencyGraph(graph)
+	if err := g.Generate(site, nil); err != nil {
+		t.Fatalf("initial Generate: %v", err)
+	}
+	eng.calls = nil
+
+	if err := g.Generate(site, &model.ChangeSet{ModifiedFiles: []string{"c.md"}}); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	terms, indexes := 0, 0
+	for _, c := range eng.calls {
+		switch c {
+		case "term.html":
+			terms++
+		case "taxonomy.html":
+			indexes++
+		}
+	}
+	if terms != 1 || indexes != 1 {
+		t.Errorf("renders: got %d term and %d index pages, want 1 (alice) and 1", terms, indexes)
+	}
+}
+
+func TestGenerate_Incremental_MissingOutputRendered(t *testing.T) {
+	contentDir := t.TempDir()
+	site := makeIncrementalSite(contentDir)
+	g, eng, outDir := newIncrementalGenerator(t, site, contentDir)
+
+	if err := os.Remove(filepath.Join(outDir, "posts", "a", "index.html")); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.Generate(site, &model.ChangeSet{}); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if len(eng.calls) != 1 || eng.calls[0] != "article.html" {
+		t.Errorf("expected only the missing article page, got %v", eng.calls)
+	}
+}
+
+func TestGenerate_Incremental_FallsBackToFull(t *testing.T) {
+	contentDir := t.TempDir()
+	tests := []struct {
+		name   string
+		cs     *model.ChangeSet
+		mutate func(*model.Site)
+	}{
+		{"deleted file", &model.ChangeSet{DeletedFiles: []string{"gone.md"}}, nil},
+		{"non-article file", &model.ChangeSet{ModifiedFiles: []string{"tags.yaml"}}, nil},
+		{"taxonomy only used by changed article", &model.ChangeSet{AddedFiles: []string{"c.md"}}, func(s *model.Site) {
+			s.Articles[2].FrontMatter.Tags = append(s.Articles[2].FrontMatter.Tags, "new")
+			s.Tags = append(s.Tags, model.Taxonomy{Name: "new"})
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			site := makeIncrementalSite(contentDir)
+			if tt.mutate != nil {
+				tt.mutate(site)
+			}
+			g, eng, _ := newIncrementalGenerator(t, site, contentDir)
+			if err := g.Generate(site, tt.cs); err != nil {
+				t.Fatalf("Generate: %v", err)
+			}
+			if len(eng.calls) != len(g.buildJobs(site)) {
+				t.Errorf("expected full render (%d pages), got %d", len(g.buildJobs(site)), len(eng.calls))
+			}
+		})
+	}
+}
+
+func TestGenerate_Incremental_DeletionWithPreviousGraph(t *testing.T) {
+	contentDir := t.TempDir()
+	site := makeIncrementalSite(contentDir)
+	g, eng, _ := newIncrementalGenerator(t, site, contentDir)
+	prev := g.graph
+
+	// Delete d: c keeps tag "rust", category "life", and the 2023 archive.
+	site.Articles = site.Articles[:3]
+	graph, err := processor.NewSiteProcessor().BuildDependencyGraph(site.Articles)
+	if err != nil {
+		t.Fatalf("BuildDependencyGraph: %v", err)
+	}
+	g.SetDependencyGraph(graph)
+	g.SetPreviousDependencyGraph(prev)
+	if err := g.Generate(site, &model.ChangeSet{DeletedFiles: []string{"d.md"}}); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if len(eng.calls) == 0 || len(eng.calls) >= len(g.buildJobs(site)) {
+		t.Errorf("expected an incremental render, got %d of %d pages", len(eng.calls), len(g.buildJobs(site)))
+	}
+	for _, c := range eng.calls {
+		if c == "article.html" {
+			return // c listed d as related and must be re-rendered
+		}
+	}
+	t.Errorf("expected c's article page to be re-rendered, got %v", eng.calls)
+}
+
+func TestGenerate_Incremental_TaxonomyMoveWithPreviousGraph(t *testing.T) {
+	contentDir := t.TempDir()
+	site := makeIncrementalSite(contentDir)
+	g, eng, _ := newIncrementalGenerator(t, site, contentDir)
+	prev := g.graph
+
+	// Move b from tag "go" to "rust"; both tags still exist afterwards.
+	site.Articles[1].FrontMatter.Tags = []string{"rust"}
+	graph, err := processor.NewSiteProcessor().BuildDependencyGraph(site.Articles)
+	if err != nil {
+		t.Fatalf("BuildDependencyGraph: %v", err)
+	}
+	g.SetDependencyGraph(graph)
+	g.SetPreviousDependencyGraph(prev)
+	if err := g.Generate(site, &model.ChangeSet{ModifiedFiles: []string{"b.md"}}); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	tags := 0
+	for _, c := range eng.calls {
+		if c == "tag.html" {
+			tags++
+		}
+	}
+	if tags != 2 {
+		t.Errorf("tag renders: got %d, want 2 (old tag go and new tag rust)", tags)
+	}
+}
+
+func TestGenerate_Incremental_AdjacentMoveWithPreviousGraph(t *testing.T) {
+	contentDir := t.TempDir()
+	site := makeIncrementalSite(contentDir)
+	g, eng, _ := newIncrementalGenerator(t, site, contentDir)
+	prev := g.graph
+
+	// Move a from between d and b to before c: the order c, d, a, b
+	// becomes a, c, d, b, and the 2023 and 2024 archives both still exist.
+	site.Articles[0].FrontMatter.Date = time.Date(2023, 4, 1, 0, 0, 0, 0, time.UTC)
+	graph, err := processor.NewSiteProcessor().BuildDependencyGraph(site.Articles)
+	if err != nil {
+		t.Fatalf("BuildDependencyGraph: %v", err)
+	}
+	g.SetDependencyGraph(graph)
+	g.SetPreviousDependencyGraph(prev)
+	if err := g.Generate(site, &model.ChangeSet{ModifiedFiles: []string{"a.md"}}); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	articles := 0
+	for _, c := range eng.calls {
+		if c == "article.html" {
+			articles++
+		}
+	}
+	if articles != 4 {
+		t.Errorf("article renders: got %d, want 4 (a, its related b, old neighbour d, and new neighbour c)", articles)
+	}
+}
+
+func TestHTMLGenerator_Outputs(t *testing.T) {
+	contentDir := t.TempDir()
+	site := makeIncrementalSite(contentDir)
+	g, _, outDir := newIncrementalGenerator(t, site, contentDir)
+
+	// Outputs lists every page of the site, including pages skipped by an
+	// incremental render.
+	if err := g.Generate(site, &model.ChangeSet{}); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	got := map[string]bool{}
+	for _, p := range g.Outputs() {
+		got[p] = true
+	}
+	for _, want := range []string{
+		filepath.Join(outDir, "index.html"),
+		filepath.Join(outDir, "posts", "a", "index.html"),
+		filepath.Join(outDir, "tags", "go", "index.html"),
+	} {
+		if !got[want] {
+			t.Errorf("Outputs: missing %s", want)
+		}
+	}
+}
+
+func TestHTMLGenerator_PlannedOutputsMatchOutputs(t *testing.T) {
+	contentDir := t.TempDir()
+	assetsDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(assetsDir, "style.css"), []byte("body{}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	site := makeIncrementalSite(contentDir)
+	outDir := t.TempDir()
+	cfg := model.Config{
+		Build: model.BuildConfig{Parallelism: 2, ContentDir: contentDir, AssetsDir: assetsDir},
+		OGP:   model.OGPConfig{Enabled: true, Width: 20, Height: 10},
+	}
+	g := NewHTMLGenerator(outDir, &mockEngine{}, cfg)
+	planned := g.PlannedOutputs(site)
+	if err := g.Generate(site, nil); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	got := g.Outputs()
+	sort.Strings(planned)
+	sort.Strings(got)
+	if strings.Join(planned, "\n") != strings.Join(got, "\n") {
+		t.Fatalf("PlannedOutputs differs from Outputs:\nplanned %v\ngot     %v", planned, got)
+	}
+	for _, p := range got {
+		if _, err := os.Stat(p); err != nil {
+			t.Errorf("listed output not written: %v", err)
+		}
+	}
+}
@@ -38,6 +38,21 @@ func NewOGPGenerator(outDir, contentDir string, cfg model.OGPConfig) *OGPGenerat
 	return &OGPGenerator{outDir: outDir, contentDir: contentDir, cfg: cfg}
 }
 
+// ogpSlug returns the file name stem of a's OGP image. The slug is sanitized
+// to prevent path traversal (slugify strips dots, slashes, etc.).
+func ogpSlug(a *model.ProcessedArticle) string {
+	slug := slugify(a.FrontMatter.Slug)
+	if slug == "untitled" {
+		slug = slugify(a.FrontMatter.Title)
+	}
+	return slug
+}
+
+// ogpImagePath returns the path of a's OGP image under outDir.
+func ogpImagePath(outDir string, a *model.ProcessedArticle) string {
+	return filepath.Join(outDir, "ogp", ogpSlug(a)+".png")
+}
+
 // Generate creates one PNG per article in public/ogp/{slug}.png.
 // Articles whose output file already exists are skipped when changeSet is non-nil
 // and the article is not in the changed set.
@@ -72,11 +87,7 @@ func (g *OGPGenerator) Generate(site *model.Site, changeSet *model.ChangeSet) er
 	changed := changedSet(changeSet)
 
 	for _, a := range site.Articles {
-		// Sanitize slug to prevent path traversal (slugify strips dots, slashes, etc.).
-		slug := slugify(a.FrontMatter.Slug)
-		if slug == "untitled" {
-			slug = slugify(a.FrontMatter.Title)
-		}
+		slug := ogpSlug(a)
 		outPath := filepath.Join(ogpDir, slug+".png")
 
 		// Skip if already exists and article not in change set.
@@ -0,0 +1,307 @@
+package generator
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/bmf-san/gohan/internal/model"
+	"github.com/bmf-san/gohan/internal/processor"
+)
+
+const (
+	// defaultRelatedCount is the number of related articles per page when
+	// related.count is not set.
+	defaultRelatedCount = 5
+	// defaultRecencyHalfLife is the date distance, in days, at which the
+	// recency signal halves when related.recency_half_life is not set.
+	defaultRecencyHalfLife = 365
+)
+
+// relatedSet holds the related articles of every article of a build.
+type relatedSet struct {
+	articles map[*model.ProcessedArticle][]*model.ProcessedArticle
+	// unresolved marks articles whose related front matter names a slug
+	// that matches no article.
+	unresolved map[*model.ProcessedArticle]bool
+	// scoredByContent marks articles whose related articles were ranked
+	// with the content signal, and so depend on every article's body.
+	scoredByContent map[*model.ProcessedArticle]bool
+}
+
+// computeRelated returns the related articles of every article in all,
+// computed once per build. An article with a related front matter list gets
+// those articles in declared order; any other gets up to cfg.Count articles
+// of its locale ranked by relatedScorer. Slugs matching no article are
+// reported to warn.
+func computeRelated(all []*model.ProcessedArticle, cfg model.RelatedConfig, warn warnFunc) relatedSet {
+	set := relatedSet{
+		articles:        make(map[*model.ProcessedArticle][]*model.ProcessedArticle, len(all)),
+		unresolved:      make(map[*model.ProcessedArticle]bool),
+		scoredByContent: make(map[*model.ProcessedArticle]bool),
+	}
+	n := cfg.Count
+	if n <= 0 {
+		n = defaultRelatedCount
+	}
+
+	byLocale := map[string][]*model.ProcessedArticle{}
+	for _, a := range all {
+		byLocale[a.Locale] = append(byLocale[a.Locale], a)
+	}
+	for _, articles := range byLocale {
+		sorted := make([]*model.ProcessedArticle, len(articles))
+		copy(sorted, articles)
+		sortByDateDesc(sorted)
+		s := newRelatedScorer(sorted, cfg)
+		for i, a := range sorted {
+			if a.FrontMatter.Related != nil {
+				set.articles[a], set.unresolved[a] = resolveRelated(a, sorted, warn)
+				continue
+			}
+			set.articles[a] = s.related(i, n)
+			set.scoredByContent[a] = s.vectors != nil && s.vectors[i] != nil
+		}
+	}
+	return set
+}
+
+// resolveRelated returns the articles of src named by the related front
+// matter of a, in declared order, and whether any slug matched nothing.
+func resolveRelated(a *model.ProcessedArticle, src []*model.ProcessedArticle, warn warnFunc) ([]*model.ProcessedArticle, bool) {
+	bySlug := make(map[string]*model.ProcessedArticle, len(src))
+	for _, pa := range src {
+		bySlug[pa.FrontMatter.Slug] = pa
+	}
+	related := make([]*model.ProcessedArticle, 0, len(a.FrontMatter.Related))
+	unresolved := false
+	for _, slug := range a.FrontMatter.Related {
+		pa, ok := bySlug[slug]
+		if !ok || pa == a {
+			warn("related", fmt.Errorf("slug %q not found (locale=%q)", slug, a.Locale), "file", a.FilePath)
+			unresolved = true
+			continue
+		}
+		related = append(related, pa)
+	}
+	return related, unresolved
+}
+
+// relatedScorer scores pairs of articles of one locale. Candidates are
+// found through inverted indexes of tags, categories, and body terms, so
+// only articles sharing something with an article are scored against it.
+type relatedScorer struct {
+	articles []*model.ProcessedArticle
+	weights  map[string]float64
+	halfLife float64
+	// byTag and byCategory list the indexes of the articles with each tag
+	// and category.
+	byTag, byCategory map[string][]int
+	// vectors holds the unit TF-IDF vector of each article's body text, and
+	// postings the articles with a non-zero weight for each term; both are
+	// nil when the content signal is disabled.
+	vectors  []map[string]float64
+	postings map[string][]posting
+}
+
+// posting is the weight of a term in the TF-IDF vector of article idx.
+type posting struct {
+	idx    int
+	weight float64
+}
+
+// newRelatedScorer returns a scorer for articles, sorted newest-first, with
+// the weights of cfg.
+func newRelatedScorer(articles []*model.ProcessedArticle, cfg model.RelatedConfig) *relatedScorer {
+	s := &relatedScorer{
+		articles:   articles,
+		weights:    make(map[string]float64, len(model.RelatedSignals)),
+		halfLife:   float64(cfg.RecencyHalfLife),
+		byTag:      map[string][]int{},
+		byCategory: map[string][]int{},
+	}
+	for signal, w := range model.RelatedSignals {
+		if v, ok := cfg.Weights[signal]; ok {
+			w = v
+		}
+		s.weights[signal] = w
+	}
+	if s.halfLife <= 0 {
+		s.halfLife = defaultRecencyHalfLife
+	}
+	for i, a := range articles {
+		for _, t := range uniqueStrings(a.FrontMatter.Tags) {
+			s.byTag[t] = append(s.byTag[t], i)
+		}
+		for _, c := range uniqueStrings(a.FrontMatter.Categories) {
+			s.byCategory[c] = append(s.byCategory[c], i)
+		}
+	}
+	if s.weights["content"] > 0 {
+		s.vectors = tfidfVectors(articles)
+		s.postings = map[string][]posting{}
+		for i, v := range s.vectors {
+			for t, w := range v {
+				s.postings[t] = append(s.postings[t], posting{i, w})
+			}
+		}
+	}
+	return s
+}
+
+// related returns up to n articles related to s.articles[i], best first.
+// Articles with equal scores keep their newest-first order.
+func (s *relatedScorer) related(i, n int) []*model.ProcessedArticle {
+	// content maps every candidate sharing a tag, category, or body term
+	// with article i to the cosine similarity of their body vectors.
+	content := map[int]float64{}
+	candidate := func(j int) {
+		if _, ok := content[j]; !ok {
+			content[j] = 0
+		}
+	}
+	fm := s.articles[i].FrontMatter
+	for _, t := range fm.Tags {
+		for _, j := range s.byTag[t] {
+			candidate(j)
+		}
+	}
+	for _, c := range fm.Categories {
+		for _, j := range s.byCategory[c] {
+			candidate(j)
+		}
+	}
+	if s.vectors != nil {
+		for t, w := range s.vectors[i] {
+			for _, p := range s.postings[t] {
+				content[p.idx] += w * p.weight
+			}
+		}
+	}
+	delete(content, i)
+
+	type scored struct {
+		idx   int
+		score float64
+	}
+	candidates := make([]scored, 0, len(content))
+	for j, c := range content {
+		if score, ok := s.score(i, j, c); ok {
+			candidates = append(candidates, scored{j, score})
+		}
+	}
+	sort.Slice(candidates, func(x, y int) bool {
+		if candidates[x].score != candidates[y].score {
+			return candidates[x].score > candidates[y].score
+		}
+		return candidates[x].idx < candidates[y].idx
+	})
+	if len(candidates) > n {
+		candidates = candidates[:n]
+	}
+	var out []*model.ProcessedArticle
+	for _, c := range candidates {
+		out = append(out, s.articles[c.idx])
+	}
+	return out
+}
+
+// score returns the weighted score of article j as related to article i,
+// given the cosine similarity of their bodies, and false when they share no
+// tag, category, or content.
+func (s *relatedScorer) score(i, j int, content float64) (float64, bool) {
+	a, b := s.articles[i].FrontMatter, s.articles[j].FrontMatter
+	relevance := s.weights["tags"]*overlap(a.Tags, b.Tags) +
+		s.weights["categories"]*overlap(a.Categories, b.Categories) +
+		s.weights["content"]*content
+	if relevance <= 0 {
+		return 0, false
+	}
+	recency := 0.0
+	if !a.Date.IsZero() && !b.Date.IsZero() {
+		days := math.Abs(a.Date.Sub(b.Date).Hours()) / 24
+		recency = math.Pow(0.5, days/s.halfLife)
+	}
+	return relevance + s.weights["recency"]*recency, true
+}
+
+// uniqueStrings returns values without repeats, in first-seen order.
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	out := values[:0:0]
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// overlap returns the Jaccard index of a and b: the number of values they
+// share divided by the number of distinct values in either.
+func overlap(a, b []string) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	set := make(map[string]bool, len(a))
+	for _, v := range a {
+		set[v] = true
+	}
+	union := len(set)
+	shared := 0
+	seen := make(map[string]bool, len(b))
+	for _, v := range b {
+		if seen[v] {
+			continue
+		}
+		seen[v] = true
+		if set[v] {
+			shared++
+		} else {
+			union++
+		}
+	}
+	return float64(shared) / float64(union)
+}
+
+// tfidfVectors returns the unit-length TF-IDF vector of the body text of
+// each article, tokenized by processor.Tokenize. Term frequencies are
+// dampened logarithmically; terms found in every article carry no weight.
+// The vector of an article without body text is nil.
+func tfidfVectors(articles []*model.ProcessedArticle) []map[string]float64 {
+	freqs := make([]map[string]int, len(articles))
+	df := map[string]int{}
+	for i, a := range articles {
+		freqs[i] = map[string]int{}
+		for _, t := range processor.Tokenize(htmlText(string(a.HTMLContent))) {
+			freqs[i][t]++
+		}
+		for t := range freqs[i] {
+			df[t]++
+		}
+	}
+	n := float64(len(articles))
+	vectors := make([]map[string]float64, len(articles))
+	for i, freq := range freqs {
+		if len(freq) == 0 {
+			continue
+		}
+		v := make(map[string]float64, len(freq))
+		var norm float64
+		for t, f := range freq {
+			w := (1 + math.Log(float64(f))) * math.Log(n/float64(df[t]))
+			if w <= 0 {
+				continue
+			}
+			v[t] = w
+			norm += w * w
+		}
+		norm = math.Sqrt(norm)
+		for t := range v {
+			v[t] /= norm
+		}
+		vectors[i] = v
+	}
+	return vectors
+}
@@ -0,0 +1,127 @@
+package generator
+
+import (
+	"fmt"
+	"html/template"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/bmf-san/gohan/internal/model"
+)
+
+// relatedTitles returns the titles of articles, for comparisons.
+func relatedTitles(articles []*model.ProcessedArticle) []string {
+	out := make([]string, len(articles))
+	for i, a := range articles {
+		out[i] = a.FrontMatter.Title
+	}
+	return out
+}
+
+func TestComputeRelated(t *testing.T) {
+	t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	t1 := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	t2 := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	mk := func(title, locale string, tags, cats []string, date time.Time, body string) *model.ProcessedArticle {
+		return &model.ProcessedArticle{
+			Article: model.Article{FilePath: title + ".md", FrontMatter: model.FrontMatter{
+				Title: title, Slug: title, Tags: tags, Categories: cats, Date: date,
+			}},
+			HTMLContent: template.HTML(body),
+			Locale:      locale,
+		}
+	}
+	target := mk("Target", "en", []string{"http", "router"}, []string{"go"}, t1, "<p>routing requests</p>")
+	sameTags := mk("SameTags", "en", []string{"http", "router"}, nil, t0, "<p>middleware</p>")
+	sameCat := mk("SameCat", "en", nil, []string{"go"}, t1.AddDate(-1, 0, 0), "<p>generics</p>")
+	sameText := mk("SameText", "en", nil, nil, t2, "<p>routing requests quickly</p>")
+	otherLocale := mk("OtherLocale", "ja", []string{"http", "router"}, []string{"go"}, t1, "")
+	unrelated := mk("Unrelated", "en", []string{"rust"}, []string{"life"}, t1, "<p>gardening</p>")
+	all := []*model.ProcessedArticle{target, sameTags, sameCat, sameText, otherLocale, unrelated}
+
+	tests := []struct {
+		name string
+		cfg  model.RelatedConfig
+		of   *model.ProcessedArticle
+		want []string
+	}{
+		{"default weights leave content out", model.RelatedConfig{}, target, []string{"SameTags", "SameCat"}},
+		{"count limits the list", model.RelatedConfig{Count: 1}, target, []string{"SameTags"}},
+		{"enabled content signal", model.RelatedConfig{Weights: map[string]float64{"content": 1}}, target, []string{"SameTags", "SameCat", "SameText"}},
+		{"category weight outranks tags", model.RelatedConfig{Weights: map[string]float64{"categories": 3, "content": 1}}, target, []string{"SameCat", "SameTags", "SameText"}},
+		{"no shared signal", model.RelatedConfig{}, unrelated, nil},
+		{"locales are separate", model.RelatedConfig{}, otherLocale, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := relatedTitles(computeRelated(all, tt.cfg, ignoreWarn).articles[tt.of])
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+			for i := range tt.want {
+				if got[i] != tt.want[i] {
+					t.Fatalf("got %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+
+	t.Run("recency breaks ties", func(t *testing.T) {
+		near := mk("Near", "en", nil, []string{"go"}, t1.AddDate(0, 0, 1), "")
+		far := mk("Far", "en", nil, []string{"go"}, t1.AddDate(-2, 0, 0), "")
+		got := relatedTitles(computeRelated([]*model.ProcessedArticle{far, target, near}, model.RelatedConfig{}, ignoreWarn).articles[target])
+		if len(got) != 2 || got[0] != "Near" || got[1] != "Far" {
+			t.Errorf("got %v, want [Near Far]", got)
+		}
+	})
+}
+
+func TestComputeRelated_FrontMatterOverride(t *testing.T) {
+	a := &model.ProcessedArticle{Article: model.Article{FrontMatter: model.FrontMatter{
+		Title: "A", Slug: "a", Categories: []string{"go"}, Related: []string{"c", "missing", "b"},
+	}}}
+	b := &model.ProcessedArticle{Article: model.Article{FrontMatter: model.FrontMatter{Title: "B", Slug: "b", Categories: []string{"go"}}}}
+	c := &model.ProcessedArticle{Article: model.Article{FrontMatter: model.FrontMatter{Title: "C", Slug: "c"}}}
+	none := &model.ProcessedArticle{Article: model.Article{FrontMatter: model.FrontMatter{
+		Title: "None", Slug: "none", Categories: []string{"go"}, Related: []string{},
+	}}}
+
+	var warned []string
+	warn := func(scope string, err error, args ...any) {
+		warned = append(warned, fmt.Sprint(scope, ": ", err, " ", args))
+	}
+	set := computeRelated([]*model.ProcessedArticle{a, b, c, none}, model.RelatedConfig{Count: 1}, warn)
+	if want := `related: slug "missing" not found (locale="") [file ]`; len(warned) != 1 || warned[0] != want {
+		t.Errorf("warnings = %q, want [%q]", warned, want)
+	}
+	if got := relatedTitles(set.articles[a]); len(got) != 2 || got[0] != "C" || got[1] != "B" {
+		t.Errorf("override: got %v, want [C B] in declared order, uncapped", got)
+	}
+	if !set.unresolved[a] || set.unresolved[b] {
+		t.Errorf("unresolved: got a=%v b=%v, want true, false", set.unresolved[a], set.unresolved[b])
+	}
+	if got := set.articles[none]; len(got) != 0 {
+		t.Errorf("empty override: got %v, want none", relatedTitles(got))
+	}
+}
+
+// ignoreWarn discards build warnings.
+func ignoreWarn(string, error, ...any) {}
+
+func TestOverlap(t *testing.T) {
+	tests := []struct {
+		a, b []string
+		want float64
+	}{
+		{[]string{"go", "web"}, []string{"go"}, 0.5},
+		{[]string{"go"}, []string{"go", "go"}, 1},
+		{[]string{"go"}, []string{"rust"}, 0},
+		{nil, []string{"go"}, 0},
+	}
+	for _, tt := range tests {
+		if got := overlap(tt.a, tt.b); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("overlap(%v, %v) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -2,23 +2,51 @@ package generator
 
 import (
 	"encoding/json"
+	"fmt"
+	"html"
 	"os"
 	"path/filepath"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/bmf-san/gohan/internal/model"
+	"github.com/bmf-san/gohan/internal/processor"
 )
 
+// searchShardDir is the directory, next to search-index.json, that holds the
+// shards of the full-text index.
+const searchShardDir = "search"
+
 // searchIndex is the top-level JSON document written to search-index.json.
 // It is consumed by client-side search implemented in the theme.
 type searchIndex struct {
 	// Generated is the RFC3339 timestamp the index was written.
 	Generated string `json:"generated"`
-	// Count is the number of article entries in this index.
+	// Count is the number of entries in this index.
 	Count int `json:"count"`
-	// Articles holds one searchable record per article, newest-first.
+	// Articles holds one searchable record per article, newest-first,
+	// followed by the records contributed by plugins.
 	Articles []searchIndexEntry `json:"articles"`
+	// FullText describes the inverted index shards; present only when
+	// search.full_text is enabled.
+	FullText *fullTextIndex `json:"full_text,omitempty"`
+}
+
+// fullTextIndex locates the shards of a full-text index.
+type fullTextIndex struct {
+	// Dir is the directory of the shard files, relative to search-index.json.
+	Dir string `json:"dir"`
+	// Shards lists the shard keys, sorted; shard k is the file Dir/k.json.
+	Shards []string `json:"shards"`
+}
+
+// searchShard is one shard file of a full-text index. Terms maps each term
+// to its postings: [article, frequency] pairs in ascending article order,
+// where article is an index into the articles array of search-index.json
+// and frequency is the number of occurrences of the term in the article.
+type searchShard struct {
+	Terms map[string][][2]int `json:"terms"`
 }
 
 // searchIndexEntry is a single searchable article record.
@@ -32,6 +60,23 @@ type searchIndexEntry struct {
 	Categories  []string `json:"categories,omitempty"`
 	Date        string   `json:"date,omitempty"`
 	Locale      string   `json:"locale,omitempty"`
+	// Fields holds the front matter values selected by search.fields, or
+	// the fields of a plugin document.
+	Fields map[string]interface{} `json:"fields,omitempty"`
+	// Plugin names the plugin that contributed the record; empty for
+	// articles.
+	Plugin string `json:"plugin,omitempty"`
+	// Length is the number of terms indexed for the article, for ranking;
+	// present only in full-text mode.
+	Length int `json:"length,omitempty"`
+}
+
+// searchRecord is an index entry together with the text its full-text
+// index covers.
+type searchRecord struct {
+	entry  searchIndexEntry
+	locale string
+	text   func() string
 }
 
 // GenerateSearchIndex writes search-index.json to outDir for client-side search.
@@ -40,19 +85,40 @@ type searchIndexEntry struct {
 // tags, categories, date, locale) — no full body text — so the index stays
 // small even for large sites. Articles are sorted newest-first and the URL is
 // built the same way as feed/sitemap links (absolute when baseURL is set).
-// baseURL must not have a trailing slash.
+// baseURL must not have a trailing slash. Articles with search_exclude set in
+// their front matter are left out, and the front matter keys listed in
+// cfg.Search.Fields are copied to each entry's fields object.
+//
+// docs, contributed by plugins, are appended after the articles in the given
+// order; their URL is resolved against baseURL like an article's.
 //
 // When cfg has I18n.Locales configured, a per-locale index is written for each
 // non-default locale at {locale}/search-index.json, and the root
-// search-index.json contains only default-locale articles. Without i18n the
-// root index contains every article.
-func GenerateSearchIndex(outDir, baseURL string, articles []*model.ProcessedArticle, cfg model.Config) error {
-	sorted := make([]*model.ProcessedArticle, len(articles))
-	copy(sorted, articles)
+// search-index.json contains only default-locale articles and documents
+// without a locale. Without i18n the root index contains every record.
+//
+// When cfg.Search.FullText is set, each index also gets an inverted index of
+// the article text, tokenized by processor.Tokenize, written as shards to the
+// search directory next to it and listed in its full_text field.
+func GenerateSearchIndex(outDir, baseURL string, articles []*model.ProcessedArticle, docs []*model.SearchDocument, cfg model.Config) error {
+	sorted := make([]*model.ProcessedArticle, 0, len(articles))
+	for _, a := range articles {
+		if !a.FrontMatter.SearchExclude {
+			sorted = append(sorted, a)
+		}
+	}
 	sort.Slice(sorted, func(i, j int) bool {
 		return sorted[i].FrontMatter.Date.After(sorted[j].FrontMatter.Date)
 	})
 
+	records := make([]searchRecord, 0, len(sorted)+len(docs))
+	for _, a := range sorted {
+		records = append(records, articleSearchRecord(baseURL, a, cfg.Search.Fields))
+	}
+	for _, d := range docs {
+		records = append(records, documentSearchRecord(baseURL, d))
+	}
+
 	if err := os.MkdirAll(outDir, 0o755); err != nil {
 		return err
 	}
@@ -60,8 +126,8 @@ func GenerateSearchIndex(outDir, baseURL string, articles []*model.ProcessedArti
 	// When i18n is active, filter the root index to the default locale only and
 	// write per-locale indexes under their locale subdirectory.
 	if len(cfg.I18n.Locales) > 0 {
-		rootArticles := filterFeedArticles(sorted, cfg.I18n.DefaultLocale)
-		if err := writeSearchIndex(filepath.Join(outDir, "search-index.json"), baseURL, rootArticles); err != nil {
+		rootRecords := filterSearchRecords(records, cfg.I18n.DefaultLocale, true)
+		if err := writeSearchIndex(filepath.Join(outDir, "search-index.json"), rootRecords, cfg.Search.FullText); err != nil {
 			return err
 		}
 		for _, loc := range cfg.I18n.Locales {
@@ -72,40 +138,166 @@ func GenerateSearchIndex(outDir, baseURL string, articles []*model.ProcessedArti
 			if err := os.MkdirAll(locDir, 0o755); err != nil {
 				return err
 			}
-			locArticles := filterFeedArticles(sorted, loc)
-			if err := writeSearchIndex(filepath.Join(locDir, "search-index.json"), baseURL, locArticles); err != nil {
+			locRecords := filterSearchRecords(records, loc, false)
+			if err := writeSearchIndex(filepath.Join(locDir, "search-index.json"), locRecords, cfg.Search.FullText); err != nil {
 				return err
 			}
 		}
 		return nil
 	}
 
-	return writeSearchIndex(filepath.Join(outDir, "search-index.json"), baseURL, sorted)
+	return writeSearchIndex(filepath.Join(outDir, "search-index.json"), records, cfg.Search.FullText)
+}
+
+// articleSearchRecord returns the search record of a, with the values of
+// the front matter keys in fields.
+func articleSearchRecord(baseURL string, a *model.ProcessedArticle, fields []string) searchRecord {
+	entry := searchIndexEntry{
+		Title:       a.FrontMatter.Title,
+		URL:         articleLink(baseURL, a),
+		Description: a.FrontMatter.Description,
+		Summary:     a.Summary,
+		Tags:        a.FrontMatter.Tags,
+		Categories:  a.FrontMatter.Categories,
+		Locale:      a.Locale,
+	}
+	if !a.FrontMatter.Date.IsZero() {
+		entry.Date = a.FrontMatter.Date.UTC().Format(time.RFC3339)
+	}
+	for _, f := range fields {
+		v, ok := processor.FrontMatterValue(a.FrontMatter, f)
+		if !ok {
+			continue
+		}
+		if entry.Fields == nil {
+			entry.Fields = make(map[string]interface{}, len(fields))
+		}
+		entry.Fields[f] = v
+	}
+	return searchRecord{
+		entry:  entry,
+		locale: a.Locale,
+		text: func() string {
+			return searchText(a) + "\n" + fieldsText(entry.Fields)
+		},
+	}
+}
+
+// documentSearchRecord returns the search record of the plugin document d.
+func documentSearchRecord(baseURL string, d *model.SearchDocument) searchRecord {
+	entry := searchIndexEntry{
+		Title:       d.Title,
+		URL:         baseURL + d.URL,
+		Description: d.Description,
+		Summary:     d.Summary,
+		Tags:        d.Tags,
+		Categories:  d.Categories,
+		Locale:      d.Locale,
+		Fields:      d.Fields,
+		Plugin:      d.Plugin,
+	}
+	if !d.Date.IsZero() {
+		entry.Date = d.Date.UTC().Format(time.RFC3339)
+	}
+	return searchRecord{
+		entry:  entry,
+		locale: d.Locale,
+		text: func() string {
+			parts := []string{d.Title, d.Description, d.Summary}
+			parts = append(parts, d.Tags...)
+			parts = append(parts, d.Categories...)
+			parts = append(parts, d.Text, fieldsText(d.Fields))
+			return strings.Join(parts, "\n")
+		},
+	}
+}
+
+// filterSearchRecords returns the records of locale, plus those without a
+// locale when orEmpty is set.
+func filterSearchRecords(records []searchRecord, locale string, orEmpty bool) []searchRecord {
+	var out []searchRecord
+	for _, r := range records {
+		if r.locale == locale || (orEmpty && r.locale == "") {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// fieldsText returns the string and string list values of fields, sorted
+// by key, for the full-text index.
+func fieldsText(fields map[string]interface{}) string {
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var parts []string
+	for _, k := range keys {
+		switch v := fields[k].(type) {
+		case string:
+			parts = append(parts, v)
+		case []string:
+			parts = append(parts, v...)
+		case []interface{}:
+			for _, e := range v {
+				if s, ok := e.(string); ok {
+					parts = append(parts, s)
+				}
+			}
+		}
+	}
+	return strings.Join(parts, "\n")
 }
 
-// writeSearchIndex marshals articles into the search-index.json document at path.
-func writeSearchIndex(path, baseURL string, articles []*model.ProcessedArticle) error {
+// writeSearchIndex marshals records into the search-index.json document at
+// path and, when fullText is set, writes the shards of their full-text index.
+func writeSearchIndex(path string, records []searchRecord, fullText bool) error {
 	idx := searchIndex{
 		Generated: time.Now().UTC().Format(time.RFC3339),
-		Count:     len(articles),
-		Articles:  make([]searchIndexEntry, 0, len(articles)),
+		Count:     len(records),
+		Articles:  make([]searchIndexEntry, 0, len(records)),
 	}
-	for _, a := range articles {
-		entry := searchIndexEntry{
-			Title:       a.FrontMatter.Title,
-			URL:         articleLink(baseURL, a),
-			Description: a.FrontMatter.Description,
-			Summary:     a.Summary,
-			Tags:        a.FrontMatter.Tags,
-			Categories:  a.FrontMatter.Categories,
-			Locale:      a.Locale,
-		}
-		if !a.FrontMatter.Date.IsZero() {
-			entry.Date = a.FrontMatter.Date.UTC().Format(time.RFC3339)
+	shards := make(map[string]map[string][][2]int)
+	for i, r := range records {
+		entry := r.entry
+		if fullText {
+			terms := processor.Tokenize(r.text())
+			entry.Length = len(terms)
+			freq := make(map[string]int)
+			for _, t := range terms {
+				freq[t]++
+			}
+			for t, n := range freq {
+				key := searchShardKey(t)
+				if shards[key] == nil {
+					shards[key] = make(map[string][][2]int)
+				}
+				shards[key][t] = append(shards[key][t], [2]int{i, n})
+			}
 		}
 		idx.Articles = append(idx.Articles, entry)
 	}
 
+	if fullText {
+		idx.FullText = &fullTextIndex{Dir: searchShardDir, Shards: make([]string, 0, len(shards))}
+		dir := filepath.Join(filepath.Dir(path), searchShardDir)
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return err
+		}
+		for key, terms := range shards {
+			idx.FullText.Shards = append(idx.FullText.Shards, key)
+			data, err := json.Marshal(searchShard{Terms: terms})
+			if err != nil {
+				return err
+			}
+			if err := writeFileAtomic(filepath.Join(dir, key+".json"), data, 0o644); err != nil {
+				return err
+			}
+		}
+		sort.Strings(idx.FullText.Shards)
+	}
+
 	data, err := json.MarshalIndent(idx, "", "  ")
 	if err != nil {
 		return err
@@ -113,3 +305,80 @@ func writeSearchIndex(path, baseURL string, articles []*model.ProcessedArticle)
 	data = append(data, '\n')
 	return writeFileAtomic(path, data, 0o644)
 }
+
+// searchShardKey returns the key of the shard holding term: its first
+// character when that is an ASCII letter or digit, otherwise "u" followed
+// by the hexadecimal code point of the first character divided by 256, so
+// that each block of 256 code points (e.g. hiragana, or a slice of the CJK
+// ideographs) shares a shard.
+func searchShardKey(term string) string {
+	r := []rune(term)[0]
+	if ('a' <= r && r <= 'z') || ('0' <= r && r <= '9') {
+		return string(r)
+	}
+	return fmt.Sprintf("u%x", r>>8)
+}
+
+// searchText returns the text of a that the full-text index covers: title,
+// description, tags, categories, and the body without markup.
+func searchText(a *model.ProcessedArticle) string {
+	parts := []string{a.FrontMatter.Title, a.FrontMatter.Description}
+	parts = append(parts, a.FrontMatter.Tags...)
+	parts = append(parts, a.FrontMatter.Categories...)
+	parts = append(parts, htmlText(string(a.HTMLContent)))
+	return strings.Join(parts, "\n")
+}
+
+// htmlText returns the text content of the HTML fragment s: tags and the
+// content of script and style elements are replaced by spaces and character
+// references are decoded.
+func htmlText(s string) string {
+	var b strings.Builder
+	for {
+		i := strings.IndexByte(s, '<')
+		if i < 0 {
+			b.WriteString(s)
+			break
+		}
+		b.WriteString(s[:i])
+		b.WriteByte(' ')
+		end := strings.IndexByte(s[i:], '>')
+		if end < 0 {
+			break
+		}
+		tag := strings.ToLower(s[i : i+end])
+		s = s[i+end+1:]
+		for _, raw := range []string{"script", "style"} {
+			if strings.HasPrefix(tag, "<"+raw) {
+				if j := strings.Index(strings.ToLower(s), "</"+raw); j >= 0 {
+					s = s[j:]
+				} else {
+					s = ""
+				}
+			}
+		}
+	}
+	return html.UnescapeString(b.String())
+}
+
+// searchShardOutputs returns the shard files listed by the search index at
+// indexPath, or nil when it cannot be read or has no full-text index.
+func searchShardOutputs(indexPath string) []string {
+	data, err := os.ReadFile(indexPath)
+	if err != nil {
+		return nil
+	}
+	var idx searchIndex
+	if err := json.Unmarshal(data, &idx); err != nil || idx.FullText == nil {
+		return nil
+	}
+	dir := filepath.Join(filepath.Dir(indexPath), filepath.FromSlash(idx.FullText.Dir))
+	paths := make([]string, 0, len(idx.FullText.Shards))
+	for _, key := range idx.FullText.Shards {
+		if !filepath.IsLocal(key + ".json") {
+			continue
+		}
+		paths = append(paths, filepath.Join(dir, key+".json"))
+	}
+	return paths
+}
@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"reflect"
+	"slices"
 	"testing"
 	"time"
 
@@ -27,7 +29,7 @@ func decodeSearchIndex(t *testing.T, path string) searchIndex {
 
 func TestGenerateSearchIndex_Valid(t *testing.T) {
 	dir := t.TempDir()
-	if err := GenerateSearchIndex(dir, "https://example.com", makeArticles(), model.Config{}); err != nil {
+	if err := GenerateSearchIndex(dir, "https://example.com", makeArticles(), nil, model.Config{}); err != nil {
 		t.Fatalf("GenerateSearchIndex: %v", err)
 	}
 
@@ -48,7 +50,7 @@ func TestGenerateSearchIndex_Valid(t *testing.T) {
 
 func TestGenerateSearchIndex_Empty(t *testing.T) {
 	dir := t.TempDir()
-	if err := GenerateSearchIndex(dir, "https://example.com", nil, model.Config{}); err != nil {
+	if err := GenerateSearchIndex(dir, "https://example.com", nil, nil, model.Config{}); err != nil {
 		t.Fatalf("GenerateSearchIndex empty: %v", err)
 	}
 	idx := decodeSearchIndex(t, filepath.Join(dir, "search-index.json"))
@@ -78,7 +80,7 @@ func TestGenerateSearchIndex_Fields(t *testing.T) {
 			Locale:  "en",
 		},
 	}
-	if err := GenerateSearchIndex(dir, "https://example.com", articles, model.Config{}); err != nil {
+	if err := GenerateSearchIndex(dir, "https://example.com", articles, nil, model.Config{}); err != nil {
 		t.Fatalf("GenerateSearchIndex: %v", err)
 	}
 
@@ -133,7 +135,7 @@ func TestGenerateSearchIndex_I18n(t *testing.T) {
 	cfg.I18n.Locales = []string{"en", "ja"}
 	cfg.I18n.DefaultLocale = "en"
 
-	if err := GenerateSearchIndex(dir, "https://example.com", articles, cfg); err != nil {
+	if err := GenerateSearchIndex(dir, "https://example.com", articles, nil, cfg); err != nil {
 		t.Fatalf("GenerateSearchIndex: %v", err)
 	}
 
@@ -152,3 +154,136 @@ func TestGenerateSearchIndex_I18n(t *testing.T) {
 		t.Errorf("ja url = %q", jaIdx.Articles[0].URL)
 	}
 }
+
+func TestGenerateSearchIndex_FullText(t *testing.T) {
+	dir := t.TempDir()
+	articles := []*model.ProcessedArticle{
+		{
+			Article:     model.Article{FrontMatter: model.FrontMatter{Title: "Hello", Slug: "hello", Tags: []string{"go"}}},
+			HTMLContent: "<p>Search <em>body</em> text, body &amp; more.</p><script>var hidden = 1</script>",
+			Locale:      "en",
+		},
+		{
+			Article:     model.Article{FrontMatter: model.FrontMatter{Title: "全文検索", Slug: "search"}},
+			HTMLContent: "<p>本文を検索</p>",
+			Locale:      "ja",
+		},
+	}
+	cfg := model.Config{Search: model.SearchConfig{FullText: true}}
+	cfg.I18n.Locales = []string{"en", "ja"}
+	cfg.I18n.DefaultLocale = "en"
+	if err := GenerateSearchIndex(dir, "", articles, nil, cfg); err != nil {
+		t.Fatalf("GenerateSearchIndex: %v", err)
+	}
+
+	readShard := func(t *testing.T, path string) searchShard {
+		t.Helper()
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var s searchShard
+		if err := json.Unmarshal(data, &s); err != nil {
+			t.Fatalf("invalid shard %s: %v", path, err)
+		}
+		return s
+	}
+
+	root := decodeSearchIndex(t, filepath.Join(dir, "search-index.json"))
+	if root.FullText == nil || root.FullText.Dir != "search" {
+		t.Fatalf("full_text: got %+v", root.FullText)
+	}
+	if want := []string{"b", "g", "h", "m", "s", "t"}; !reflect.DeepEqual(root.FullText.Shards, want) {
+		t.Errorf("shards: got %v, want %v", root.FullText.Shards, want)
+	}
+	if root.Articles[0].Length != 7 { // hello go search body text body more
+		t.Errorf("length: got %d, want 7", root.Articles[0].Length)
+	}
+	b := readShard(t, filepath.Join(dir, "search", "b.json"))
+	if got := b.Terms["body"]; !reflect.DeepEqual(got, [][2]int{{0, 2}}) {
+		t.Errorf("postings of body: got %v", got)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "search", "v.json")); err == nil {
+		t.Error("script content was indexed")
+	}
+
+	ja := decodeSearchIndex(t, filepath.Join(dir, "ja", "search-index.json"))
+	if ja.FullText == nil || !reflect.DeepEqual(ja.FullText.Shards, []string{"u30", "u51", "u65", "u67", "u69"}) {
+		t.Fatalf("ja shards: got %+v", ja.FullText)
+	}
+	kanji := readShard(t, filepath.Join(dir, "ja", "search", "u69.json"))
+	if got := kanji.Terms["検索"]; !reflect.DeepEqual(got, [][2]int{{0, 2}}) {
+		t.Errorf("postings of 検索: got %v", got)
+	}
+
+	outputs := FeedOutputs(dir, cfg)
+	for _, want := range []string{filepath.Join(dir, "search", "b.json"), filepath.Join(dir, "ja", "search", "u69.json")} {
+		if !slices.Contains(outputs, want) {
+			t.Errorf("FeedOutputs does not list %s", want)
+		}
+	}
+}
+
+func TestGenerateSearchIndex_ExcludeFieldsAndDocuments(t *testing.T) {
+	dir := t.TempDir()
+	date := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	articles := []*model.ProcessedArticle{
+		{
+			Article: model.Article{FrontMatter: model.FrontMatter{
+				Title: "Hello", Slug: "hello", Date: date, Author: "alice", Series: "intro",
+				Extra: map[string]interface{}{"level": 2},
+			}},
+			URL:    "/posts/hello/",
+			Locale: "en",
+		},
+		{
+			Article: model.Article{FrontMatter: model.FrontMatter{Title: "Secret", Slug: "secret", SearchExclude: true}},
+			URL:     "/posts/secret/",
+			Locale:  "en",
+		},
+		{
+			Article: model.Article{FrontMatter: model.FrontMatter{Title: "こんにちは", Slug: "hello", Date: date}},
+			URL:     "/ja/posts/hello/",
+			Locale:  "ja",
+		},
+	}
+	docs := []*model.SearchDocument{
+		{Title: "Bookshelf", URL: "/bookshelf/", Text: "Gopher Handbook", Plugin: "bookshelf"},
+		{Title: "本棚", URL: "/ja/bookshelf/", Locale: "ja", Plugin: "bookshelf"},
+	}
+	cfg := model.Config{Search: model.SearchConfig{FullText: true, Fields: []string{"author", "series", "level", "missing"}}}
+	cfg.I18n.Locales = []string{"en", "ja"}
+	cfg.I18n.DefaultLocale = "en"
+	if err := GenerateSearchIndex(dir, "https://example.com", articles, docs, cfg); err != nil {
+		t.Fatalf("GenerateSearchIndex: %v", err)
+	}
+
+	root := decodeSearchIndex(t, filepath.Join(dir, "search-index.json"))
+	if root.Count != 2 || len(root.Articles) != 2 {
+		t.Fatalf("root: got %+v, want Hello and the en bookshelf", root.Articles)
+	}
+	hello, shelf := root.Articles[0], root.Articles[1]
+	want := map[string]interface{}{"author": "alice", "series": "intro", "level": float64(2)}
+	if hello.Title != "Hello" || !reflect.DeepEqual(hello.Fields, want) {
+		t.Errorf("article: got %q with fields %v, want Hello with %v", hello.Title, hello.Fields, want)
+	}
+	if shelf.URL != "https://example.com/bookshelf/" || shelf.Plugin != "bookshelf" || hello.Plugin != "" {
+		t.Errorf("document: got %+v", shelf)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "search", "a.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var shard searchShard
+	if err := json.Unmarshal(data, &shard); err != nil {
+		t.Fatal(err)
+	}
+	if got := shard.Terms["alice"]; !reflect.DeepEqual(got, [][2]int{{0, 1}}) {
+		t.Errorf("postings of a field value: got %v", got)
+	}
+
+	jaIdx := decodeSearchIndex(t, filepath.Join(dir, "ja", "search-index.json"))
+	if jaIdx.Count != 2 || jaIdx.Articles[1].Title != "本棚" {
+		t.Errorf("ja index: got %+v", jaIdx.Articles)
+	}
+}
@@ -0,0 +1,70 @@
+package generator
+
+import (
+	"path/filepath"
+
+	"github.com/bmf-san/gohan/internal/model"
+)
+
+// seriesPaths returns the output path prefix, relative to the output
+// directory, and the URL prefix of the listing page of s, e.g.
+// "series/go-tutorial" and "/series/go-tutorial", or "ja/series/go-tutorial"
+// and "/ja/series/go-tutorial" for a non-default locale.
+func seriesPaths(s *model.Series, cfg model.Config) (basePath, baseURLPath string) {
+	slug := tagNorm(s.Name)
+	if s.Locale == "" || s.Locale == cfg.I18n.DefaultLocale {
+		return filepath.Join("series", slug), "/series/" + slug
+	}
+	return filepath.Join(s.Locale, "series", slug), "/" + s.Locale + "/series/" + slug
+}
+
+// seriesIn returns the series in all that have a part among articles.
+func seriesIn(all []*model.Series, articles []*model.ProcessedArticle) []*model.Series {
+	if len(all) == 0 {
+		return nil
+	}
+	type key struct{ locale, name string }
+	present := make(map[key]bool)
+	for _, a := range articles {
+		if a.FrontMatter.Series != "" {
+			present[key{a.Locale, a.FrontMatter.Series}] = true
+		}
+	}
+	var out []*model.Series
+	for _, s := range all {
+		if present[key{s.Locale, s.Name}] {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// seriesPosition is the place of an article in its series.
+type seriesPosition struct {
+	series *model.Series
+	index  int // 0-based index into series.Articles
+}
+
+// seriesPositions maps every part of every series in all to its position.
+func seriesPositions(all []*model.Series) map[*model.ProcessedArticle]seriesPosition {
+	pos := make(map[*model.ProcessedArticle]seriesPosition)
+	for _, s := range all {
+		for i, a := range s.Articles {
+			pos[a] = seriesPosition{series: s, index: i}
+		}
+	}
+	return pos
+}
+
+// setSeries sets the series navigation of d, the data of the article page of
+// the part at p.
+func setSeries(d *model.Site, p seriesPosition) {
+	d.CurrentSeries = p.series
+	d.SeriesPart = p.index + 1
+	if p.index > 0 {
+		d.PrevInSeries = p.series.Articles[p.index-1]
+	}
+	if p.index+1 < len(p.series.Articles) {
+		d.NextInSeries = p.series.Articles[p.index+1]
+	}
+}
@@ -0,0 +1,129 @@
+package generator
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bmf-san/gohan/internal/model"
+	"github.com/bmf-san/gohan/internal/processor"
+)
+
+// makeSiteSeries returns an i18n site with a two-part "Go Tutorial" series
+// per locale and one standalone article.
+func makeSiteSeries() *model.Site {
+	site := makeSiteI18n()
+	now := time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC)
+	part := func(title, slug, locale string, order int) *model.ProcessedArticle {
+		return &model.ProcessedArticle{
+			Article: model.Article{FilePath: slug + ".md", FrontMatter: model.FrontMatter{
+				Title: title, Slug: slug, Date: now, Series: "Go Tutorial", SeriesOrder: order,
+			}},
+			Locale: locale,
+		}
+	}
+	site.Articles = append(site.Articles,
+		part("Part 2", "part-2", "en", 2),
+		part("Part 1", "part-1", "en", 1),
+		part("Part 1 JA", "part-1-ja", "ja", 1),
+	)
+	site.Series = processor.NewSiteProcessor().BuildSeries(site.Articles)
+	return site
+}
+
+func TestBuildJobs_Series(t *testing.T) {
+	site := makeSiteSeries()
+	g := NewHTMLGenerator(t.TempDir(), &mockEngine{}, site.Config)
+	jobs := g.buildJobs(site)
+
+	byPath := map[string]writeJob{}
+	for _, j := range jobs {
+		byPath[j.path] = j
+	}
+	job := func(rel string) writeJob {
+		t.Helper()
+		j, ok := byPath[filepath.Join(g.outDir, filepath.FromSlash(rel))]
+		if !ok {
+			t.Fatalf("no job for %s", rel)
+		}
+		return j
+	}
+
+	t.Run("article navigation", func(t *testing.T) {
+		first := job("posts/part-1/index.html").data
+		second := job("posts/part-2/index.html").data
+		if first.CurrentSeries == nil || first.CurrentSeries.URL != "/series/go-tutorial/" {
+			t.Fatalf("CurrentSeries = %+v, want URL /series/go-tutorial/", first.CurrentSeries)
+		}
+		if first.SeriesPart != 1 || first.PrevInSeries != nil || first.NextInSeries == nil || first.NextInSeries.FrontMatter.Slug != "part-2" {
+			t.Errorf("part 1: SeriesPart=%d prev=%v next=%v", first.SeriesPart, first.PrevInSeries, first.NextInSeries)
+		}
+		if second.SeriesPart != 2 || second.NextInSeries != nil || second.PrevInSeries == nil || second.PrevInSeries.FrontMatter.Slug != "part-1" {
+			t.Errorf("part 2: SeriesPart=%d prev=%v next=%v", second.SeriesPart, second.PrevInSeries, second.NextInSeries)
+		}
+		if standalone := job("posts/hello-en/index.html").data; standalone.CurrentSeries != nil {
+			t.Errorf("standalone article has series %q", standalone.CurrentSeries.Name)
+		}
+		if !containsString(job("posts/part-1/index.html").deps, processor.SeriesNode("Go Tutorial")) {
+			t.Errorf("part 1 deps %v lack the series node", job("posts/part-1/index.html").deps)
+		}
+	})
+
+	t.Run("listing pages", func(t *testing.T) {
+		en := job("series/go-tutorial/index.html")
+		if en.tmpl != "series.html" || en.data.CurrentSeries == nil || en.data.CurrentSeries.Locale != "en" {
+			t.Fatalf("en listing: tmpl=%s series=%+v", en.tmpl, en.data.CurrentSeries)
+		}
+		if got := relatedTitles(en.data.Articles); len(got) != 2 || got[0] != "Part 1" || got[1] != "Part 2" {
+			t.Errorf("en listing articles = %v, want [Part 1 Part 2]", got)
+		}
+		if !containsString(en.deps, processor.SeriesNode("Go Tutorial")) {
+			t.Errorf("en listing deps %v lack the series node", en.deps)
+		}
+		ja := job("ja/series/go-tutorial/index.html")
+		if ja.data.CurrentLocale != "ja" || len(ja.data.Articles) != 1 {
+			t.Errorf("ja listing: locale=%q articles=%v", ja.data.CurrentLocale, relatedTitles(ja.data.Articles))
+		}
+		if len(ja.data.Series) != 1 || ja.data.Series[0].Locale != "ja" {
+			t.Errorf("ja listing Series = %v, want the ja series only", ja.data.Series)
+		}
+	})
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// seriesLessEngine is a mockEngine whose theme defines no series.html.
+type seriesLessEngine struct{ mockEngine }
+
+func (e *seriesLessEngine) Has(name string) bool { return name != "series.html" }
+
+func TestGenerate_SeriesWithoutTemplate(t *testing.T) {
+	site := makeSiteSeries()
+	eng := &seriesLessEngine{}
+	g := NewHTMLGenerator(t.TempDir(), eng, site.Config)
+	var warned []string
+	g.SetWarnFunc(func(scope string, _ error, _ ...any) { warned = append(warned, scope) })
+	if err := g.Generate(site, nil); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if len(warned) != 1 || warned[0] != "series" {
+		t.Errorf("warnings = %v, want one series warning", warned)
+	}
+	for _, c := range eng.calls {
+		if c == "series.html" {
+			t.Fatal("series.html rendered although the theme does not define it")
+		}
+	}
+	for _, p := range g.Outputs() {
+		if filepath.Base(filepath.Dir(p)) == "go-tutorial" {
+			t.Errorf("series listing %s planned without a template", p)
+		}
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/bmf-san/gohan/internal/minify"
 	"github.com/bmf-san/gohan/internal/model"
 )
 
@@ -92,10 +93,8 @@ func GenerateSitemap(outDir, baseURL string, articles []*model.ProcessedArticle,
 
 		buf.WriteString("  <url>\n")
 		buf.WriteString("    <loc>" + html.EscapeString(loc) + "</loc>\n")
-		if !a.FrontMatter.LastMod.IsZero() {
-			buf.WriteString("    <lastmod>" + a.FrontMatter.LastMod.UTC().Format("2006-01-02") + "</lastmod>\n")
-		} else if !a.FrontMatter.Date.IsZero() {
-			buf.WriteString("    <lastmod>" + a.FrontMatter.Date.UTC().Format("2006-01-02") + "</lastmod>\n")
+		if lastmod := articleLastMod(a); !lastmod.IsZero() {
+			buf.WriteString("    <lastmod>" + lastmod.UTC().Format("2006-01-02") + "</lastmod>\n")
 		}
 		if len(a.Translations) > 0 {
 			// Self-referencing hreflang (recommended by Google).
@@ -129,5 +128,9 @@ func GenerateSitemap(outDir, baseURL string, articles []*model.ProcessedArticle,
 	if err := os.MkdirAll(outDir, 0o755); err != nil {
 		return err
 	}
-	return writeFileAtomic(filepath.Join(outDir, "sitemap.xml"), []byte(buf.String()), 0o644)
+	data := []byte(buf.String())
+	if cfg.Build.MinifyXML {
+		data = minify.XML(data)
+	}
+	return writeFileAtomic(filepath.Join(outDir, "sitemap.xml"), data, 0o644)
 }
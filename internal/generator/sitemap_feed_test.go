@@ -184,6 +184,77 @@ func TestGenerateSitemap_LastmodFallsBackToDate(t *testing.T) {
 	}
 }
 
+func TestGenerateSitemap_LastmodFromGitInfo(t *testing.T) {
+	date := time.Date(2020, 6, 15, 0, 0, 0, 0, time.UTC)
+	commit := time.Date(2025, 2, 3, 10, 0, 0, 0, time.UTC)
+	articles := []*model.ProcessedArticle{
+		{
+			Article: model.Article{FrontMatter: model.FrontMatter{Slug: "git", Date: date}},
+			URL:     "/posts/git/",
+			GitInfo: &model.GitInfo{LastCommitDate: commit},
+		},
+	}
+	dir := t.TempDir()
+	if err := GenerateSitemap(dir, "https://example.com", articles, nil, nil, model.Config{}); err != nil {
+		t.Fatalf("GenerateSitemap: %v", err)
+	}
+	data, _ := os.ReadFile(filepath.Join(dir, "sitemap.xml"))
+	if !strings.Contains(string(data), "<lastmod>2025-02-03</lastmod>") {
+		t.Errorf("expected last commit date, got:\n%s", data)
+	}
+
+	// An explicit lastmod still wins over git history.
+	articles[0].FrontMatter.LastMod = time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC)
+	if err := GenerateSitemap(dir, "https://example.com", articles, nil, nil, model.Config{}); err != nil {
+		t.Fatalf("GenerateSitemap: %v", err)
+	}
+	data, _ = os.ReadFile(filepath.Join(dir, "sitemap.xml"))
+	if !strings.Contains(string(data), "<lastmod>2026-03-15</lastmod>") {
+		t.Errorf("expected front matter lastmod, got:\n%s", data)
+	}
+}
+
+func TestGenerateFeeds_AtomUpdatedFromGitInfo(t *testing.T) {
+	articles := makeArticles()
+	commit := time.Date(2025, 2, 3, 10, 0, 0, 0, time.UTC)
+	articles[0].GitInfo = &model.GitInfo{LastCommitDate: commit}
+	dir := t.TempDir()
+	if err := GenerateFeeds(dir, "https://example.com", "Blog", articles, model.Config{}); err != nil {
+		t.Fatalf("GenerateFeeds: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "atom.xml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var feed atomFeed
+	if err := xml.Unmarshal(data, &feed); err != nil {
+		t.Fatalf("unmarshal atom: %v", err)
+	}
+	if feed.Updated != "2025-02-03T10:00:00Z" {
+		t.Errorf("feed updated: got %s, want the latest entry update", feed.Updated)
+	}
+	got := map[string]string{}
+	for _, e := range feed.Entries {
+		got[e.Title] = e.Updated
+	}
+	if got["Old Post"] != "2025-02-03T10:00:00Z" {
+		t.Errorf("Old Post updated: got %s, want last commit date", got["Old Post"])
+	}
+	if got["New Post"] != "2024-06-01T00:00:00Z" {
+		t.Errorf("New Post updated: got %s, want publication date", got["New Post"])
+	}
+
+	// An explicit lastmod still wins over git history.
+	articles[0].FrontMatter.LastMod = time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC)
+	if err := GenerateFeeds(dir, "https://example.com", "Blog", articles, model.Config{}); err != nil {
+		t.Fatalf("GenerateFeeds: %v", err)
+	}
+	data, _ = os.ReadFile(filepath.Join(dir, "atom.xml"))
+	if !strings.Contains(string(data), "<updated>2026-03-15T00:00:00Z</updated>") {
+		t.Errorf("expected front matter lastmod, got:\n%s", data)
+	}
+}
+
 func TestGenerateSitemap_HreflangAlternates(t *testing.T) {
 	newer := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
 	articles := []*model.ProcessedArticle{
@@ -497,6 +568,7 @@ func TestTaxonomyURLs_I18n(t *testing.T) {
 		Config:     cfg,
 		Tags:       []model.Taxonomy{{Name: "Go"}},
 		Categories: []model.Taxonomy{{Name: "Architecture"}},
+		Series:     []*model.Series{{Name: "Go Tutorial", Locale: "en"}, {Name: "Go Tutorial", Locale: "ja"}},
 		Articles: []*model.ProcessedArticle{
 			{
 				Article: model.Article{FrontMatter: model.FrontMatter{
@@ -529,6 +601,8 @@ func TestTaxonomyURLs_I18n(t *testing.T) {
 		"/ja/archives/2024/",
 		"/archives/2024/03/",
 		"/ja/archives/2024/03/",
+		"/series/go-tutorial/",
+		"/ja/series/go-tutorial/",
 	}
 	urlSet := map[string]bool{}
 	for _, u := range urls {
@@ -540,3 +614,27 @@ func TestTaxonomyURLs_I18n(t *testing.T) {
 		}
 	}
 }
+
+func TestGenerateSitemapAndFeeds_MinifyXML(t *testing.T) {
+	dir := t.TempDir()
+	cfg := model.Config{Build: model.BuildConfig{MinifyXML: true}}
+	if err := GenerateSitemap(dir, "https://example.com", makeArticles(), nil, nil, cfg); err != nil {
+		t.Fatal(err)
+	}
+	if err := GenerateFeeds(dir, "https://example.com", "Site", makeArticles(), cfg); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"sitemap.xml", "feed.xml", "atom.xml"} {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Contains(string(data), "\n") {
+			t.Errorf("%s not minified:\n%s", name, data)
+		}
+		var v interface{}
+		if err := xml.Unmarshal(data, &v); err != nil || !strings.Contains(string(data), "new-post") {
+			t.Errorf("%s: invalid or incomplete XML (%v):\n%s", name, err, data)
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package generator
+
+import (
+	"path/filepath"
+	"sort"
+
+	"github.com/bmf-san/gohan/internal/model"
+	"github.com/bmf-san/gohan/internal/processor"
+)
+
+// termPage is the listing of one term of a user-defined taxonomy in one
+// locale.
+type termPage struct {
+	taxonomy model.TaxonomyConfig
+	locale   string // empty when i18n is not configured
+	term     model.Taxonomy
+	articles []*model.ProcessedArticle // newest first
+}
+
+// taxonomyPaths returns the output path prefix, relative to the output
+// directory, and the URL prefix of the pages of the user-defined taxonomy
+// name in locale, e.g. "authors" and "/authors", or "ja/authors" and
+// "/ja/authors" for a non-default locale.
+func taxonomyPaths(name, locale string, cfg model.Config) (basePath, baseURLPath string) {
+	if locale == "" || locale == cfg.I18n.DefaultLocale {
+		return name, "/" + name
+	}
+	return filepath.Join(locale, name), "/" + locale + "/" + name
+}
+
+// termPages returns the listing of every term of every user-defined taxonomy
+// of cfg, one per locale with articles using the term when i18n is active.
+// Terms keep the order of site.Taxonomies; terms without articles are
+// skipped.
+func termPages(site *model.Site, cfg model.Config) []termPage {
+	locales := cfg.I18n.Locales
+	if len(locales) == 0 {
+		locales = []string{""}
+	}
+	var pages []termPage
+	for _, tc := range cfg.Taxonomies {
+		for _, locale := range locales {
+			for _, term := range site.Taxonomies[tc.Name] {
+				filtered := filterArticles(site.Articles, func(a *model.ProcessedArticle) bool {
+					if locale != "" && a.Locale != locale {
+						return false
+					}
+					for _, t := range a.Taxonomies[tc.Name] {
+						if t == term.Name {
+							return true
+						}
+					}
+					return false
+				})
+				if len(filtered) == 0 {
+					continue
+				}
+				sortByDateDesc(filtered)
+				pages = append(pages, termPage{taxonomy: tc, locale: locale, term: term, articles: filtered})
+			}
+		}
+	}
+	return pages
+}
+
+// termPaths returns the output path prefix and URL prefix of the listing of
+// p, e.g. "authors/alice" and "/authors/alice".
+func termPaths(p termPage, cfg model.Config) (basePath, baseURLPath string) {
+	basePath, baseURLPath = taxonomyPaths(p.taxonomy.Name, p.locale, cfg)
+	slug := tagNorm(p.term.Name)
+	return filepath.Join(basePath, slug), baseURLPath + "/" + slug
+}
+
+// taxonomyJobs returns the paginated term listings of every user-defined
+// taxonomy and the index page of each taxonomy per locale with terms.
+func (g *HTMLGenerator) taxonomyJobs(site *model.Site) []writeJob {
+	var jobs []writeJob
+	pages := termPages(site, g.cfg)
+
+	translations := make(map[string]map[string]map[string]string, len(g.cfg.Taxonomies))
+	for _, tc := range g.cfg.Taxonomies {
+		name := tc.Name
+		translations[name] = buildTaxonomyTranslations(site, g.cfg, site.Taxonomies[name], name, func(a *model.ProcessedArticle) []string { return a.Taxonomies[name] })
+	}
+
+	type index struct {
+		taxonomy model.TaxonomyConfig
+		locale   string
+	}
+	indexArticles := map[index][]*model.ProcessedArticle{}
+	indexSeen := map[index]map[*model.ProcessedArticle]bool{}
+	var indexes []index
+
+	for _, p := range pages {
+		t := p.term
+		t.Translations = taxonomyTranslationsFor(translations[p.taxonomy.Name], taxonomyTranslationKey(t), p.locale)
+		basePath, baseURLPath := termPaths(p, g.cfg)
+		termJobs := paginatedJobs(site, p.articles, g.outDir, p.taxonomy.Template, basePath, baseURLPath, g.cfg.Build.PerPage, p.locale, &t)
+		for i := range termJobs {
+			termJobs[i].data.CurrentTaxonomyName = p.taxonomy.Name
+		}
+		jobs = append(jobs, withDeps(termJobs, processor.TermNode(p.taxonomy.Name, t.Name))...)
+
+		k := index{p.taxonomy, p.locale}
+		if indexSeen[k] == nil {
+			indexSeen[k] = map[*model.ProcessedArticle]bool{}
+			indexes = append(indexes, k)
+		}
+		for _, a := range p.articles {
+			if !indexSeen[k][a] {
+				indexSeen[k][a] = true
+				indexArticles[k] = append(indexArticles[k], a)
+			}
+		}
+	}
+
+	// Index pages list every term of the locale. They depend on the whole
+	// site, as the home page does, so that a term disappearing from the
+	// build also re-renders them.
+	for _, k := range indexes {
+		articles := indexArticles[k]
+		sortByDateDesc(articles)
+		basePath, _ := taxonomyPaths(k.taxonomy.Name, k.locale, g.cfg)
+		d := siteFor(localeTaxonomyBase(site, articles), articles)
+		d.CurrentLocale = k.locale
+		d.CurrentTaxonomyName = k.taxonomy.Name
+		jobs = append(jobs, writeJob{
+			path: filepath.Join(g.outDir, basePath, "index.html"),
+			tmpl: k.taxonomy.IndexTemplate,
+			data: d,
+		})
+	}
+	return jobs
+}
+
+// termURLs returns the URL paths, with trailing slash, of the page-1 term
+// listings and the index pages of every user-defined taxonomy of cfg.
+func termURLs(site *model.Site, cfg model.Config) []string {
+	var urls []string
+	seen := map[string]bool{}
+	for _, p := range termPages(site, cfg) {
+		_, baseURLPath := termPaths(p, cfg)
+		urls = append(urls, baseURLPath+"/")
+		_, indexURLPath := taxonomyPaths(p.taxonomy.Name, p.locale, cfg)
+		if !seen[indexURLPath] {
+			seen[indexURLPath] = true
+			urls = append(urls, indexURLPath+"/")
+		}
+	}
+	return urls
+}
+
+// localeTerms returns the terms of each user-defined taxonomy of base used
+// by articles, sorted by name, with the descriptions of base.Taxonomies.
+func localeTerms(base *model.Site, articles []*model.ProcessedArticle) map[string][]model.Taxonomy {
+	var out map[string][]model.Taxonomy
+	for _, tc := range base.Config.Taxonomies {
+		desc := make(map[string]string, len(base.Taxonomies[tc.Name]))
+		for _, t := range base.Taxonomies[tc.Name] {
+			desc[t.Name] = t.Description
+		}
+		seen := map[string]bool{}
+		var terms []model.Taxonomy
+		for _, a := range articles {
+			for _, t := range a.Taxonomies[tc.Name] {
+				if !seen[t] {
+					seen[t] = true
+					terms = append(terms, model.Taxonomy{Name: t, Description: desc[t]})
+				}
+			}
+		}
+		if len(terms) == 0 {
+			continue
+		}
+		sort.Slice(terms, func(i, j int) bool { return terms[i].Name < terms[j].Name })
+		if out == nil {
+			out = make(map[string][]model.Taxonomy)
+		}
+		out[tc.Name] = terms
+	}
+	return out
+}
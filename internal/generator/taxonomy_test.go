@@ -0,0 +1,142 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bmf-san/gohan/internal/model"
+	"github.com/bmf-san/gohan/internal/processor"
+)
+
+// makeSiteTerms returns an i18n site with an "authors" taxonomy: Jane Doe
+// writes in both locales, Bob in English only.
+func makeSiteTerms() *model.Site {
+	site := makeSiteI18n()
+	site.Config.Taxonomies = []model.TaxonomyConfig{{Name: "authors", Template: "term.html", IndexTemplate: "taxonomy.html", Feed: true}}
+	now := time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC)
+	post := func(title, slug, locale string, authors ...string) *model.ProcessedArticle {
+		return &model.ProcessedArticle{
+			Article: model.Article{FilePath: slug + ".md", FrontMatter: model.FrontMatter{
+				Title: title, Slug: slug, Date: now,
+			}},
+			Locale:     locale,
+			URL:        "/" + slug + "/",
+			Taxonomies: map[string][]string{"authors": authors},
+		}
+	}
+	site.Articles = append(site.Articles,
+		post("By Jane", "by-jane", "en", "Jane Doe"),
+		post("By Both", "by-both", "en", "Jane Doe", "Bob"),
+		post("By Jane JA", "by-jane-ja", "ja", "Jane Doe"),
+	)
+	site.Taxonomies = map[string][]model.Taxonomy{
+		"authors": {{Name: "Jane Doe", Description: "Editor"}, {Name: "Bob"}},
+	}
+	return site
+}
+
+func TestBuildJobs_Taxonomies(t *testing.T) {
+	site := makeSiteTerms()
+	g := NewHTMLGenerator(t.TempDir(), &mockEngine{}, site.Config)
+	byPath := map[string]writeJob{}
+	for _, j := range g.buildJobs(site) {
+		byPath[j.path] = j
+	}
+	job := func(rel string) writeJob {
+		t.Helper()
+		j, ok := byPath[filepath.Join(g.outDir, filepath.FromSlash(rel))]
+		if !ok {
+			t.Fatalf("no job for %s", rel)
+		}
+		return j
+	}
+
+	t.Run("term pages", func(t *testing.T) {
+		en := job("authors/jane-doe/index.html")
+		if en.tmpl != "term.html" || en.data.CurrentTaxonomyName != "authors" {
+			t.Fatalf("en term: tmpl=%s taxonomy=%q", en.tmpl, en.data.CurrentTaxonomyName)
+		}
+		tax := en.data.CurrentTaxonomy
+		if tax == nil || tax.URL != "/authors/jane-doe/" || tax.Description != "Editor" {
+			t.Fatalf("CurrentTaxonomy = %+v", tax)
+		}
+		if tax.Translations["ja"] != "/ja/authors/jane-doe/" {
+			t.Errorf("Translations = %v, want ja → /ja/authors/jane-doe/", tax.Translations)
+		}
+		if len(en.data.Articles) != 2 {
+			t.Errorf("en term articles = %v, want 2", relatedTitles(en.data.Articles))
+		}
+		if !containsString(en.deps, processor.TermNode("authors", "Jane Doe")) {
+			t.Errorf("en term deps %v lack the term node", en.deps)
+		}
+		ja := job("ja/authors/jane-doe/index.html")
+		if ja.data.CurrentLocale != "ja" || len(ja.data.Articles) != 1 {
+			t.Errorf("ja term: locale=%q articles=%v", ja.data.CurrentLocale, relatedTitles(ja.data.Articles))
+		}
+		job("authors/bob/index.html")
+		if _, ok := byPath[filepath.Join(g.outDir, "ja", "authors", "bob", "index.html")]; ok {
+			t.Error("Bob has no ja articles and should have no ja page")
+		}
+	})
+
+	t.Run("index pages", func(t *testing.T) {
+		en := job("authors/index.html")
+		if en.tmpl != "taxonomy.html" || en.data.CurrentTaxonomyName != "authors" || en.deps != nil {
+			t.Fatalf("en index: tmpl=%s taxonomy=%q deps=%v", en.tmpl, en.data.CurrentTaxonomyName, en.deps)
+		}
+		terms := en.data.Taxonomies["authors"]
+		if len(terms) != 2 || terms[0].Name != "Bob" || terms[1].Description != "Editor" {
+			t.Errorf("en index terms = %v, want Bob and Jane Doe with description", terms)
+		}
+		ja := job("ja/authors/index.html")
+		if terms := ja.data.Taxonomies["authors"]; len(terms) != 1 || terms[0].Name != "Jane Doe" {
+			t.Errorf("ja index terms = %v, want Jane Doe only", terms)
+		}
+	})
+}
+
+func TestTaxonomyURLs_Terms(t *testing.T) {
+	site := makeSiteTerms()
+	urls := TaxonomyURLs(site, site.Config)
+	for _, want := range []string{"/authors/", "/authors/jane-doe/", "/authors/bob/", "/ja/authors/", "/ja/authors/jane-doe/"} {
+		if !containsString(urls, want) {
+			t.Errorf("missing %s in %v", want, urls)
+		}
+	}
+	if containsString(urls, "/ja/authors/bob/") {
+		t.Errorf("unexpected /ja/authors/bob/ in %v", urls)
+	}
+}
+
+func TestGenerateTaxonomyFeeds(t *testing.T) {
+	dir := t.TempDir()
+	site := makeSiteTerms()
+	if err := GenerateTaxonomyFeeds(dir, "https://example.com", "Blog", site, site.Config); err != nil {
+		t.Fatalf("GenerateTaxonomyFeeds: %v", err)
+	}
+	outputs := TaxonomyFeedOutputs(dir, site, site.Config)
+	if len(outputs) != 6 {
+		t.Errorf("outputs = %v, want feed.xml and atom.xml for 3 term pages", outputs)
+	}
+	for _, p := range outputs {
+		if _, err := os.Stat(p); err != nil {
+			t.Errorf("listed output not written: %v", err)
+		}
+	}
+	data, _ := os.ReadFile(filepath.Join(dir, "authors", "bob", "feed.xml"))
+	s := string(data)
+	if !strings.Contains(s, "Blog - Bob") || !strings.Contains(s, "https://example.com/authors/bob/") {
+		t.Errorf("bob feed lacks title or channel link:\n%s", s)
+	}
+	if !strings.Contains(s, "By Both") || strings.Contains(s, "By Jane<") {
+		t.Errorf("bob feed should list only Bob's articles:\n%s", s)
+	}
+
+	site.Config.Taxonomies[0].Feed = false
+	if got := TaxonomyFeedOutputs(dir, site, site.Config); len(got) != 0 {
+		t.Errorf("feed disabled: outputs = %v, want none", got)
+	}
+}
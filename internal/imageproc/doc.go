@@ -0,0 +1,2 @@
+// Package imageproc plans and generates the resized image variants behind responsive <img srcset> attributes.
+package imageproc
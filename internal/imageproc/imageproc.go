@@ -0,0 +1,188 @@
+package imageproc
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	xdraw "golang.org/x/image/draw"
+
+	"github.com/bmf-san/gohan/internal/model"
+)
+
+// CacheDir is the name of the derivative cache directory inside the build
+// cache directory (.gohan/cache/images).
+const CacheDir = "images"
+
+// Image is a source image together with the variants planned for it.
+type Image struct {
+	URL    string // site-root URL path of the original
+	Width  int    // pixel width of the original
+	Height int    // pixel height of the original
+	Hash   string // hex SHA-256 of the original
+	// Variants lists the resized copies, narrowest first. It is empty when
+	// the original is not wider than any configured width.
+	Variants []model.ImageVariant
+}
+
+// Srcset returns the value of the srcset attribute: every variant followed
+// by the original, each with its width descriptor.
+func (img *Image) Srcset() string {
+	parts := make([]string, 0, len(img.Variants)+1)
+	for _, v := range img.Variants {
+		parts = append(parts, v.URL+" "+strconv.Itoa(v.Width)+"w")
+	}
+	parts = append(parts, img.URL+" "+strconv.Itoa(img.Width)+"w")
+	return strings.Join(parts, ", ")
+}
+
+// Planner decides which variants to generate for each image. Plan reads every
+// source image once; Planner is safe for concurrent use.
+type Planner struct {
+	widths []int
+
+	mu     sync.Mutex
+	images map[string]*Image // keyed by source path and URL
+}
+
+// NewPlanner returns a Planner generating the widths in cfg.
+func NewPlanner(cfg model.ImagesConfig) *Planner {
+	widths := append([]int(nil), cfg.Widths...)
+	sort.Ints(widths)
+	return &Planner{widths: widths, images: make(map[string]*Image)}
+}
+
+// Plan returns the image stored at src and published at url, with one
+// variant per configured width narrower than the original. Images other than
+// JPEG and PNG are not processed: Plan returns nil and no error for them.
+func (p *Planner) Plan(src, url string) (*Image, error) {
+	key := src + "\x00" + url
+	p.mu.Lock()
+	img, ok := p.images[key]
+	p.mu.Unlock()
+	if ok {
+		return img, nil
+	}
+
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return nil, err
+	}
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil || (format != "jpeg" && format != "png") {
+		return nil, nil
+	}
+	sum := sha256.Sum256(data)
+	img = &Image{URL: url, Width: cfg.Width, Height: cfg.Height, Hash: hex.EncodeToString(sum[:])}
+	ext := path.Ext(url)
+	stem := strings.TrimSuffix(url, ext)
+	for _, w := range p.widths {
+		if w >= cfg.Width || (len(img.Variants) > 0 && img.Variants[len(img.Variants)-1].Width == w) {
+			continue
+		}
+		img.Variants = append(img.Variants, model.ImageVariant{
+			SourcePath: src,
+			SourceHash: img.Hash,
+			URL:        fmt.Sprintf("%s-%dw%s", stem, w, ext),
+			Width:      w,
+			Height:     max(1, (cfg.Height*w+cfg.Width/2)/cfg.Width),
+		})
+	}
+
+	p.mu.Lock()
+	p.images[key] = img
+	p.mu.Unlock()
+	return img, nil
+}
+
+// Resize returns the encoded bytes of v, in the format of its source image.
+// quality is the JPEG quality. When cacheDir is not empty the result is read
+// from, or stored in, the derivative cache there, so an unchanged image is
+// only resized once.
+func Resize(v model.ImageVariant, quality int, cacheDir string) ([]byte, error) {
+	var cachePath string
+	if cacheDir != "" {
+		cachePath = filepath.Join(cacheDir, cacheName(v, quality))
+		if data, err := os.ReadFile(cachePath); err == nil {
+			return data, nil
+		}
+	}
+
+	f, err := os.Open(v.SourcePath)
+	if err != nil {
+		return nil, err
+	}
+	src, format, err := image.Decode(f)
+	_ = f.Close()
+	if err != nil {
+		return nil, fmt.Errorf("decode %s: %w", v.SourcePath, err)
+	}
+	dst := image.NewNRGBA(image.Rect(0, 0, v.Width, v.Height))
+	xdraw.CatmullRom.Scale(dst, dst.Bounds(), src, src.Bounds(), xdraw.Src, nil)
+
+	var buf bytes.Buffer
+	if format == "png" {
+		err = png.Encode(&buf, dst)
+	} else {
+		err = jpeg.Encode(&buf, dst, &jpeg.Options{Quality: quality})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("encode %s: %w", v.URL, err)
+	}
+
+	if cachePath != "" {
+		// Failing to store a derivative is not an error: the next build
+		// resizes the image again.
+		if err := os.MkdirAll(filepath.Dir(cachePath), 0o755); err == nil {
+			_ = os.WriteFile(cachePath, buf.Bytes(), 0o644)
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// cacheName returns the cache-relative path of v encoded at quality. The
+// name depends only on the source content and the encoding parameters.
+func cacheName(v model.ImageVariant, quality int) string {
+	name := fmt.Sprintf("%s-%dx%d-q%d%s", v.SourceHash, v.Width, v.Height, quality, strings.ToLower(path.Ext(v.URL)))
+	return filepath.Join(v.SourceHash[:2], name)
+}
+
+// Prune deletes every derivative in cacheDir other than those of variants
+// encoded at quality, so that derivatives of edited or removed images do not
+// accumulate.
+func Prune(cacheDir string, variants []model.ImageVariant, quality int) error {
+	keep := make(map[string]bool, len(variants))
+	for _, v := range variants {
+		keep[cacheName(v, quality)] = true
+	}
+	err := filepath.WalkDir(cacheDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(cacheDir, p)
+		if err != nil || keep[rel] {
+			return err
+		}
+		return os.Remove(p)
+	})
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
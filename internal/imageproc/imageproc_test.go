@@ -0,0 +1,107 @@
+package imageproc
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bmf-san/gohan/internal/model"
+)
+
+func writePNG(t *testing.T, path string, w, h int) {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewNRGBA(image.Rect(0, 0, w, h))); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPlanner_Plan(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "cover.png")
+	writePNG(t, src, 1000, 500)
+
+	p := NewPlanner(model.ImagesConfig{Widths: []int{1600, 400, 800, 400}})
+	img, err := p.Plan(src, "/posts/a/cover.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if img.Width != 1000 || img.Height != 500 {
+		t.Errorf("dimensions: got %dx%d, want 1000x500", img.Width, img.Height)
+	}
+	want := []model.ImageVariant{
+		{SourcePath: src, SourceHash: img.Hash, URL: "/posts/a/cover-400w.png", Width: 400, Height: 200},
+		{SourcePath: src, SourceHash: img.Hash, URL: "/posts/a/cover-800w.png", Width: 800, Height: 400},
+	}
+	if len(img.Variants) != len(want) {
+		t.Fatalf("Variants: got %+v, want %+v", img.Variants, want)
+	}
+	for i := range want {
+		if img.Variants[i] != want[i] {
+			t.Errorf("Variants[%d]: got %+v, want %+v", i, img.Variants[i], want[i])
+		}
+	}
+	if got, want := img.Srcset(), "/posts/a/cover-400w.png 400w, /posts/a/cover-800w.png 800w, /posts/a/cover.png 1000w"; got != want {
+		t.Errorf("Srcset: got %q, want %q", got, want)
+	}
+}
+
+func TestPlanner_Plan_Unsupported(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "diagram.svg")
+	if err := os.WriteFile(src, []byte("<svg/>"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	img, err := NewPlanner(model.ImagesConfig{Widths: []int{400}}).Plan(src, "/diagram.svg")
+	if err != nil || img != nil {
+		t.Errorf("Plan: got %+v, %v, want nil, nil", img, err)
+	}
+	if _, err := NewPlanner(model.ImagesConfig{}).Plan(filepath.Join(t.TempDir(), "missing.png"), "/missing.png"); err == nil {
+		t.Error("expected error for a missing file")
+	}
+}
+
+func TestResize_Cache(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "cover.png")
+	writePNG(t, src, 1000, 500)
+	img, err := NewPlanner(model.ImagesConfig{Widths: []int{400}}).Plan(src, "/cover.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cacheDir := t.TempDir()
+	data, err := Resize(img.Variants[0], 80, cacheDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil || format != "png" || cfg.Width != 400 || cfg.Height != 200 {
+		t.Errorf("variant: got %s %dx%d (%v), want png 400x200", format, cfg.Width, cfg.Height, err)
+	}
+
+	// The second call is served from the cache, even with the source gone.
+	if err := os.Remove(src); err != nil {
+		t.Fatal(err)
+	}
+	cached, err := Resize(img.Variants[0], 80, cacheDir)
+	if err != nil || !bytes.Equal(cached, data) {
+		t.Errorf("cached variant differs (err %v)", err)
+	}
+
+	// Prune keeps only the derivatives still in use.
+	if err := Prune(cacheDir, img.Variants, 80); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(cacheDir, cacheName(img.Variants[0], 80))); err != nil {
+		t.Errorf("derivative in use was pruned: %v", err)
+	}
+	if err := Prune(cacheDir, nil, 80); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(cacheDir, cacheName(img.Variants[0], 80))); !os.IsNotExist(err) {
+		t.Errorf("unused derivative not pruned: %v", err)
+	}
+}
@@ -0,0 +1,2 @@
+// Package linkcheck verifies the links in a generated site by crawling the HTML files of the output directory.
+package linkcheck
@@ -0,0 +1,180 @@
+package linkcheck
+
+import (
+	"bytes"
+	"html"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// ref is a URL referenced by an HTML attribute.
+type ref struct {
+	URL  string
+	Line int
+}
+
+// page is what the scanner extracts from one HTML file.
+type page struct {
+	refs []ref
+	ids  map[string]bool // element ids and <a name> targets
+}
+
+// linkAttrs lists, per element, the attributes holding a URL to check.
+var linkAttrs = map[string][]string{
+	"a":      {"href"},
+	"area":   {"href"},
+	"audio":  {"src"},
+	"embed":  {"src"},
+	"iframe": {"src"},
+	"img":    {"src", "srcset"},
+	"link":   {"href"},
+	"script": {"src"},
+	"source": {"src", "srcset"},
+	"track":  {"src"},
+	"video":  {"src", "poster"},
+}
+
+// rawTextElements are elements whose content is not markup.
+var rawTextElements = map[string]bool{"script": true, "style": true, "textarea": true, "title": true}
+
+// scanHTML extracts link references and anchor targets from an HTML
+// document. It is a tolerant tokenizer, not a validating parser: comments,
+// doctypes, and the content of script and style elements are skipped, and
+// malformed markup never fails the scan.
+func scanHTML(data []byte) *page {
+	p := &page{ids: make(map[string]bool)}
+	newlines := newlineOffsets(data)
+	lineAt := func(off int) int {
+		return sort.SearchInts(newlines, off) + 1
+	}
+
+	for i := 0; i < len(data); {
+		lt := bytes.IndexByte(data[i:], '<')
+		if lt < 0 {
+			break
+		}
+		i += lt
+		rest := data[i:]
+		switch {
+		case bytes.HasPrefix(rest, []byte("<!--")):
+			end := bytes.Index(rest[4:], []byte("-->"))
+			if end < 0 {
+				return p
+			}
+			i += 4 + end + 3
+			continue
+		case len(rest) > 1 && (rest[1] == '!' || rest[1] == '?' || rest[1] == '/'):
+			end := bytes.IndexByte(rest, '>')
+			if end < 0 {
+				return p
+			}
+			i += end + 1
+			continue
+		case len(rest) < 2 || !isLetter(rest[1]):
+			i++
+			continue
+		}
+
+		// Start tag: read the element name, then its attributes.
+		j := i + 1
+		for j < len(data) && !isSpace(data[j]) && data[j] != '>' && data[j] != '/' {
+			j++
+		}
+		name := strings.ToLower(string(data[i+1 : j]))
+		wanted := linkAttrs[name]
+		for {
+			for j < len(data) && (isSpace(data[j]) || data[j] == '/') {
+				j++
+			}
+			if j >= len(data) || data[j] == '>' {
+				break
+			}
+			start := j
+			for j < len(data) && !isSpace(data[j]) && data[j] != '=' && data[j] != '>' && data[j] != '/' {
+				j++
+			}
+			attr := strings.ToLower(string(data[start:j]))
+			for j < len(data) && isSpace(data[j]) {
+				j++
+			}
+			if j >= len(data) || data[j] != '=' {
+				continue
+			}
+			j++
+			for j < len(data) && isSpace(data[j]) {
+				j++
+			}
+			var value []byte
+			if j < len(data) && (data[j] == '"' || data[j] == '\'') {
+				q := data[j]
+				end := bytes.IndexByte(data[j+1:], q)
+				if end < 0 {
+					return p
+				}
+				value = data[j+1 : j+1+end]
+				j += end + 2
+			} else {
+				vs := j
+				for j < len(data) && !isSpace(data[j]) && data[j] != '>' {
+					j++
+				}
+				value = data[vs:j]
+			}
+			v := html.UnescapeString(string(value))
+			switch {
+			case attr == "id" || (attr == "name" && name == "a"):
+				p.ids[v] = true
+			case slices.Contains(wanted, attr):
+				line := lineAt(start)
+				if attr == "srcset" {
+					for _, u := range srcsetURLs(v) {
+						p.refs = append(p.refs, ref{URL: u, Line: line})
+					}
+				} else {
+					p.refs = append(p.refs, ref{URL: strings.TrimSpace(v), Line: line})
+				}
+			}
+		}
+		i = j + 1
+
+		if rawTextElements[name] {
+			end := bytes.Index(bytes.ToLower(data[min(i, len(data)):]), []byte("</"+name))
+			if end < 0 {
+				return p
+			}
+			i += end
+		}
+	}
+	return p
+}
+
+// srcsetURLs returns the image URLs of a srcset attribute value such as
+// "a.jpg 1x, b.jpg 2x".
+func srcsetURLs(v string) []string {
+	var urls []string
+	for _, candidate := range strings.Split(v, ",") {
+		if fields := strings.Fields(candidate); len(fields) > 0 {
+			urls = append(urls, fields[0])
+		}
+	}
+	return urls
+}
+
+func newlineOffsets(data []byte) []int {
+	var offs []int
+	for i, b := range data {
+		if b == '\n' {
+			offs = append(offs, i)
+		}
+	}
+	return offs
+}
+
+func isLetter(b byte) bool {
+	return b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z'
+}
+
+func isSpace(b byte) bool {
+	return b == ' ' || b == '\t' || b == '\n' || b == '\r' || b == '\f'
+}
@@ -0,0 +1,43 @@
+package linkcheck
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestScanHTML(t *testing.T) {
+	src := []byte(`<!DOCTYPE html>
+<html><head>
+<link rel="stylesheet" href="/style.css?v=1">
+<link rel="alternate" hreflang="ja" href='https://example.com/ja/'>
+<script>var s = "<a href='/not-a-link/'>";</script>
+<!-- <a href="/commented/"> -->
+</head><body>
+<h2 id="intro">Intro</h2><a name=legacy></a>
+<a class=x href=/posts/a/#intro>A</a>
+<img src="a.png" srcset="a-1x.png 1x, a-2x.png 2x" alt="a &amp; b">
+<a href="/q?a=1&amp;b=2">Q</a>
+</body></html>`)
+	got := scanHTML(src)
+	wantRefs := []ref{
+		{URL: "/style.css?v=1", Line: 3},
+		{URL: "https://example.com/ja/", Line: 4},
+		{URL: "/posts/a/#intro", Line: 9},
+		{URL: "a.png", Line: 10},
+		{URL: "a-1x.png", Line: 10},
+		{URL: "a-2x.png", Line: 10},
+		{URL: "/q?a=1&b=2", Line: 11},
+	}
+	if !reflect.DeepEqual(got.refs, wantRefs) {
+		t.Errorf("refs =\n%+v\nwant\n%+v", got.refs, wantRefs)
+	}
+	if !got.ids["intro"] || !got.ids["legacy"] || len(got.ids) != 2 {
+		t.Errorf("ids = %v, want intro and legacy", got.ids)
+	}
+}
+
+func TestScanHTML_Malformed(t *testing.T) {
+	for _, src := range []string{"<a href=\"/x", "<!-- open", "<script>", "<a =x>", "< a>", "<"} {
+		scanHTML([]byte(src)) // must terminate without panicking
+	}
+}
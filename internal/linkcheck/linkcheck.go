@@ -0,0 +1,212 @@
+package linkcheck
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Problem kinds reported by Check.
+const (
+	KindMissingFile   = "missing-file"   // the referenced file does not exist
+	KindMissingAnchor = "missing-anchor" // the page exists but has no element with the fragment's id
+)
+
+// Options configures Check.
+type Options struct {
+	// BaseURL is the site's base URL (site.base_url). Absolute URLs on its
+	// host are checked like root-relative ones; a path component is treated
+	// as the prefix under which the output directory is served.
+	BaseURL string
+	// Anchors enables checking that fragments (#id) of links to HTML pages
+	// name an element id or <a name> on the target page.
+	Anchors bool
+	// Parallelism is the number of pages read and scanned concurrently.
+	// Values below 1 are treated as 1.
+	Parallelism int
+}
+
+// Problem is a reference that does not resolve.
+type Problem struct {
+	Page string // slash-separated path of the HTML file, relative to the output directory
+	Line int    // 1-based line of the attribute holding the reference
+	URL  string // the reference as written
+	Kind string // KindMissingFile or KindMissingAnchor
+}
+
+// Check scans every .html file under outDir and reports the href, src, and
+// srcset references (links, pagination, hreflang alternates, feeds, images,
+// scripts, stylesheets) that do not resolve to a file under outDir. A URL
+// path ending in "/" or naming a directory resolves to its index.html.
+// Links to other hosts, and non-HTTP schemes such as mailto:, are not
+// checked; the check never touches the network. Problems are sorted by page
+// and line.
+func Check(outDir string, opts Options) ([]Problem, error) {
+	var base *url.URL
+	if opts.BaseURL != "" {
+		u, err := url.Parse(opts.BaseURL)
+		if err != nil {
+			return nil, fmt.Errorf("linkcheck: parse base URL: %w", err)
+		}
+		base = u
+	}
+
+	var files []string
+	err := filepath.WalkDir(outDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && strings.EqualFold(filepath.Ext(p), ".html") {
+			rel, relErr := filepath.Rel(outDir, p)
+			if relErr != nil {
+				return relErr
+			}
+			files = append(files, filepath.ToSlash(rel))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("linkcheck: walk %s: %w", outDir, err)
+	}
+
+	pages, err := scanPages(outDir, files, opts.Parallelism)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &checker{outDir: outDir, base: base, pages: pages}
+	var problems []Problem
+	for _, f := range files {
+		for _, r := range pages[f].refs {
+			if kind, ok := c.resolve(f, r.URL, opts.Anchors); !ok {
+				problems = append(problems, Problem{Page: f, Line: r.Line, URL: r.URL, Kind: kind})
+			}
+		}
+	}
+	sort.SliceStable(problems, func(i, j int) bool {
+		if problems[i].Page != problems[j].Page {
+			return problems[i].Page < problems[j].Page
+		}
+		return problems[i].Line < problems[j].Line
+	})
+	return problems, nil
+}
+
+// scanPages reads and scans files (relative to outDir) on a bounded worker
+// pool.
+func scanPages(outDir string, files []string, parallelism int) (map[string]*page, error) {
+	if parallelism <= 0 {
+		parallelism = 1
+	}
+	scanned := make([]*page, len(files))
+	errs := make([]error, len(files))
+	sem := make(chan struct{}, parallelism)
+	var wg sync.WaitGroup
+	for i, f := range files {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, f string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			data, err := os.ReadFile(filepath.Join(outDir, filepath.FromSlash(f)))
+			if err != nil {
+				errs[i] = fmt.Errorf("linkcheck: %w", err)
+				return
+			}
+			scanned[i] = scanHTML(data)
+		}(i, f)
+	}
+	wg.Wait()
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	pages := make(map[string]*page, len(files))
+	for i, f := range files {
+		pages[f] = scanned[i]
+	}
+	return pages, nil
+}
+
+// checker resolves references found on the scanned pages.
+type checker struct {
+	outDir string
+	base   *url.URL
+	pages  map[string]*page // scanned HTML files by slash path relative to outDir
+}
+
+// resolve reports whether the reference raw found on the page from resolves,
+// and if not, why. References that are not checked resolve.
+func (c *checker) resolve(from, raw string, anchors bool) (kind string, ok bool) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return KindMissingFile, false
+	}
+	if u.Scheme != "" || u.Host != "" {
+		if c.base == nil || u.Host != c.base.Host || (u.Scheme != "" && u.Scheme != "http" && u.Scheme != "https") {
+			return "", true
+		}
+	}
+
+	var target string // slash path relative to outDir
+	switch {
+	case u.Path == "":
+		if u.Fragment == "" {
+			return "", true
+		}
+		target = from
+	case strings.HasPrefix(u.Path, "/"):
+		p := u.Path
+		if c.base != nil {
+			if prefix := strings.TrimSuffix(c.base.Path, "/"); prefix != "" && strings.HasPrefix(p+"/", prefix+"/") {
+				p = strings.TrimPrefix(p, prefix)
+			}
+		}
+		target = c.file(p)
+	default:
+		target = c.file(path.Join("/", path.Dir(from), u.Path) + trailingSlash(u.Path))
+	}
+	if target == "" {
+		return KindMissingFile, false
+	}
+
+	if anchors && u.Fragment != "" {
+		if pg, isPage := c.pages[target]; isPage && !pg.ids[u.Fragment] {
+			return KindMissingAnchor, false
+		}
+	}
+	return "", true
+}
+
+// file returns the slash path, relative to outDir, of the file served for
+// the root-relative URL path p, or "" when there is none.
+func (c *checker) file(p string) string {
+	rel := strings.TrimPrefix(path.Clean("/"+p), "/")
+	if strings.HasSuffix(p, "/") || rel == "" {
+		rel = path.Join(rel, "index.html")
+	}
+	info, err := os.Stat(filepath.Join(c.outDir, filepath.FromSlash(rel)))
+	switch {
+	case err != nil:
+		return ""
+	case info.IsDir():
+		rel = path.Join(rel, "index.html")
+		if _, err := os.Stat(filepath.Join(c.outDir, filepath.FromSlash(rel))); err != nil {
+			return ""
+		}
+	}
+	return rel
+}
+
+func trailingSlash(p string) string {
+	if strings.HasSuffix(p, "/") {
+		return "/"
+	}
+	return ""
+}
@@ -0,0 +1,80 @@
+package linkcheck
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func writeOutput(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	dir := t.TempDir()
+	writeOutput(t, dir, map[string]string{
+		"index.html": `<a href="/posts/a/">a</a>
+<a href="/posts/gone/">gone</a>
+<a href="page/2/">next</a>
+<a href="https://example.com/blog/atom.xml">feed</a>
+<a href="https://other.example/missing/">external</a>
+<a href="mailto:me@example.com">mail</a>
+<a href="/posts/a/#intro">anchor</a>
+<a href="/posts/a/#nope">bad anchor</a>
+<a href="#top">top</a>
+<img src="/blog/img/missing.png">`,
+		"page/2/index.html":  `<a href="../../">prev</a><a href="/posts/a">no slash</a>`,
+		"posts/a/index.html": `<h2 id="intro">Intro</h2><img src="cover.png"><img src="../../missing.png">`,
+		"posts/a/cover.png":  "png",
+		"atom.xml":           "<feed/>",
+		"assets/demo.html":   `<a href="/nowhere/">nowhere</a>`,
+	})
+
+	problems, err := Check(dir, Options{BaseURL: "https://example.com/blog", Parallelism: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Problem{
+		{Page: "assets/demo.html", Line: 1, URL: "/nowhere/", Kind: KindMissingFile},
+		{Page: "index.html", Line: 2, URL: "/posts/gone/", Kind: KindMissingFile},
+		{Page: "index.html", Line: 10, URL: "/blog/img/missing.png", Kind: KindMissingFile},
+		{Page: "posts/a/index.html", Line: 1, URL: "../../missing.png", Kind: KindMissingFile},
+	}
+	if !reflect.DeepEqual(problems, want) {
+		t.Errorf("problems =\n%+v\nwant\n%+v", problems, want)
+	}
+
+	problems, err = Check(dir, Options{BaseURL: "https://example.com/blog", Anchors: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var anchors []Problem
+	for _, p := range problems {
+		if p.Kind == KindMissingAnchor {
+			anchors = append(anchors, p)
+		}
+	}
+	wantAnchors := []Problem{
+		{Page: "index.html", Line: 8, URL: "/posts/a/#nope", Kind: KindMissingAnchor},
+		{Page: "index.html", Line: 9, URL: "#top", Kind: KindMissingAnchor},
+	}
+	if !reflect.DeepEqual(anchors, wantAnchors) {
+		t.Errorf("anchor problems =\n%+v\nwant\n%+v", anchors, wantAnchors)
+	}
+}
+
+func TestCheck_MissingOutputDir(t *testing.T) {
+	if _, err := Check(filepath.Join(t.TempDir(), "public"), Options{}); err == nil {
+		t.Error("expected error for missing output directory")
+	}
+}
@@ -0,0 +1,2 @@
+// Package minify removes comments and redundant whitespace from HTML, XML, CSS, and JavaScript without changing how they are parsed.
+package minify
@@ -0,0 +1,204 @@
+package minify
+
+import (
+	"bytes"
+	"strings"
+)
+
+// rawElements are the elements whose content is copied unchanged, since
+// whitespace in it is significant or it is not HTML. The content of <style>
+// is minified as CSS instead. So is the content of any element of class
+// "mermaid", whose line breaks the diagram syntax depends on.
+var rawElements = map[string]bool{"pre": true, "code": true, "textarea": true, "script": true}
+
+// blockElements are the elements around whose tags whitespace never renders,
+// so it is removed rather than collapsed to a single space. Elements that may
+// sit inside a line of text, such as <script>, <video>, or <picture>, are not
+// listed.
+var blockElements = map[string]bool{
+	"!doctype": true, "html": true, "head": true, "body": true, "title": true,
+	"meta": true, "link": true, "base": true, "style": true,
+	"br": true, "hr": true,
+	"address": true, "article": true, "aside": true, "blockquote": true,
+	"details": true, "dialog": true, "dd": true, "div": true, "dl": true,
+	"dt": true, "fieldset": true, "figcaption": true, "figure": true,
+	"footer": true, "form": true, "h1": true, "h2": true, "h3": true,
+	"h4": true, "h5": true, "h6": true, "header": true, "hgroup": true,
+	"li": true, "main": true, "nav": true, "ol": true, "p": true,
+	"pre": true, "section": true, "summary": true, "table": true,
+	"caption": true, "colgroup": true, "col": true, "thead": true,
+	"tbody": true, "tfoot": true, "tr": true, "td": true, "th": true,
+	"ul": true, "option": true, "optgroup": true,
+}
+
+// HTML returns src with comments removed and whitespace collapsed: runs of
+// whitespace become a single space, or nothing next to the tags of block
+// elements. Tags, attribute values, and the content of <pre>, <code>,
+// <textarea>, <script>, and Mermaid diagrams (class="mermaid") are copied
+// unchanged; conditional comments ("<!--[if") are kept.
+func HTML(src []byte) []byte {
+	var out bytes.Buffer
+	out.Grow(len(src))
+	space := false // whitespace was skipped since the last byte written
+	block := true  // the last thing written was the tag of a block element
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case bytes.HasPrefix(src[i:], []byte("<!--")):
+			end := len(src)
+			if j := bytes.Index(src[i+4:], []byte("-->")); j >= 0 {
+				end = i + 4 + j + 3
+			}
+			if bytes.HasPrefix(src[i:], []byte("<!--[if")) {
+				flushHTMLSpace(&out, &space, block)
+				out.Write(src[i:end])
+				block = false
+			}
+			i = end
+		case c == '<' && i+1 < len(src) && isTagStart(src[i+1]):
+			end := tagEnd(src, i)
+			tag := src[i:end]
+			name, closing := tagName(tag)
+			isBlock := blockElements[name]
+			if isBlock {
+				space = false
+			}
+			flushHTMLSpace(&out, &space, block)
+			out.Write(src[i:end])
+			block = isBlock
+			i = end
+			if closing || bytes.HasSuffix(src[i-2:i], []byte("/>")) {
+				continue
+			}
+			if rawElements[name] || name == "style" || hasClass(tag, "mermaid") {
+				content := rawEnd(src, i, name)
+				if name == "style" {
+					out.Write(CSS(src[i:content]))
+				} else {
+					out.Write(src[i:content])
+				}
+				block = block && content == i
+				i = content
+			}
+		case isSpace(c):
+			space = true
+			i++
+		default:
+			flushHTMLSpace(&out, &space, block)
+			out.WriteByte(c)
+			block = false
+			i++
+		}
+	}
+	return out.Bytes()
+}
+
+// flushHTMLSpace writes a single space for the whitespace skipped since the
+// last byte written unless it follows the tag of a block element.
+func flushHTMLSpace(out *bytes.Buffer, space *bool, block bool) {
+	if *space && !block && out.Len() > 0 {
+		out.WriteByte(' ')
+	}
+	*space = false
+}
+
+// isTagStart reports whether c, following "<", starts a tag, a closing
+// tag, or a declaration such as <!DOCTYPE html>.
+func isTagStart(c byte) bool {
+	return c == '/' || c == '!' || c == '?' || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z')
+}
+
+// tagEnd returns the index just past the tag starting at src[i], skipping
+// ">" inside quoted attribute values.
+func tagEnd(src []byte, i int) int {
+	var quote byte
+	for j := i + 1; j < len(src); j++ {
+		switch c := src[j]; {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '>':
+			return j + 1
+		}
+	}
+	return len(src)
+}
+
+// tagName returns the lower-cased element name of tag and whether it is a
+// closing tag.
+func tagName(tag []byte) (string, bool) {
+	tag = tag[1:]
+	closing := len(tag) > 0 && tag[0] == '/'
+	if closing {
+		tag = tag[1:]
+	}
+	n := 0
+	for n < len(tag) && !isSpace(tag[n]) && tag[n] != '>' && tag[n] != '/' {
+		n++
+	}
+	return strings.ToLower(string(tag[:n])), closing
+}
+
+// hasClass reports whether the class attribute of tag lists class.
+func hasClass(tag []byte, class string) bool {
+	lower := bytes.ToLower(tag)
+	for i := 0; ; {
+		j := bytes.Index(lower[i:], []byte("class"))
+		if j < 0 {
+			return false
+		}
+		i += j + len("class")
+		if !isSpace(lower[i-len("class")-1]) {
+			continue
+		}
+		k := i
+		for k < len(tag) && isSpace(tag[k]) {
+			k++
+		}
+		if k == len(tag) || tag[k] != '=' {
+			continue
+		}
+		k++
+		for k < len(tag) && isSpace(tag[k]) {
+			k++
+		}
+		var value []byte
+		if k < len(tag) && (tag[k] == '"' || tag[k] == '\'') {
+			q := tag[k]
+			end := bytes.IndexByte(tag[k+1:], q)
+			if end < 0 {
+				return false
+			}
+			value = tag[k+1 : k+1+end]
+		} else {
+			end := k
+			for end < len(tag) && !isSpace(tag[end]) && tag[end] != '>' {
+				end++
+			}
+			value = tag[k:end]
+		}
+		for _, f := range strings.Fields(string(value)) {
+			if f == class {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// rawEnd returns the index of the closing tag of the element name whose
+// content starts at src[i], or len(src) when it is not closed.
+func rawEnd(src []byte, i int, name string) int {
+	closing := []byte("</" + name)
+	for j := i; j+len(closing) <= len(src); j++ {
+		if src[j] == '<' && bytes.EqualFold(src[j:j+len(closing)], closing) {
+			if k := j + len(closing); k == len(src) || src[k] == '>' || isSpace(src[k]) {
+				return j
+			}
+		}
+	}
+	return len(src)
+}
@@ -0,0 +1,50 @@
+package minify
+
+import "testing"
+
+func TestHTML(t *testing.T) {
+	tests := []struct {
+		name, in, want string
+	}{
+		{
+			name: "block elements",
+			in:   "<!DOCTYPE html>\n<html>\n  <head>\n    <title>T</title>\n  </head>\n  <body>\n    <div>\n      <p>Hello\n        world</p>\n    </div>\n  </body>\n</html>\n",
+			want: "<!DOCTYPE html><html><head><title>T</title></head><body><div><p>Hello world</p></div></body></html>",
+		},
+		{
+			name: "inline elements keep one space",
+			in:   "<p>\n  <a href=\"/x\">x</a>  <em>y</em>\n</p>",
+			want: "<p><a href=\"/x\">x</a> <em>y</em></p>",
+		},
+		{
+			name: "comments",
+			in:   "<p>a <!-- note --> b</p><!--[if IE]><p>old</p><![endif]-->",
+			want: "<p>a b</p><!--[if IE]><p>old</p><![endif]-->",
+		},
+		{
+			name: "preserved content",
+			in:   "<pre><code>  a\n    b</code></pre>\n<p>use <code>x  =  1</code></p>\n<textarea>\n  t\n</textarea>\n<script>\n  if (a < b) { f() } // </div>\n</script>",
+			want: "<pre><code>  a\n    b</code></pre><p>use <code>x  =  1</code></p><textarea>\n  t\n</textarea> <script>\n  if (a < b) { f() } // </div>\n</script>",
+		},
+		{
+			name: "tags and styles",
+			in:   "<style>\n  a { color: red; }\n</style>\n<img alt=\"a  >  b\" src=\"x.png\" />\n<span>1 &lt; 2</span>",
+			want: "<style>a{color:red}</style><img alt=\"a  >  b\" src=\"x.png\" /> <span>1 &lt; 2</span>",
+		},
+		{
+			name: "mermaid diagrams",
+			in:   "<div class=\"mermaid\">sequenceDiagram\n  Alice->>Bob: Hi\n  Bob-->>Alice: Yo\n</div>\n<pre class='diagram mermaid'>graph TD\n  A-->B\n</pre>",
+			want: "<div class=\"mermaid\">sequenceDiagram\n  Alice->>Bob: Hi\n  Bob-->>Alice: Yo\n</div><pre class='diagram mermaid'>graph TD\n  A-->B\n</pre>",
+		},
+		{
+			name: "inline-level elements keep surrounding spaces",
+			in:   "<p>Hello <script>x()</script> world, see <video src=\"a.mp4\"></video> here\n<picture> <source srcset=\"a.webp\"> <img src=\"a.png\"> </picture></p>",
+			want: "<p>Hello <script>x()</script> world, see <video src=\"a.mp4\"></video> here <picture> <source srcset=\"a.webp\"> <img src=\"a.png\"> </picture></p>",
+		},
+	}
+	for _, tt := range tests {
+		if got := string(HTML([]byte(tt.in))); got != tt.want {
+			t.Errorf("%s:\ngot  %q\nwant %q", tt.name, got, tt.want)
+		}
+	}
+}
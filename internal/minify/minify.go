@@ -0,0 +1,309 @@
+package minify
+
+import (
+	"bytes"
+	"strings"
+)
+
+// The minifiers below are deliberately conservative: they remove comments
+// and whitespace only where doing so can never change how a browser parses
+// the file, and leave everything else, such as identifiers and numbers,
+// untouched. Comments starting with "/*!" (typically licenses) are kept.
+
+// cssTight lists the characters around which whitespace is insignificant in
+// CSS. "(" and ":" are not in it: "and (" and "a :hover" mean something
+// else without the space.
+const cssTight = "{};,>"
+
+// CSS returns src without comments and redundant whitespace.
+func CSS(src []byte) []byte {
+	var out bytes.Buffer
+	space := false // whitespace was skipped since the last byte written
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == '"' || c == '\'':
+			end := quotedEnd(src, i)
+			flushCSSSpace(&out, &space, c)
+			out.Write(src[i:end])
+			i = end
+		case c == '/' && i+1 < len(src) && src[i+1] == '*':
+			end := commentEnd(src, i)
+			if i+2 < len(src) && src[i+2] == '!' {
+				flushCSSSpace(&out, &space, c)
+				out.Write(src[i:end])
+			} else {
+				space = true
+			}
+			i = end
+		case isSpace(c):
+			space = true
+			i++
+		default:
+			if c == '}' {
+				// The last declaration of a block needs no semicolon.
+				if b := out.Bytes(); len(b) > 0 && b[len(b)-1] == ';' {
+					out.Truncate(len(b) - 1)
+				}
+			}
+			flushCSSSpace(&out, &space, c)
+			out.WriteByte(c)
+			i++
+		}
+	}
+	return out.Bytes()
+}
+
+// flushCSSSpace writes the whitespace skipped before next unless it is
+// insignificant.
+func flushCSSSpace(out *bytes.Buffer, space *bool, next byte) {
+	if !*space {
+		return
+	}
+	*space = false
+	b := out.Bytes()
+	if len(b) == 0 || bytes.HasSuffix(b, []byte("*/")) || strings.IndexByte(cssTight, next) >= 0 || next == ')' {
+		return
+	}
+	if prev := b[len(b)-1]; strings.IndexByte(cssTight, prev) >= 0 || prev == ':' || prev == '(' {
+		return
+	}
+	out.WriteByte(' ')
+}
+
+// jsTight lists the characters around which whitespace within a line is
+// insignificant in JavaScript. Operators that can be doubled or start a
+// comment ("+", "-", "/", "*", "<", "!", ".") are not in it.
+const jsTight = "{}()[];,=:&|?"
+
+// jsRegexPrefix lists the characters after which "/" starts a regular
+// expression literal rather than a division.
+const jsRegexPrefix = "(,=:[!&|?{};+-*%~^<>"
+
+// jsRegexKeywords are the keywords after which "/" starts a regular
+// expression literal.
+var jsRegexKeywords = []string{"return", "typeof", "instanceof", "in", "of", "new", "delete", "void", "throw", "case", "do", "else", "yield", "await"}
+
+// JS returns src without comments and redundant whitespace. Line
+// breaks are kept wherever automatic semicolon insertion might depend on
+// them.
+func JS(src []byte) []byte {
+	m := &jsMinifier{src: src}
+	m.code(false)
+	return m.out.Bytes()
+}
+
+type jsMinifier struct {
+	src []byte
+	i   int
+	out bytes.Buffer
+	// space and newline record whitespace skipped since the last byte
+	// written.
+	space, newline bool
+}
+
+// code minifies JavaScript code until the end of the input or, when inTemplate
+// is set, until the "}" closing a template literal substitution.
+func (m *jsMinifier) code(inTemplate bool) {
+	depth := 0
+	for m.i < len(m.src) {
+		c := m.src[m.i]
+		switch {
+		case c == '"' || c == '\'':
+			end := quotedEnd(m.src, m.i)
+			m.emit(m.src[m.i:end])
+			m.i = end
+		case c == '`':
+			m.template()
+		case c == '/' && m.i+1 < len(m.src) && m.src[m.i+1] == '/':
+			for m.i < len(m.src) && m.src[m.i] != '\n' {
+				m.i++
+			}
+		case c == '/' && m.i+1 < len(m.src) && m.src[m.i+1] == '*':
+			end := commentEnd(m.src, m.i)
+			if m.i+2 < len(m.src) && m.src[m.i+2] == '!' {
+				m.emit(m.src[m.i:end])
+			} else if bytes.IndexByte(m.src[m.i:end], '\n') >= 0 {
+				m.newline = true
+			} else {
+				m.space = true
+			}
+			m.i = end
+		case c == '/' && m.regexAllowed():
+			end := regexEnd(m.src, m.i)
+			m.emit(m.src[m.i:end])
+			m.i = end
+		case c == '\n':
+			m.newline = true
+			m.i++
+		case isSpace(c):
+			m.space = true
+			m.i++
+		default:
+			if inTemplate {
+				if c == '{' {
+					depth++
+				} else if c == '}' {
+					if depth == 0 {
+						m.flush(c)
+						return
+					}
+					depth--
+				}
+			}
+			m.emit([]byte{c})
+			m.i++
+		}
+	}
+}
+
+// template copies a template literal, minifying the code of its
+// substitutions.
+func (m *jsMinifier) template() {
+	m.flush('`')
+	start := m.i
+	m.i++ // opening backtick
+	for m.i < len(m.src) {
+		switch m.src[m.i] {
+		case '\\':
+			m.i += 2
+			continue
+		case '`':
+			m.i++
+			m.out.Write(m.src[start:m.i])
+			return
+		case '$':
+			if m.i+1 < len(m.src) && m.src[m.i+1] == '{' {
+				m.i += 2
+				m.out.Write(m.src[start:m.i])
+				m.code(true)
+				if m.i < len(m.src) {
+					m.out.WriteByte('}')
+					m.i++
+				}
+				start = m.i
+				continue
+			}
+		}
+		m.i++
+	}
+	m.out.Write(m.src[start:min(m.i, len(m.src))])
+}
+
+// emit writes b after the whitespace skipped before it, if significant.
+func (m *jsMinifier) emit(b []byte) {
+	m.flush(b[0])
+	m.out.Write(b)
+}
+
+func (m *jsMinifier) flush(next byte) {
+	space, newline := m.space, m.newline
+	m.space, m.newline = false, false
+	out := m.out.Bytes()
+	if len(out) == 0 || (!space && !newline) {
+		return
+	}
+	prev := out[len(out)-1]
+	if newline {
+		// A line break after an opening bracket or a separator, or before
+		// a closing bracket or a separator, never ends a statement.
+		if strings.IndexByte("{([,;", prev) >= 0 || strings.IndexByte("})],;", next) >= 0 {
+			return
+		}
+		m.out.WriteByte('\n')
+		return
+	}
+	if strings.IndexByte(jsTight, prev) >= 0 || strings.IndexByte(jsTight, next) >= 0 {
+		return
+	}
+	m.out.WriteByte(' ')
+}
+
+// regexAllowed reports whether a "/" at the current position starts a
+// regular expression literal, judging by the code written before it.
+func (m *jsMinifier) regexAllowed() bool {
+	out := m.out.Bytes()
+	if len(out) == 0 {
+		return true
+	}
+	prev := out[len(out)-1]
+	if strings.IndexByte(jsRegexPrefix, prev) >= 0 {
+		return true
+	}
+	if !isIdentByte(prev) {
+		return false
+	}
+	start := len(out)
+	for start > 0 && isIdentByte(out[start-1]) {
+		start--
+	}
+	word := string(out[start:])
+	for _, k := range jsRegexKeywords {
+		if word == k {
+			return true
+		}
+	}
+	return false
+}
+
+// quotedEnd returns the index just past the string literal starting at
+// src[i], or len(src) when it is not terminated.
+func quotedEnd(src []byte, i int) int {
+	q := src[i]
+	for j := i + 1; j < len(src); j++ {
+		switch src[j] {
+		case '\\':
+			j++
+		case q, '\n':
+			return j + 1
+		}
+	}
+	return len(src)
+}
+
+// commentEnd returns the index just past the block comment starting at
+// src[i].
+func commentEnd(src []byte, i int) int {
+	if end := bytes.Index(src[i+2:], []byte("*/")); end >= 0 {
+		return i + 2 + end + 2
+	}
+	return len(src)
+}
+
+// regexEnd returns the index just past the regular expression literal,
+// including its flags, starting at src[i].
+func regexEnd(src []byte, i int) int {
+	class := false
+	j := i + 1
+	for ; j < len(src); j++ {
+		c := src[j]
+		if c == '\\' {
+			j++
+			continue
+		}
+		if c == '\n' {
+			return j
+		}
+		if c == '[' {
+			class = true
+		} else if c == ']' {
+			class = false
+		} else if c == '/' && !class {
+			j++
+			break
+		}
+	}
+	for j < len(src) && isIdentByte(src[j]) {
+		j++
+	}
+	return min(j, len(src))
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f'
+}
+
+func isIdentByte(c byte) bool {
+	return c == '_' || c == '$' || c >= 0x80 ||
+		('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9')
+}
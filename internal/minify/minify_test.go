@@ -0,0 +1,72 @@
+package minify
+
+import "testing"
+
+func TestMinifyCSS(t *testing.T) {
+	tests := []struct {
+		name, in, want string
+	}{
+		{
+			name: "whitespace and comments",
+			in:   "/* header */\nbody {\n  margin: 0;\n  color: #333;\n}\n\na , b > c {\n  padding: 1px 2px;\n}\n",
+			want: "body{margin:0;color:#333}a,b>c{padding:1px 2px}",
+		},
+		{
+			name: "significant spaces",
+			in:   "a :hover { width: calc(100% - 2px) }\n@media screen and (min-width: 600px) { a { b: c } }",
+			want: "a :hover{width:calc(100% - 2px)}@media screen and (min-width:600px){a{b:c}}",
+		},
+		{
+			name: "strings and license comments",
+			in:   "/*! keep me */\na::before { content: \"  /* not a comment */  \"; }",
+			want: "/*! keep me */a::before{content:\"  /* not a comment */  \"}",
+		},
+	}
+	for _, tt := range tests {
+		if got := string(CSS([]byte(tt.in))); got != tt.want {
+			t.Errorf("%s:\ngot  %q\nwant %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestMinifyJS(t *testing.T) {
+	tests := []struct {
+		name, in, want string
+	}{
+		{
+			name: "whitespace and comments",
+			in:   "// setup\nfunction add ( a, b ) {\n  /* sum */\n  return a + b;\n}\n",
+			want: "function add(a,b){return a + b;}",
+		},
+		{
+			name: "line breaks kept for ASI",
+			in:   "let a = 1\nlet b = a\n++b\n",
+			want: "let a=1\nlet b=a\n++b",
+		},
+		{
+			name: "operators that must stay apart",
+			in:   "x = a - -b; y = a + +b; z = 1 .toString()",
+			want: "x=a - -b;y=a + +b;z=1 .toString()",
+		},
+		{
+			name: "strings",
+			in:   "s = \"a  // b\" + 'c /* d */'",
+			want: "s=\"a  // b\" + 'c /* d */'",
+		},
+		{
+			name: "regular expressions",
+			in:   "if (/\\/\\/ x/.test(s)) { r = s.replace(/[/]  +/g, ' ') }\nq = a / b / c",
+			want: "if(/\\/\\/ x/.test(s)){r=s.replace(/[/]  +/g,' ')}\nq=a / b / c",
+		},
+		{
+			name: "template literals",
+			in:   "t = `a  ${ f( x ) }  // b ${ {k: 1}.k }`",
+			want: "t=`a  ${f(x)}  // b ${{k:1}.k}`",
+		},
+	}
+	for _, tt := range tests {
+		if got := string(JS([]byte(tt.in))); got != tt.want {
+			t.Errorf("%s:\ngot  %q\nwant %q", tt.name, got, tt.want)
+		}
+	}
+}
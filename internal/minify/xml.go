@@ -0,0 +1,44 @@
+package minify
+
+import "bytes"
+
+// XML returns src with comments and whitespace-only text between tags
+// removed, such as the indentation of a sitemap or feed. Text containing
+// anything but whitespace, CDATA sections, and tags are copied unchanged.
+func XML(src []byte) []byte {
+	var out bytes.Buffer
+	out.Grow(len(src))
+	for i := 0; i < len(src); {
+		switch {
+		case bytes.HasPrefix(src[i:], []byte("<!--")):
+			i = xmlSectionEnd(src, i, "-->")
+		case bytes.HasPrefix(src[i:], []byte("<![CDATA[")):
+			end := xmlSectionEnd(src, i, "]]>")
+			out.Write(src[i:end])
+			i = end
+		case src[i] == '<':
+			end := tagEnd(src, i)
+			out.Write(src[i:end])
+			i = end
+		default:
+			end := i
+			for end < len(src) && src[end] != '<' {
+				end++
+			}
+			if len(bytes.TrimSpace(src[i:end])) > 0 {
+				out.Write(src[i:end])
+			}
+			i = end
+		}
+	}
+	return out.Bytes()
+}
+
+// xmlSectionEnd returns the index just past the first terminator after
+// src[i], or len(src) when there is none.
+func xmlSectionEnd(src []byte, i int, terminator string) int {
+	if j := bytes.Index(src[i:], []byte(terminator)); j >= 0 {
+		return i + j + len(terminator)
+	}
+	return len(src)
+}
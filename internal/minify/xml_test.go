@@ -0,0 +1,11 @@
+package minify
+
+import "testing"
+
+func TestXML(t *testing.T) {
+	in := "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n<!-- generated -->\n<feed>\n  <title>A &amp; B</title>\n  <summary type=\"html\">  &lt;p&gt;x&lt;/p&gt;  </summary>\n  <content><![CDATA[\n  <p>y</p>\n]]></content>\n</feed>\n"
+	want := "<?xml version=\"1.0\" encoding=\"UTF-8\"?><feed><title>A &amp; B</title><summary type=\"html\">  &lt;p&gt;x&lt;/p&gt;  </summary><content><![CDATA[\n  <p>y</p>\n]]></content></feed>"
+	if got := string(XML([]byte(in))); got != want {
+		t.Errorf("got  %q\nwant %q", got, want)
+	}
+}
@@ -39,9 +39,72 @@ type ProcessedArticle struct {
 	WordCount int
 	// ReadingTime is the estimated reading time in minutes, with a minimum of 1.
 	ReadingTime int
+	// Taxonomies holds the terms of each user-defined taxonomy the article
+	// assigns in its front matter, keyed by taxonomy name. Access in
+	// templates: {{index .Taxonomies "authors"}}
+	Taxonomies map[string][]string
 	// TOC is the hierarchical table of contents extracted from the article's
 	// Markdown headings. Empty when the article has no headings.
 	TOC []TOCEntry
+	// GitInfo holds the git history of the source file. nil unless
+	// build.git_info is enabled and the file has been committed.
+	GitInfo *GitInfo
+	// Resources lists the files of the article's page bundle, sorted by
+	// Name. Empty unless the article is a leaf bundle (an index.md in its
+	// own directory, e.g. content/posts/my-post/index.md).
+	Resources []Resource
+	// ImageVariants lists the resized copies of the images in HTMLContent
+	// that the <img> srcset attributes refer to. Empty unless images.enabled
+	// is set.
+	ImageVariants []ImageVariant
+}
+
+// Resource is a file stored in a page bundle next to its index.md, or in a
+// subdirectory of the bundle. Resources are copied next to the article's
+// index.html, so relative links such as ![](cover.png) keep working.
+type Resource struct {
+	// Name is the slash-separated path relative to the bundle directory,
+	// e.g. "cover.png" or "images/diagram.svg".
+	Name string
+	// URL is the site-root URL path of the copied file, e.g.
+	// "/posts/my-post/cover.png".
+	URL string
+	// MediaType is the MIME type derived from the file extension, e.g.
+	// "image/png". Empty when the extension is unknown.
+	MediaType string
+	// SourcePath is the path of the file in the content directory.
+	SourcePath string
+}
+
+// ImageVariant is a resized copy of a JPEG or PNG image, generated at build
+// time for a responsive srcset.
+type ImageVariant struct {
+	// SourcePath is the path of the original image.
+	SourcePath string
+	// SourceHash is the hex SHA-256 of the original image; it keys the
+	// derivative cache.
+	SourceHash string
+	// URL is the site-root URL path of the variant, e.g.
+	// "/posts/my-post/cover-480w.jpg".
+	URL string
+	// Width and Height are the pixel dimensions of the variant.
+	Width  int
+	Height int
+}
+
+// GitInfo describes the git history of an article's source file.
+type GitInfo struct {
+	// Hash is the full hash of the last commit that touched the file.
+	Hash string
+	// AbbreviatedHash is the first 7 characters of Hash.
+	AbbreviatedHash string
+	// AuthorName and AuthorEmail identify the author of that commit.
+	AuthorName  string
+	AuthorEmail string
+	// FirstCommitDate is the author date of the commit that added the file.
+	FirstCommitDate time.Time
+	// LastCommitDate is the author date of the last commit that touched the file.
+	LastCommitDate time.Time
 }
 
 // TOCEntry represents a single Markdown heading in the table of contents.
@@ -84,7 +147,39 @@ type FrontMatter struct {
 	// listing pages.  When non-empty the generator resolves these slugs and
 	// exposes them via Site.ListingArticles for template rendering.
 	ListingSlugs []string `yaml:"listing_slugs"`
+	// Series names the series the article is a part of; articles of the
+	// same locale with the same value form one series.
+	Series string `yaml:"series"`
+	// SeriesOrder is the position of the article in its series. Parts
+	// without one follow the numbered parts, oldest first.
+	SeriesOrder int `yaml:"series_order"`
+	// Related names the slugs of the articles to list as related, in order,
+	// instead of the scored ones. An empty list lists none.
+	Related []string `yaml:"related"`
+	// SearchExclude leaves the article out of search-index.json.
+	SearchExclude bool `yaml:"search_exclude"`
 	// Extra captures any front-matter keys not listed above.
 	// Plugins read their configuration from this field.
 	Extra map[string]interface{} `yaml:",inline"`
 }
+
+// BuiltinFieldTypes maps each front matter key decoded into a FrontMatter
+// field to its FieldSchema type.
+var BuiltinFieldTypes = map[string]string{
+	"title":           "string",
+	"date":            "date",
+	"lastmod":         "date",
+	"draft":           "bool",
+	"tags":            "list",
+	"categories":      "list",
+	"description":     "string",
+	"author":          "string",
+	"slug":            "string",
+	"template":        "string",
+	"translation_key": "string",
+	"listing_slugs":   "list",
+	"series":          "string",
+	"series_order":    "int",
+	"related":         "list",
+	"search_exclude":  "bool",
+}
@@ -7,8 +7,19 @@ type Config struct {
 	Theme           ThemeConfig            `yaml:"theme"`
 	SyntaxHighlight SyntaxHighlightConfig  `yaml:"syntax_highlight"`
 	OGP             OGPConfig              `yaml:"ogp"`
+	Images          ImagesConfig           `yaml:"images"`
+	Assets          AssetsConfig           `yaml:"assets"`
+	Search          SearchConfig           `yaml:"search"`
+	Related         RelatedConfig          `yaml:"related"`
+	Taxonomies      []TaxonomyConfig       `yaml:"taxonomies"`
 	Plugins         map[string]interface{} `yaml:"plugins"`
 	I18n            I18nConfig             `yaml:"i18n"`
+	Check           CheckConfig            `yaml:"check"`
+	// ContentSchema declares the front matter of each content section, keyed
+	// by section name: the first directory under the content directory (after
+	// the locale directory when i18n is enabled), e.g. "posts" or "pages".
+	// The key "*" applies to every section without its own entry.
+	ContentSchema map[string]SectionSchema `yaml:"content_schema"`
 }
 
 // SiteConfig holds site-wide metadata.
@@ -34,6 +45,34 @@ type BuildConfig struct {
 	ExcludeFiles []string `yaml:"exclude_files"`
 	Parallelism  int      `yaml:"parallelism"`
 	PerPage      int      `yaml:"per_page"`
+	// GitInfo derives each article's first and last commit dates, last
+	// author, and commit hash from git history (see ProcessedArticle.GitInfo).
+	GitInfo bool `yaml:"git_info"`
+	// EnforceSchema fails the build when an article violates ContentSchema.
+	// `gohan check` always reports violations.
+	EnforceSchema bool `yaml:"enforce_schema"`
+	// MinifyHTML removes comments and collapses whitespace in rendered
+	// pages, leaving the content of <pre>, <code>, <textarea>, and <script>
+	// untouched.
+	MinifyHTML bool `yaml:"minify_html"`
+	// MinifyXML removes comments and indentation from sitemap.xml and the
+	// RSS and Atom feeds.
+	MinifyXML bool `yaml:"minify_xml"`
+	// Precompress writes gzip and brotli variants of text outputs next to
+	// them, for servers and CDNs that serve precompressed files.
+	Precompress PrecompressConfig `yaml:"precompress"`
+}
+
+// PrecompressConfig selects the precompressed variants written for HTML,
+// CSS, JS, JSON, XML, and SVG outputs.
+type PrecompressConfig struct {
+	// Gzip writes a ".gz" variant of each file.
+	Gzip bool `yaml:"gzip"`
+	// Brotli writes a ".br" variant of each file.
+	Brotli bool `yaml:"brotli"`
+	// MinSize is the size in bytes below which files are not compressed
+	// (default 1024).
+	MinSize int `yaml:"min_size"`
 }
 
 // ThemeConfig holds theme name, directory, and custom parameters.
@@ -69,6 +108,102 @@ type OGPConfig struct {
 	Height   int    `yaml:"height"`
 }
 
+// ImagesConfig holds settings for build-time responsive image processing.
+// When enabled, JPEG and PNG images referenced from article Markdown are
+// resized to each of Widths and the <img> tag gets srcset, sizes, width, and
+// height attributes.
+type ImagesConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Widths lists the pixel widths to generate. Widths not smaller than the
+	// original image are skipped; the original is always part of the srcset.
+	Widths []int `yaml:"widths"`
+	// Sizes is the value of the sizes attribute (e.g. "(max-width: 768px) 100vw, 768px").
+	Sizes string `yaml:"sizes"`
+	// Quality is the JPEG encoding quality, 1 to 100.
+	Quality int `yaml:"quality"`
+}
+
+// SearchConfig holds settings for the client-side search index written to
+// search-index.json.
+type SearchConfig struct {
+	// FullText additionally writes an inverted index of every article's
+	// title, description, taxonomies, and body text, sharded by the first
+	// character of each term, so themes can search article bodies.
+	FullText bool `yaml:"full_text"`
+	// Fields lists front matter keys, built-in or custom, whose values are
+	// added to each entry's fields object. String and list values are also
+	// covered by the full-text index.
+	Fields []string `yaml:"fields"`
+}
+
+// RelatedConfig configures the related articles listed on each article page
+// (Site.RelatedArticles). Candidates in the same locale are scored by a
+// weighted sum of signals; see RelatedSignals.
+type RelatedConfig struct {
+	// Count is the maximum number of related articles per page; 0 means 5.
+	Count int `yaml:"count"`
+	// Weights maps a signal name in RelatedSignals to its weight. A missing
+	// signal keeps its default weight; a weight of 0 disables the signal.
+	Weights map[string]float64 `yaml:"weights"`
+	// RecencyHalfLife is the publication date distance, in days, at which the
+	// recency signal halves; 0 means 365.
+	RecencyHalfLife int `yaml:"recency_half_life"`
+}
+
+// RelatedSignals lists the signals related articles are scored by, each
+// between 0 and 1, with their default weights:
+//   - tags: the share of tags the two articles have in common;
+//   - categories: the share of categories they have in common;
+//   - content: the TF-IDF cosine similarity of their body text;
+//   - recency: how close their publication dates are.
+//
+// Only articles with a positive tags, categories, or content score are
+// related; recency just ranks them. The content signal is off by default:
+// its scores depend on the body of every article, so with it an incremental
+// build re-renders every scored article page whenever any article changes.
+var RelatedSignals = map[string]float64{
+	"tags":       1,
+	"categories": 1,
+	"content":    0,
+	"recency":    0.5,
+}
+
+// TaxonomyConfig declares a user-defined taxonomy besides tags and
+// categories. Articles assign terms with the front matter field named after
+// the taxonomy, holding a string or a list of strings (e.g. authors: [alice]).
+type TaxonomyConfig struct {
+	// Name is the plural name of the taxonomy, e.g. "authors": the front
+	// matter field, the URL segment of its pages (/authors/alice/), and the
+	// base name of its registry files (authors.yaml).
+	Name string `yaml:"name"`
+	// Template renders the article listing of each term; defaults to
+	// "term.html".
+	Template string `yaml:"template"`
+	// IndexTemplate renders the page listing every term at /{name}/;
+	// defaults to "taxonomy.html".
+	IndexTemplate string `yaml:"index_template"`
+	// Feed writes feed.xml and atom.xml next to the listing of each term.
+	Feed bool `yaml:"feed"`
+}
+
+// AssetsConfig configures the asset pipeline applied to the CSS and JS files
+// of Build.AssetsDir. Templates resolve assets with the asset function, which
+// returns the published URL and a subresource integrity hash.
+type AssetsConfig struct {
+	// Minify strips comments and redundant whitespace from CSS and JS files.
+	Minify bool `yaml:"minify"`
+	// Fingerprint additionally publishes every CSS and JS file under a
+	// content-hashed name (e.g. css/main.3f9a1c2b.css), which the asset
+	// function returns, so the files can be served with far-future cache
+	// headers.
+	Fingerprint bool `yaml:"fingerprint"`
+	// Bundles maps an output path relative to the assets directory (e.g.
+	// "js/app.js") to the source files, relative to the same directory,
+	// concatenated into it in order. Sources must have the extension of the
+	// output, .css or .js.
+	Bundles map[string][]string `yaml:"bundles"`
+}
+
 // I18nConfig holds multi-language content configuration.
 type I18nConfig struct {
 	// Locales is the ordered list of locale codes present under the content
@@ -81,3 +216,38 @@ type I18nConfig struct {
 	// /ja/posts/hello/ is Japanese.
 	DefaultLocale string `yaml:"default_locale"`
 }
+
+// CheckConfig configures the rules run by `gohan check`.
+type CheckConfig struct {
+	// Severity overrides the default severity of a rule, keyed by rule ID
+	// (e.g. "missing-date": "warning"). Valid severities are "error",
+	// "warning", and "info"; only errors make `gohan check` fail.
+	Severity map[string]string `yaml:"severity"`
+	// Enable lists rules to run in addition to those enabled by default.
+	Enable []string `yaml:"enable"`
+	// Disable lists rules not to run. It takes precedence over Enable.
+	Disable []string `yaml:"disable"`
+}
+
+// SectionSchema declares the front matter fields of one content section.
+type SectionSchema struct {
+	// Fields maps a front matter key, built-in (e.g. "description") or not
+	// (stored in FrontMatter.Extra), to its constraints.
+	Fields map[string]FieldSchema `yaml:"fields"`
+}
+
+// FieldSchema constrains one front matter field. Zero values impose no
+// constraint.
+type FieldSchema struct {
+	// Required reports a missing field. Built-in fields count as missing
+	// when empty (e.g. title: "" or draft: false).
+	Required bool `yaml:"required"`
+	// Type is one of "string", "int", "float", "bool", "date", "list", or
+	// "map". "float" also accepts integers.
+	Type string `yaml:"type"`
+	// Enum lists the allowed values. For lists it applies to every element.
+	Enum []string `yaml:"enum"`
+	// Pattern is a regular expression (RE2 syntax) string values must match.
+	// For lists it applies to every element.
+	Pattern string `yaml:"pattern"`
+}
@@ -11,6 +11,8 @@ const (
 	NodeTypeCategory
 	NodeTypeArchive
 	NodeTypePage
+	NodeTypeSeries
+	NodeTypeTerm
 )
 
 // Node is a vertex in the dependency graph.
@@ -4,10 +4,14 @@ import "time"
 
 // BuildManifest is the build history persisted to .gohan/cache/manifest.json.
 type BuildManifest struct {
-	Version      string              `json:"version"`
-	BuildTime    time.Time           `json:"build_time"`
-	LastCommit   string              `json:"last_commit"`
-	FileHashes   map[string]string   `json:"file_hashes"`
+	Version    string            `json:"version"`
+	BuildTime  time.Time         `json:"build_time"`
+	LastCommit string            `json:"last_commit"`
+	FileHashes map[string]string `json:"file_hashes"`
+	// GitInfo records the git history each built article was rendered with
+	// (see diff.GitInfoDigests), keyed by content-relative path. Empty unless
+	// build.git_info is enabled.
+	GitInfo      map[string]string   `json:"git_info,omitempty"`
 	Dependencies map[string][]string `json:"dependencies"`
 	OutputFiles  []OutputFile        `json:"output_files"`
 }
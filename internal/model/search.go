@@ -0,0 +1,29 @@
+package model
+
+import "time"
+
+// SearchDocument is a searchable record contributed by a SitePlugin, such as
+// a virtual page, that is added to search-index.json next to the articles.
+// Populated by plugin.Registry.EnrichVirtual from plugins implementing
+// SearchDocumentProvider.
+type SearchDocument struct {
+	Title string
+	// URL is the site-relative path of the page (e.g. "/bookshelf/"); the
+	// index makes it absolute with the base URL like article links.
+	URL         string
+	Description string
+	Summary     string
+	Tags        []string
+	Categories  []string
+	Date        time.Time
+	// Locale selects the per-locale index the document is written to; empty
+	// means the default locale.
+	Locale string
+	// Text is the additional text covered by the full-text index, e.g. the
+	// body of the page without markup. It is not written to the entry itself.
+	Text string
+	// Fields holds extra values written to the entry's fields object.
+	Fields map[string]interface{}
+	// Plugin is the name of the contributing plugin, set by the registry.
+	Plugin string
+}
@@ -9,10 +9,36 @@ type Site struct {
 	ArchiveYears          []int               // unique years that have articles, sorted newest-first
 	Pagination            *Pagination         // nil when pagination is disabled or not a listing page
 	CurrentLocale         string              // locale for the current page; empty when i18n is not configured
-	RelatedArticles       []*ProcessedArticle // articles sharing at least one category with the current article (article pages only)
-	CurrentTaxonomy       *Taxonomy           // set on tag and category listing pages; nil elsewhere
+	RelatedArticles       []*ProcessedArticle // highest-scoring related articles of the current article (article pages only); see RelatedConfig
+	CurrentTaxonomy       *Taxonomy           // set on tag, category, and user-defined term listing pages; nil elsewhere
 	CurrentArchivePath    string              // set on archive pages; locale-aware path e.g. "/archives/2024/01/" or "/ja/archives/2024/01/"
 	CurrentArchiveIsMonth bool                // true for month archives (/archives/2024/01/), false for year archives (/archives/2024/)
+	// Taxonomies holds the terms of each user-defined taxonomy (see
+	// TaxonomyConfig) present among the page's articles, keyed by taxonomy
+	// name, like Tags and Categories.
+	Taxonomies map[string][]Taxonomy
+	// CurrentTaxonomyName is the name of the user-defined taxonomy on its
+	// term listing and index pages, e.g. "authors"; empty elsewhere.
+	CurrentTaxonomyName string
+	// PrevArticle and NextArticle are the next older and next newer articles
+	// of the same locale and content section on article pages; nil at either
+	// end and on all other pages.
+	PrevArticle *ProcessedArticle
+	NextArticle *ProcessedArticle
+	// Series lists the series with a part among the page's articles (the
+	// whole locale on article pages). Populated from
+	// processor.SiteProcessor.BuildSeries.
+	Series []*Series
+	// CurrentSeries is the series of the current article on article pages,
+	// or the series being listed on series pages; nil elsewhere.
+	CurrentSeries *Series
+	// SeriesPart is the 1-based position of the current article in
+	// CurrentSeries ("Part 2 of 5"); 0 on all other pages.
+	SeriesPart int
+	// PrevInSeries and NextInSeries are the parts before and after the
+	// current article in CurrentSeries; nil at either end and elsewhere.
+	PrevInSeries *ProcessedArticle
+	NextInSeries *ProcessedArticle
 	// ListingArticles holds the ordered set of articles resolved from
 	// FrontMatter.ListingSlugs.  Nil for all pages that do not declare
 	// listing_slugs.  Templates should use {{range .ListingArticles}} on
@@ -32,6 +58,10 @@ type Site struct {
 	// Populated by plugin.Registry.EnrichVirtual from plugins implementing
 	// SiteDataProvider. Access in templates: {{index .SiteData "<plugin>"}}.
 	SiteData map[string]interface{}
+	// SearchDocuments holds the search records contributed by SitePlugins
+	// implementing SearchDocumentProvider, written to search-index.json
+	// after the articles. Populated by plugin.Registry.EnrichVirtual.
+	SearchDocuments []*SearchDocument
 }
 
 // Pagination holds computed paging metadata for listing pages.
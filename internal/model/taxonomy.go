@@ -1,6 +1,6 @@
 package model
 
-// Taxonomy represents a single tag or category entry.
+// Taxonomy represents a single tag, category, or user-defined taxonomy term.
 type Taxonomy struct {
 	Name        string `yaml:"name"`
 	Description string `yaml:"description"`
@@ -20,4 +20,21 @@ type Taxonomy struct {
 type TaxonomyRegistry struct {
 	Tags       []Taxonomy `yaml:"tags"`
 	Categories []Taxonomy `yaml:"categories"`
+	// Taxonomies holds the terms of each user-defined taxonomy (see
+	// TaxonomyConfig), keyed by taxonomy name.
+	Taxonomies map[string][]Taxonomy `yaml:"-"`
+}
+
+// Series is an ordered group of articles of one locale that share the same
+// series front matter value, such as the parts of a multi-part tutorial.
+type Series struct {
+	Name string
+	// Locale is the locale of the articles; empty when i18n is not configured.
+	Locale string
+	// URL is the locale-aware URL of the series listing page, e.g.
+	// "/series/go-tutorial/" or "/ja/series/go-tutorial/"; set at render time.
+	URL string
+	// Articles lists the parts in reading order: ascending series_order,
+	// with parts without one last, then oldest first.
+	Articles []*ProcessedArticle
 }
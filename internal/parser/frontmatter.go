@@ -1,10 +1,17 @@
 package parser
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"math"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
+	"time"
 
 	"gopkg.in/yaml.v3"
 
@@ -12,13 +19,15 @@ import (
 )
 
 // FileParser implements the Parser interface, reading Markdown files from disk.
-// Each file may optionally begin with a YAML front matter block delimited by
-// "---" lines. The remainder of the file is treated as the raw Markdown body.
+// Each file may optionally begin with a front matter block: YAML delimited by
+// "---" lines, TOML delimited by "+++" lines, or a JSON object. The remainder
+// of the file is treated as the raw Markdown body.
 //
 // ExcludeFiles holds glob patterns (relative to the content directory) that
 // should be skipped during ParseAll. Patterns use filepath.Match syntax.
 type FileParser struct {
 	excludeFiles []string
+	parallelism  int // files parsed concurrently by ParseAll; <= 1 means sequential
 }
 
 // NewFileParser returns a new FileParser. Pass any number of glob patterns
@@ -27,7 +36,13 @@ func NewFileParser(excludeFiles ...string) *FileParser {
 	return &FileParser{excludeFiles: excludeFiles}
 }
 
-// Parse reads the file at filePath, extracts any YAML front matter, and
+// SetParallelism sets how many files ParseAll reads and parses concurrently.
+// Values below 1 are treated as 1.
+func (p *FileParser) SetParallelism(n int) {
+	p.parallelism = n
+}
+
+// Parse reads the file at filePath, extracts any front matter, and
 // returns a fully populated *model.Article.
 func (p *FileParser) Parse(filePath string) (*model.Article, error) {
 	data, err := os.ReadFile(filePath)
@@ -56,9 +71,11 @@ func (p *FileParser) Parse(filePath string) (*model.Article, error) {
 // ParseAll walks contentDir recursively and returns one *model.Article per
 // Markdown file (.md or .markdown extension, case-insensitive).
 // Files whose path (relative to contentDir) matches any pattern in
-// FileParser.excludeFiles are silently skipped.
+// FileParser.excludeFiles are silently skipped. Files are parsed concurrently
+// (see SetParallelism) but returned in walk order. Every file that fails to
+// parse is reported, joined with errors.Join, rather than only the first.
 func (p *FileParser) ParseAll(contentDir string) ([]*model.Article, error) {
-	var articles []*model.Article
+	var paths []string
 
 	err := filepath.WalkDir(contentDir, func(path string, d os.DirEntry, err error) error {
 		if err != nil {
@@ -82,51 +99,286 @@ func (p *FileParser) ParseAll(contentDir string) ([]*model.Article, error) {
 				}
 			}
 		}
-		a, parseErr := p.Parse(path)
-		if parseErr != nil {
-			return parseErr
-		}
-		articles = append(articles, a)
+		paths = append(paths, path)
 		return nil
 	})
 	if err != nil {
 		return nil, fmt.Errorf("parser: walk %s: %w", contentDir, err)
 	}
 
+	parallelism := p.parallelism
+	if parallelism <= 0 {
+		parallelism = 1
+	}
+	articles := make([]*model.Article, len(paths))
+	errs := make([]error, len(paths))
+	sem := make(chan struct{}, parallelism)
+	var wg sync.WaitGroup
+	for i, path := range paths {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, path string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			articles[i], errs[i] = p.Parse(path)
+		}(i, path)
+	}
+	wg.Wait()
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
 	return articles, nil
 }
 
-// splitFrontMatter separates a YAML front matter block from the Markdown body.
-// Front matter must start on the very first line as "---" and end with a
-// subsequent "---" line. If no valid front matter is found the entire content
-// is returned as the body unchanged.
+// Front matter delimiters. YAML and TOML blocks are fenced by a line holding
+// only the delimiter; JSON front matter is a single object at the very start
+// of the file.
+const (
+	yamlDelimiter = "---"
+	tomlDelimiter = "+++"
+)
+
+// splitFrontMatter separates the front matter block from the Markdown body.
+// Three formats are recognised by the first line of the file: YAML fenced by
+// "---" lines, TOML fenced by "+++" lines, and a JSON object opened on the
+// first line. All of them decode into the same model.FrontMatter, with unknown keys
+// in Extra. If the file starts with none of them the entire content is
+// returned as the body unchanged; a block that is never closed is an error.
 func splitFrontMatter(data []byte) (model.FrontMatter, []byte, error) {
 	var fm model.FrontMatter
 
 	lines := strings.Split(string(data), "\n")
-	if len(lines) == 0 || strings.TrimRight(lines[0], "\r") != "---" {
+	first := strings.TrimRight(lines[0], "\r")
+	switch {
+	case first == yamlDelimiter || first == tomlDelimiter:
+	case isJSONFrontMatter(first):
+		return splitJSONFrontMatter(data)
+	default:
 		return fm, data, nil
 	}
 
-	// Find the closing "---".
+	// Find the closing delimiter.
 	closingIdx := -1
 	for i := 1; i < len(lines); i++ {
-		if strings.TrimRight(lines[i], "\r") == "---" {
+		if strings.TrimRight(lines[i], "\r") == first {
 			closingIdx = i
 			break
 		}
 	}
-
 	if closingIdx == -1 {
-		// No closing delimiter — treat entire content as body.
-		return fm, data, nil
+		return fm, nil, fmt.Errorf("unterminated front matter: no closing %q line", first)
+	}
+
+	block := strings.Join(lines[1:closingIdx], "\n")
+	body := []byte(strings.Join(lines[closingIdx+1:], "\n"))
+	if first == yamlDelimiter {
+		if err := yaml.Unmarshal([]byte(block), &fm); err != nil {
+			return fm, nil, fmt.Errorf("unmarshal front matter: %w", err)
+		}
+		return fm, body, nil
 	}
 
-	yamlData := strings.Join(lines[1:closingIdx], "\n")
-	if err := yaml.Unmarshal([]byte(yamlData), &fm); err != nil {
+	values, err := decodeTOML(block)
+	if err != nil {
 		return fm, nil, fmt.Errorf("unmarshal front matter: %w", err)
 	}
+	if err := decodeFrontMatterMap(values, &fm); err != nil {
+		return fm, nil, err
+	}
+	return fm, body, nil
+}
 
-	body := strings.Join(lines[closingIdx+1:], "\n")
-	return fm, []byte(body), nil
+// isJSONFrontMatter reports whether first, the first line of a file, opens a
+// JSON object: a lone "{", or "{" followed by the first key. Bodies that
+// merely start with a brace, such as "{{< shortcode >}}" or "{% raw %}",
+// are not front matter.
+func isJSONFrontMatter(first string) bool {
+	rest, ok := strings.CutPrefix(strings.TrimSpace(first), "{")
+	if !ok {
+		return false
+	}
+	rest = strings.TrimSpace(rest)
+	return rest == "" || strings.HasPrefix(rest, `"`)
+}
+
+// splitJSONFrontMatter decodes the JSON object at the start of data. The
+// body starts on the line after the closing brace.
+func splitJSONFrontMatter(data []byte) (model.FrontMatter, []byte, error) {
+	var fm model.FrontMatter
+	var values map[string]interface{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&values); err != nil {
+		if errors.Is(err, io.ErrUnexpectedEOF) {
+			return fm, nil, errors.New("unterminated front matter: no closing \"}\"")
+		}
+		return fm, nil, fmt.Errorf("unmarshal front matter: %w", err)
+	}
+	body := data[dec.InputOffset():]
+	if i := bytes.IndexByte(body, '\n'); i >= 0 && len(bytes.TrimSpace(body[:i])) == 0 {
+		body = body[i+1:]
+	} else if len(bytes.TrimSpace(body)) == 0 {
+		body = nil
+	}
+	if err := decodeFrontMatterMap(jsonNumbers(values).(map[string]interface{}), &fm); err != nil {
+		return fm, nil, err
+	}
+	return fm, body, nil
+}
+
+// decodeFrontMatterMap fills fm from decoded TOML or JSON values. Keys of
+// the FrontMatter fields are converted to the field types, accepting what
+// YAML front matter accepts for them; every other key is kept in Extra as
+// decoded, so floats stay float64s and tables stay maps.
+func decodeFrontMatterMap(values map[string]interface{}, fm *model.FrontMatter) error {
+	for key, v := range values {
+		if v == nil {
+			continue
+		}
+		var err error
+		switch key {
+		case "title":
+			fm.Title, err = frontMatterString(v)
+		case "date":
+			fm.Date, err = frontMatterTime(v)
+		case "lastmod":
+			fm.LastMod, err = frontMatterTime(v)
+		case "draft":
+			fm.Draft, err = frontMatterBool(v)
+		case "tags":
+			fm.Tags, err = frontMatterStrings(v)
+		case "categories":
+			fm.Categories, err = frontMatterStrings(v)
+		case "description":
+			fm.Description, err = frontMatterString(v)
+		case "author":
+			fm.Author, err = frontMatterString(v)
+		case "slug":
+			fm.Slug, err = frontMatterString(v)
+		case "template":
+			fm.Template, err = frontMatterString(v)
+		case "translation_key":
+			fm.TranslationKey, err = frontMatterString(v)
+		case "listing_slugs":
+			fm.ListingSlugs, err = frontMatterStrings(v)
+		case "series":
+			fm.Series, err = frontMatterString(v)
+		case "series_order":
+			fm.SeriesOrder, err = frontMatterInt(v)
+		case "related":
+			fm.Related, err = frontMatterStrings(v)
+		case "search_exclude":
+			fm.SearchExclude, err = frontMatterBool(v)
+		default:
+			if fm.Extra == nil {
+				fm.Extra = make(map[string]interface{})
+			}
+			fm.Extra[key] = v
+		}
+		if err != nil {
+			return fmt.Errorf("unmarshal front matter: %s: %w", key, err)
+		}
+	}
+	return nil
+}
+
+// frontMatterString converts a scalar to a string, as YAML does for string
+// fields.
+func frontMatterString(v interface{}) (string, error) {
+	switch x := v.(type) {
+	case string:
+		return x, nil
+	case int, float64, bool:
+		return fmt.Sprint(x), nil
+	}
+	return "", fmt.Errorf("cannot use %T as a string", v)
+}
+
+func frontMatterStrings(v interface{}) ([]string, error) {
+	list, ok := v.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("cannot use %T as a list of strings", v)
+	}
+	out := make([]string, len(list))
+	for i, e := range list {
+		s, err := frontMatterString(e)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = s
+	}
+	return out, nil
+}
+
+func frontMatterBool(v interface{}) (bool, error) {
+	if b, ok := v.(bool); ok {
+		return b, nil
+	}
+	return false, fmt.Errorf("cannot use %T as a bool", v)
+}
+
+func frontMatterInt(v interface{}) (int, error) {
+	switch x := v.(type) {
+	case int:
+		return x, nil
+	case float64:
+		if x == math.Trunc(x) && math.Abs(x) <= math.MaxInt32 {
+			return int(x), nil
+		}
+	}
+	return 0, fmt.Errorf("cannot use %T as an integer", v)
+}
+
+// frontMatterTime accepts a decoded time or, since JSON has no date type and
+// TOML dates are often quoted, a string in the forms YAML front matter does.
+func frontMatterTime(v interface{}) (time.Time, error) {
+	switch x := v.(type) {
+	case time.Time:
+		return x, nil
+	case string:
+		return parseFrontMatterDate(x)
+	}
+	return time.Time{}, fmt.Errorf("cannot use %T as a date", v)
+}
+
+// jsonNumbers replaces json.Number values in v with ints where they are
+// integers and float64s otherwise, matching what YAML decoding produces.
+func jsonNumbers(v interface{}) interface{} {
+	switch x := v.(type) {
+	case json.Number:
+		if n, err := x.Int64(); err == nil {
+			return int(n)
+		}
+		f, _ := x.Float64()
+		return f
+	case map[string]interface{}:
+		for k, e := range x {
+			x[k] = jsonNumbers(e)
+		}
+	case []interface{}:
+		for i, e := range x {
+			x[i] = jsonNumbers(e)
+		}
+	}
+	return v
+}
+
+// frontMatterDateLayouts are the timestamp forms YAML front matter accepts.
+var frontMatterDateLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999 -07:00",
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02",
+}
+
+func parseFrontMatterDate(s string) (time.Time, error) {
+	for _, layout := range frontMatterDateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q", s)
 }
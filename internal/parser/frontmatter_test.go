@@ -1,8 +1,10 @@
 package parser
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 )
@@ -77,18 +79,102 @@ func TestFileParser_Parse_EmptyFile(t *testing.T) {
 
 func TestFileParser_Parse_NoClosingDelimiter(t *testing.T) {
 	dir := t.TempDir()
-	content := "---\ntitle: Incomplete\n# No closing"
-	path := writeFile(t, dir, "incomplete.md", content)
-	p := NewFileParser()
-	a, err := p.Parse(path)
+	for name, content := range map[string]string{
+		"yaml.md": "---\ntitle: Incomplete\n# No closing",
+		"toml.md": "+++\ntitle = \"Incomplete\"\n# No closing",
+		"json.md": "{\n  \"title\": \"Incomplete\",\n  \"tags\": [\"go\"",
+	} {
+		path := writeFile(t, dir, name, content)
+		_, err := NewFileParser().Parse(path)
+		if err == nil || !strings.Contains(err.Error(), "unterminated front matter") {
+			t.Errorf("%s: expected unterminated front matter error, got %v", name, err)
+		}
+	}
+}
+
+func TestFileParser_Parse_TOMLFrontMatter(t *testing.T) {
+	dir := t.TempDir()
+	src := "+++\ntitle = \"Hello TOML\"\ndate = 2024-03-15\ntags = [\"go\", \"ssg\"]\ndraft = false\nweight = 3\n\n[params]\nhero = \"img/a.png\"\n+++\n# Body\n"
+	path := writeFile(t, dir, "post.md", src)
+	a, err := NewFileParser().Parse(path)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if a.FrontMatter.Title != "" {
-		t.Errorf("expected empty title, got %q", a.FrontMatter.Title)
+	fm := a.FrontMatter
+	if fm.Title != "Hello TOML" || !fm.Date.Equal(time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC)) || len(fm.Tags) != 2 {
+		t.Errorf("FrontMatter = %+v", fm)
+	}
+	if fm.Extra["weight"] != 3 {
+		t.Errorf("Extra[weight] = %#v, want 3", fm.Extra["weight"])
 	}
-	if string(a.RawContent) != content {
-		t.Error("RawContent should be entire file content")
+	if params, _ := fm.Extra["params"].(map[string]interface{}); params["hero"] != "img/a.png" {
+		t.Errorf("Extra[params] = %#v", fm.Extra["params"])
+	}
+	if a.RawContent != "# Body\n" {
+		t.Errorf("RawContent = %q", a.RawContent)
+	}
+}
+
+func TestFileParser_Parse_JSONFrontMatter(t *testing.T) {
+	dir := t.TempDir()
+	src := "{\n  \"title\": \"Hello JSON\",\n  \"date\": \"2024-03-15T10:00:00Z\",\n  \"categories\": [\"tech\"],\n  \"rating\": 4.5,\n  \"count\": 2\n}\n# Body\n"
+	path := writeFile(t, dir, "post.md", src)
+	a, err := NewFileParser().Parse(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	fm := a.FrontMatter
+	if fm.Title != "Hello JSON" || fm.Date.Hour() != 10 || len(fm.Categories) != 1 {
+		t.Errorf("FrontMatter = %+v", fm)
+	}
+	if fm.Extra["rating"] != 4.5 || fm.Extra["count"] != 2 {
+		t.Errorf("Extra = %#v", fm.Extra)
+	}
+	if a.RawContent != "# Body\n" {
+		t.Errorf("RawContent = %q", a.RawContent)
+	}
+}
+
+func TestFileParser_Parse_FrontMatterFloats(t *testing.T) {
+	dir := t.TempDir()
+	for name, src := range map[string]string{
+		"toml.md": "+++\ntitle = \"x\"\nversion = 1.0\nseries_order = 2\n+++\nbody\n",
+		"json.md": "{\"title\": \"x\", \"version\": 1.0, \"series_order\": 2}\nbody\n",
+	} {
+		a, err := NewFileParser().Parse(writeFile(t, dir, name, src))
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+		if v, ok := a.FrontMatter.Extra["version"].(float64); !ok || v != 1 {
+			t.Errorf("%s: Extra[version] = %#v, want float64 1", name, a.FrontMatter.Extra["version"])
+		}
+		if a.FrontMatter.SeriesOrder != 2 {
+			t.Errorf("%s: SeriesOrder = %d, want 2", name, a.FrontMatter.SeriesOrder)
+		}
+	}
+}
+
+func TestFileParser_Parse_BraceBodyIsNotJSON(t *testing.T) {
+	dir := t.TempDir()
+	for _, src := range []string{
+		"{{< youtube id >}}\n\nText.\n",
+		"{% raw %}\n{{ x }}\n{% endraw %}\n",
+	} {
+		a, err := NewFileParser().Parse(writeFile(t, dir, "post.md", src))
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", src, err)
+		}
+		if a.RawContent != src || a.FrontMatter.Title != "" {
+			t.Errorf("%q: RawContent = %q, want the whole file as body", src, a.RawContent)
+		}
+	}
+}
+
+func TestFileParser_Parse_InvalidTOML(t *testing.T) {
+	dir := t.TempDir()
+	path := writeFile(t, dir, "bad.md", "+++\ntitle = \"x\"\ntitle = \"y\"\n+++\nbody\n")
+	if _, err := NewFileParser().Parse(path); err == nil || !strings.Contains(err.Error(), "already been defined") {
+		t.Errorf("expected duplicate key error, got %v", err)
 	}
 }
 
@@ -184,6 +270,48 @@ func TestFileParser_ParseAll_SubDirectories(t *testing.T) {
 	}
 }
 
+func TestFileParser_ParseAll_ParallelKeepsWalkOrder(t *testing.T) {
+	dir := t.TempDir()
+	var want []string
+	for i := 0; i < 20; i++ {
+		name := fmt.Sprintf("post-%02d.md", i)
+		writeFile(t, dir, name, fmt.Sprintf("---\ntitle: T%d\n---\nbody\n", i))
+		want = append(want, filepath.Join(dir, name))
+	}
+	p := NewFileParser()
+	p.SetParallelism(4)
+	articles, err := p.ParseAll(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(articles) != len(want) {
+		t.Fatalf("expected %d articles, got %d", len(want), len(articles))
+	}
+	for i, a := range articles {
+		if a.FilePath != want[i] {
+			t.Errorf("articles[%d] = %s, want %s", i, a.FilePath, want[i])
+		}
+	}
+}
+
+func TestFileParser_ParseAll_ReportsAllBrokenFiles(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "bad1.md", "---\ntitle: [unclosed\n---\nbody\n")
+	writeFile(t, dir, "good.md", "---\ntitle: Good\n---\nbody\n")
+	writeFile(t, dir, "bad2.md", "---\ntags: {\n---\nbody\n")
+	p := NewFileParser()
+	p.SetParallelism(2)
+	_, err := p.ParseAll(dir)
+	if err == nil {
+		t.Fatal("expected error for broken front matter, got nil")
+	}
+	for _, name := range []string{"bad1.md", "bad2.md"} {
+		if !strings.Contains(err.Error(), name) {
+			t.Errorf("error does not mention %s: %v", name, err)
+		}
+	}
+}
+
 func TestFileParser_ParseAll_DirNotFound(t *testing.T) {
 	p := NewFileParser()
 	_, err := p.ParseAll("/nonexistent/content/dir")
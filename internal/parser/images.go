@@ -0,0 +1,73 @@
+package parser
+
+import (
+	"strconv"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	goldmarkparser "github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// ImageAttrs are the attributes added to the <img> tag of a Markdown image.
+// Zero values are omitted.
+type ImageAttrs struct {
+	Width  int
+	Height int
+	Srcset string
+	Sizes  string
+}
+
+// ImageResolver returns the attributes of the Markdown image whose
+// destination is dest, or nil to render the image unchanged.
+type ImageResolver func(dest string) *ImageAttrs
+
+// imageResolverKey holds the ImageResolver of a ConvertWithImages call in the
+// goldmark parser context.
+var imageResolverKey = goldmarkparser.NewContextKey()
+
+// imageTransformer sets the attributes returned by the ImageResolver in the
+// parser context on every image node. goldmark's HTML renderer then emits
+// them on the <img> tag, so no renderer for ast.KindImage is registered.
+type imageTransformer struct{}
+
+func (t *imageTransformer) Transform(doc *ast.Document, _ text.Reader, pc goldmarkparser.Context) {
+	resolve, _ := pc.Get(imageResolverKey).(ImageResolver)
+	if resolve == nil {
+		return
+	}
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		img, ok := n.(*ast.Image)
+		if !entering || !ok {
+			return ast.WalkContinue, nil
+		}
+		attrs := resolve(string(img.Destination))
+		if attrs == nil {
+			return ast.WalkContinue, nil
+		}
+		if attrs.Width > 0 {
+			img.SetAttributeString("width", []byte(strconv.Itoa(attrs.Width)))
+		}
+		if attrs.Height > 0 {
+			img.SetAttributeString("height", []byte(strconv.Itoa(attrs.Height)))
+		}
+		if attrs.Srcset != "" {
+			img.SetAttributeString("srcset", []byte(attrs.Srcset))
+			if attrs.Sizes != "" {
+				img.SetAttributeString("sizes", []byte(attrs.Sizes))
+			}
+		}
+		return ast.WalkContinue, nil
+	})
+}
+
+type imageExtender struct{}
+
+func (e *imageExtender) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(
+		goldmarkparser.WithASTTransformers(
+			util.Prioritized(&imageTransformer{}, 200),
+		),
+	)
+}
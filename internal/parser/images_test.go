@@ -0,0 +1,49 @@
+package parser_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bmf-san/gohan/internal/parser"
+)
+
+func TestConverter_ConvertWithImages(t *testing.T) {
+	c := parser.NewConverter(parser.WithGFM(), parser.WithImages())
+	var dests []string
+	resolve := func(dest string) *parser.ImageAttrs {
+		dests = append(dests, dest)
+		if dest != "cover.png" {
+			return nil
+		}
+		return &parser.ImageAttrs{Width: 1000, Height: 500, Srcset: "cover-480w.png 480w, cover.png 1000w", Sizes: "100vw"}
+	}
+	got, err := c.ConvertWithImages([]byte("![Cover](cover.png)\n\n![Logo](https://example.com/logo.png)\n"), resolve)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	html := string(got)
+	want := `<img src="cover.png" alt="Cover" width="1000" height="500" srcset="cover-480w.png 480w, cover.png 1000w" sizes="100vw">`
+	if !strings.Contains(html, want) {
+		t.Errorf("expected %s in output, got: %s", want, html)
+	}
+	if !strings.Contains(html, `<img src="https://example.com/logo.png" alt="Logo">`) {
+		t.Errorf("unresolved image should be unchanged, got: %s", html)
+	}
+	if len(dests) != 2 {
+		t.Errorf("resolver calls: got %v, want both images", dests)
+	}
+}
+
+func TestConverter_ConvertWithImages_WithoutOption(t *testing.T) {
+	c := parser.NewConverter()
+	got, err := c.ConvertWithImages([]byte("![Cover](cover.png)\n"), func(string) *parser.ImageAttrs {
+		t.Error("resolver called without WithImages")
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(string(got), "srcset") {
+		t.Errorf("unexpected srcset, got: %s", got)
+	}
+}
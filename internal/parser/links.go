@@ -0,0 +1,64 @@
+package parser
+
+import (
+	"bytes"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/text"
+)
+
+// Link is a link or image reference found in Markdown source.
+type Link struct {
+	Destination string // the URL as written, e.g. "/posts/hello/" or "img/a.png"
+	Line        int    // 1-based line number in the source
+	Image       bool   // true for ![alt](src), false for [text](href)
+}
+
+// ExtractLinks returns every inline link and image in Markdown source, in
+// document order. Reference-style links are reported with their resolved
+// destination at the line where they are used. Autolinks and raw HTML are
+// not included.
+func ExtractLinks(src []byte) []Link {
+	md := goldmark.New(goldmark.WithExtensions(extension.GFM))
+	doc := md.Parser().Parse(text.NewReader(src))
+
+	var links []Link
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch v := n.(type) {
+		case *ast.Link:
+			links = append(links, Link{Destination: string(v.Destination), Line: nodeLine(v, src)})
+		case *ast.Image:
+			links = append(links, Link{Destination: string(v.Destination), Line: nodeLine(v, src), Image: true})
+		}
+		return ast.WalkContinue, nil
+	})
+	return links
+}
+
+// nodeLine returns the 1-based source line of an inline node: the line of its
+// first text segment, or of the enclosing block when it has no text (e.g. a
+// link with empty text).
+func nodeLine(n ast.Node, src []byte) int {
+	offset := -1
+	_ = ast.Walk(n, func(c ast.Node, entering bool) (ast.WalkStatus, error) {
+		if t, ok := c.(*ast.Text); ok && entering {
+			offset = t.Segment.Start
+			return ast.WalkStop, nil
+		}
+		return ast.WalkContinue, nil
+	})
+	for p := n.Parent(); offset < 0 && p != nil; p = p.Parent() {
+		if p.Type() == ast.TypeBlock && p.Lines().Len() > 0 {
+			offset = p.Lines().At(0).Start
+		}
+	}
+	if offset < 0 {
+		return 1
+	}
+	return bytes.Count(src[:offset], []byte("\n")) + 1
+}
@@ -0,0 +1,20 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExtractLinks(t *testing.T) {
+	src := []byte("# Title\n\nSee [the post](/posts/a/) and\n![diagram](img/d.png \"D\").\n\n" +
+		"```\n[not a link](/code/)\n```\n\n- [](/empty/)\n- [ref][r]\n\n[r]: ../b/#top\n")
+	want := []Link{
+		{Destination: "/posts/a/", Line: 3},
+		{Destination: "img/d.png", Line: 4, Image: true},
+		{Destination: "/empty/", Line: 10},
+		{Destination: "../b/#top", Line: 11},
+	}
+	if got := ExtractLinks(src); !reflect.DeepEqual(got, want) {
+		t.Errorf("ExtractLinks() =\n%+v\nwant\n%+v", got, want)
+	}
+}
@@ -27,6 +27,7 @@ type converterConfig struct {
 	unsafeHTML   bool
 	highlighting *highlight.Config
 	mermaid      bool
+	images       bool
 }
 
 // ConverterOption is a functional option for NewConverter.
@@ -57,6 +58,13 @@ func WithMermaid() ConverterOption {
 	return func(c *converterConfig) { c.mermaid = true }
 }
 
+// WithImages enables responsive images: ConvertWithImages adds the width,
+// height, srcset, and sizes attributes its ImageResolver returns to the <img>
+// tag of each Markdown image.
+func WithImages() ConverterOption {
+	return func(c *converterConfig) { c.images = true }
+}
+
 // NewConverter builds a Converter with the supplied options.  When no options
 // are given, GFM extensions are enabled and raw HTML is escaped.
 func NewConverter(opts ...ConverterOption) *Converter {
@@ -89,6 +97,10 @@ func NewConverter(opts ...ConverterOption) *Converter {
 		mdOpts = append(mdOpts, goldmark.WithExtensions(mermaid.Extension()))
 	}
 
+	if cfg.images {
+		mdOpts = append(mdOpts, goldmark.WithExtensions(&imageExtender{}))
+	}
+
 	return &Converter{md: goldmark.New(mdOpts...)}
 }
 
@@ -101,3 +113,16 @@ func (c *Converter) Convert(src []byte) (template.HTML, error) {
 	}
 	return template.HTML(buf.String()), nil //nolint:gosec // goldmark output is safe HTML
 }
+
+// ConvertWithImages is like Convert, but resolves the attributes of every
+// Markdown image with resolve. It requires a Converter built WithImages;
+// otherwise resolve is never called.
+func (c *Converter) ConvertWithImages(src []byte, resolve ImageResolver) (template.HTML, error) {
+	pc := goldmarkparser.NewContext()
+	pc.Set(imageResolverKey, resolve)
+	var buf bytes.Buffer
+	if err := c.md.Convert(src, &buf, goldmarkparser.WithContext(pc)); err != nil {
+		return "", fmt.Errorf("markdown: convert: %w", err)
+	}
+	return template.HTML(buf.String()), nil //nolint:gosec // goldmark output is safe HTML
+}
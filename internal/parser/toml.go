@@ -0,0 +1,61 @@
+package parser
+
+import (
+	"time"
+
+	"github.com/BurntSushi/toml"
+)
+
+// decodeTOML decodes a TOML document into a map in the shape yaml.v3 produces
+// for YAML front matter: strings, ints, float64s, bools, time.Times,
+// []interface{}, and map[string]interface{}. Local times without a date are
+// kept as strings; local dates and date-times are read as UTC.
+func decodeTOML(src string) (map[string]interface{}, error) {
+	var root map[string]interface{}
+	if _, err := toml.Decode(src, &root); err != nil {
+		return nil, err
+	}
+	return tomlValue(root).(map[string]interface{}), nil
+}
+
+// Location names github.com/BurntSushi/toml gives the times it decodes from
+// TOML values without an offset.
+const (
+	tomlLocalDatetime = "datetime-local"
+	tomlLocalDate     = "date-local"
+	tomlLocalTime     = "time-local"
+)
+
+// tomlValue converts a value decoded by github.com/BurntSushi/toml to the
+// types decodeTOML returns.
+func tomlValue(v interface{}) interface{} {
+	switch x := v.(type) {
+	case int64:
+		return int(x)
+	case time.Time:
+		switch x.Location().String() {
+		case tomlLocalTime:
+			return x.Format("15:04:05.999999999")
+		case tomlLocalDatetime, tomlLocalDate:
+			return time.Date(x.Year(), x.Month(), x.Day(), x.Hour(), x.Minute(), x.Second(), x.Nanosecond(), time.UTC)
+		}
+		return x
+	case map[string]interface{}:
+		for k, e := range x {
+			x[k] = tomlValue(e)
+		}
+		return x
+	case []map[string]interface{}:
+		out := make([]interface{}, len(x))
+		for i, e := range x {
+			out[i] = tomlValue(e)
+		}
+		return out
+	case []interface{}:
+		for i, e := range x {
+			x[i] = tomlValue(e)
+		}
+		return x
+	}
+	return v
+}
@@ -0,0 +1,110 @@
+package parser
+
+import (
+	"math"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDecodeTOML(t *testing.T) {
+	src := `# comment
+title = "Say \"hi\" \u00e9"  # trailing comment
+path = 'C:\raw'
+"quoted key" = 1
+site.name = "dotted"
+hex = 0xff
+big = 1_000
+neg = -3.5e2
+inf = -inf
+ok = true
+date = 1979-05-27
+dt = 1979-05-27 07:32:00Z
+local = 1979-05-27T07:32:00
+clock = 07:32:00
+tags = [
+  "a",  # first
+  "b",
+]
+nested = [[1, 2], ["x"]]
+author = { name = "Ann", links = { web = "https://example.com" } }
+body = """
+Line one \
+  continued"""
+lit = '''
+no \escapes'''
+
+[params]
+color = "blue"
+
+[params.sub]
+n = 1
+
+[[menu]]
+name = "Home"
+
+[[menu]]
+name = "About"
+`
+	got, err := decodeTOML(src)
+	if err != nil {
+		t.Fatalf("decodeTOML: %v", err)
+	}
+	want := map[string]interface{}{
+		"title":      "Say \"hi\" é",
+		"path":       `C:\raw`,
+		"quoted key": 1,
+		"site":       map[string]interface{}{"name": "dotted"},
+		"hex":        255,
+		"big":        1000,
+		"neg":        -350.0,
+		"inf":        math.Inf(-1),
+		"ok":         true,
+		"date":       time.Date(1979, 5, 27, 0, 0, 0, 0, time.UTC),
+		"dt":         time.Date(1979, 5, 27, 7, 32, 0, 0, time.UTC),
+		"local":      time.Date(1979, 5, 27, 7, 32, 0, 0, time.UTC),
+		"clock":      "07:32:00",
+		"tags":       []interface{}{"a", "b"},
+		"nested":     []interface{}{[]interface{}{1, 2}, []interface{}{"x"}},
+		"author": map[string]interface{}{
+			"name":  "Ann",
+			"links": map[string]interface{}{"web": "https://example.com"},
+		},
+		"body": "Line one continued",
+		"lit":  `no \escapes`,
+		"params": map[string]interface{}{
+			"color": "blue",
+			"sub":   map[string]interface{}{"n": 1},
+		},
+		"menu": []interface{}{
+			map[string]interface{}{"name": "Home"},
+			map[string]interface{}{"name": "About"},
+		},
+	}
+	for k, w := range want {
+		if g := got[k]; !reflect.DeepEqual(g, w) {
+			t.Errorf("%s = %#v, want %#v", k, g, w)
+		}
+	}
+	if len(got) != len(want) {
+		t.Errorf("got %d keys, want %d", len(got), len(want))
+	}
+}
+
+func TestDecodeTOML_Errors(t *testing.T) {
+	for _, src := range []string{
+		`title = "unterminated`,
+		`title "missing equals"`,
+		"a = 1\na = 2",
+		`a = [1, 2`,
+		`a = 1 b = 2`,
+		`date = 2024-13-45`,
+		"[table\nx = 1",
+		"[a]\nx = 1\n[a]\ny = 2",
+		`a = nope`,
+	} {
+		if _, err := decodeTOML(src); err == nil {
+			t.Errorf("decodeTOML(%q): expected error", src)
+		}
+	}
+}
@@ -8,6 +8,7 @@
 //	    enabled: true
 //	    tag: "your-associate-tag-22"   # Amazon Associates tracking tag
 //	    recent_limit: 5                # optional; books exposed via SiteData (default 5)
+//	    title: "Bookshelf"             # optional; title of the page's search-index entry
 //
 // # Front-matter (article .md) — same key used by amazon_books plugin
 //
@@ -49,6 +50,7 @@ import (
 	"net/url"
 	"path"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/bmf-san/gohan/internal/model"
@@ -61,6 +63,7 @@ const (
 	imageURLTemplate = "https://images-na.ssl-images-amazon.com/images/P/%s.01._SL250_.jpg"
 	linkURLTemplate  = "https://www.amazon.co.jp/dp/%s?tag=%s"
 	defaultTag       = ""
+	defaultTitle     = "Bookshelf"
 
 	// defaultRecentLimit is the number of newest books exposed per locale via
 	// SiteData when `recent_limit` is not set in config.
@@ -99,6 +102,7 @@ var _ interface {
 	Enabled(map[string]interface{}) bool
 	VirtualPages(*model.Site, map[string]interface{}) ([]*model.VirtualPage, error)
 	SiteData(*model.Site, map[string]interface{}) (interface{}, error)
+	SearchDocuments(*model.Site, map[string]interface{}) ([]*model.SearchDocument, error)
 } = (*Bookshelf)(nil)
 
 // Name returns the plugin identifier.
@@ -131,22 +135,9 @@ func (b *Bookshelf) VirtualPages(site *model.Site, cfg map[string]interface{}) (
 		return nil, nil
 	}
 
-	defaultLocale := site.Config.I18n.DefaultLocale
-	if defaultLocale == "" {
-		defaultLocale = site.Config.Site.Language
-	}
-
 	var pages []*model.VirtualPage
 	for locale, entries := range byLocale {
-		var outputPath, pageURL string
-		if locale == defaultLocale || locale == "" {
-			outputPath = path.Join("bookshelf", "index.html")
-			pageURL = "/bookshelf/"
-		} else {
-			outputPath = path.Join(locale, "bookshelf", "index.html")
-			pageURL = "/" + locale + "/bookshelf/"
-		}
-
+		outputPath, pageURL := pagePaths(site, locale)
 		pages = append(pages, &model.VirtualPage{
 			OutputPath: outputPath,
 			URL:        pageURL,
@@ -196,6 +187,58 @@ func (b *Bookshelf) SiteData(site *model.Site, cfg map[string]interface{}) (inte
 	return map[string]interface{}{"recent": recent}, nil
 }
 
+// SearchDocuments implements plugin.SearchDocumentProvider. It returns one
+// document per bookshelf page, whose full-text covers the titles of the
+// books on it, so site search finds books as well as articles. The document
+// title is the `title` config key under plugins.bookshelf (default
+// "Bookshelf"), and its date is that of the newest book.
+func (b *Bookshelf) SearchDocuments(site *model.Site, cfg map[string]interface{}) ([]*model.SearchDocument, error) {
+	tag := strVal(cfg, "tag", defaultTag)
+	title := strVal(cfg, "title", defaultTitle)
+
+	byLocale, err := collectEntriesByLocale(site, tag)
+	if err != nil {
+		return nil, err
+	}
+
+	locales := make([]string, 0, len(byLocale))
+	for locale := range byLocale {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+
+	docs := make([]*model.SearchDocument, 0, len(locales))
+	for _, locale := range locales {
+		entries := byLocale[locale]
+		_, pageURL := pagePaths(site, locale)
+		titles := make([]string, len(entries))
+		for i, e := range entries {
+			titles[i] = e.Title
+		}
+		docs = append(docs, &model.SearchDocument{
+			Title:  title,
+			URL:    pageURL,
+			Date:   entries[0].Date,
+			Locale: locale,
+			Text:   strings.Join(titles, "\n"),
+		})
+	}
+	return docs, nil
+}
+
+// pagePaths returns the output path and URL of the bookshelf page for
+// locale. The default locale's page lives at the site root.
+func pagePaths(site *model.Site, locale string) (outputPath, pageURL string) {
+	defaultLocale := site.Config.I18n.DefaultLocale
+	if defaultLocale == "" {
+		defaultLocale = site.Config.Site.Language
+	}
+	if locale == defaultLocale || locale == "" {
+		return path.Join("bookshelf", "index.html"), "/bookshelf/"
+	}
+	return path.Join(locale, "bookshelf", "index.html"), "/" + locale + "/bookshelf/"
+}
+
 // collectEntriesByLocale aggregates book entries from every article's
 // front-matter, grouped by locale and sorted by date descending (newest
 // first). Returns an empty map when no article declares books.
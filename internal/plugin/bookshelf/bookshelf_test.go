@@ -584,3 +584,64 @@ func TestBookshelf_SiteData_PerLocale(t *testing.T) {
 		t.Errorf("ja recent = %+v, want single BBB", ja)
 	}
 }
+
+func TestBookshelf_SearchDocuments(t *testing.T) {
+	b := bookshelf.New()
+	older := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	newer := time.Date(2024, 5, 4, 0, 0, 0, 0, time.UTC)
+	site := &model.Site{
+		Config: model.Config{
+			Site: model.SiteConfig{Language: "en"},
+			I18n: model.I18nConfig{DefaultLocale: "en", Locales: []string{"en", "ja"}},
+		},
+		Articles: []*model.ProcessedArticle{
+			{
+				Article: model.Article{FrontMatter: model.FrontMatter{
+					Title: "Old", Date: older,
+					Extra: map[string]interface{}{
+						"books": []interface{}{map[string]interface{}{"asin": "AAA", "title": "Go Basics"}},
+					},
+				}},
+				Locale: "en",
+			},
+			{
+				Article: model.Article{FrontMatter: model.FrontMatter{
+					Title: "New", Date: newer,
+					Extra: map[string]interface{}{
+						"books": []interface{}{map[string]interface{}{"asin": "BBB", "title": "Go Advanced"}},
+					},
+				}},
+				Locale: "en",
+			},
+			{
+				Article: model.Article{FrontMatter: model.FrontMatter{
+					Title: "本", Date: older,
+					Extra: map[string]interface{}{
+						"books": []interface{}{map[string]interface{}{"asin": "CCC", "title": "入門"}},
+					},
+				}},
+				Locale: "ja",
+			},
+		},
+	}
+
+	c := cfg(true, "")
+	c["title"] = "Books"
+	docs, err := b.SearchDocuments(site, c)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(docs) != 2 {
+		t.Fatalf("expected 2 documents, got %d", len(docs))
+	}
+	en, ja := docs[0], docs[1]
+	if en.Title != "Books" || en.URL != "/bookshelf/" || en.Locale != "en" || !en.Date.Equal(newer) {
+		t.Errorf("en document = %+v", en)
+	}
+	if en.Text != "Go Advanced\nGo Basics" {
+		t.Errorf("en text = %q", en.Text)
+	}
+	if ja.URL != "/ja/bookshelf/" || ja.Text != "入門" {
+		t.Errorf("ja document = %+v", ja)
+	}
+}
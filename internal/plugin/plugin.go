@@ -69,3 +69,16 @@ type SiteDataProvider interface {
 	// every page. cfg is the map under plugins.<name> in config.yaml.
 	SiteData(site *model.Site, cfg map[string]interface{}) (interface{}, error)
 }
+
+// SearchDocumentProvider is an optional interface a SitePlugin may implement
+// to add records for its pages to search-index.json, so that client-side
+// search covers them alongside the articles.
+//
+// The returned documents are stored in Site.SearchDocuments with their
+// Plugin field set to the plugin name, and written after the articles to the
+// index of their locale.
+type SearchDocumentProvider interface {
+	// SearchDocuments inspects the full site and returns the records to
+	// index. cfg is the map under plugins.<name> in config.yaml.
+	SearchDocuments(site *model.Site, cfg map[string]interface{}) ([]*model.SearchDocument, error)
+}
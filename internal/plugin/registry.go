@@ -81,6 +81,21 @@ func (r *Registry) EnrichVirtual(site *model.Site) error {
 				site.SiteData[sp.Name()] = data
 			}
 		}
+
+		// Optionally collect search records for the plugin's pages.
+		if sdp, ok := sp.(SearchDocumentProvider); ok {
+			docs, err := sdp.SearchDocuments(site, cfg)
+			if err != nil {
+				return fmt.Errorf("site plugin %s search documents: %w", sp.Name(), err)
+			}
+			for _, doc := range docs {
+				if doc == nil {
+					continue
+				}
+				doc.Plugin = sp.Name()
+				site.SearchDocuments = append(site.SearchDocuments, doc)
+			}
+		}
 	}
 	return nil
 }
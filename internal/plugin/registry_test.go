@@ -80,3 +80,33 @@ func TestRegistry_Enrich_NoPluginsConfig(t *testing.T) {
 		t.Fatalf("unexpected error: %v", err)
 	}
 }
+
+func TestRegistry_EnrichVirtual_SearchDocuments(t *testing.T) {
+	site := &model.Site{
+		Config: model.Config{
+			Plugins: map[string]interface{}{
+				"bookshelf": map[string]interface{}{"enabled": true},
+			},
+		},
+		Articles: []*model.ProcessedArticle{
+			{
+				Article: model.Article{FrontMatter: model.FrontMatter{
+					Title: "A",
+					Extra: map[string]interface{}{
+						"books": []interface{}{map[string]interface{}{"asin": "4873119464", "title": "入門"}},
+					},
+				}},
+			},
+		},
+	}
+
+	if err := plugin.DefaultRegistry().EnrichVirtual(site); err != nil {
+		t.Fatalf("EnrichVirtual error: %v", err)
+	}
+	if len(site.SearchDocuments) != 1 {
+		t.Fatalf("expected 1 search document, got %d", len(site.SearchDocuments))
+	}
+	if doc := site.SearchDocuments[0]; doc.Plugin != "bookshelf" || doc.URL != "/bookshelf/" {
+		t.Errorf("unexpected search document %+v", doc)
+	}
+}
@@ -0,0 +1,2 @@
+// Package precompress writes gzip and brotli variants of text build outputs for servers that serve precompressed files.
+package precompress
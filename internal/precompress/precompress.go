@@ -0,0 +1,201 @@
+package precompress
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+
+	"github.com/bmf-san/gohan/internal/model"
+)
+
+// Encoding is a compression format whose variant is written next to the
+// source file under the source name plus Ext.
+type Encoding struct {
+	// Name is the Content-Encoding token, e.g. "br".
+	Name string
+	// Ext is the file name suffix of the variant, e.g. ".br".
+	Ext string
+}
+
+var (
+	// Brotli is the "br" encoding, written as ".br" files.
+	Brotli = Encoding{Name: "br", Ext: ".br"}
+	// Gzip is the "gzip" encoding, written as ".gz" files.
+	Gzip = Encoding{Name: "gzip", Ext: ".gz"}
+)
+
+// Encodings lists every supported encoding in order of preference.
+var Encodings = []Encoding{Brotli, Gzip}
+
+// compressible lists the extensions of the files that are compressed.
+var compressible = map[string]bool{
+	".html": true, ".css": true, ".js": true, ".json": true, ".xml": true, ".svg": true,
+}
+
+// Compressible reports whether path is a text format worth precompressing:
+// HTML, CSS, JS, JSON, XML, or SVG.
+func Compressible(path string) bool {
+	return compressible[strings.ToLower(filepath.Ext(path))]
+}
+
+// enabled returns the encodings selected by cfg.
+func enabled(cfg model.PrecompressConfig) []Encoding {
+	var encs []Encoding
+	if cfg.Brotli {
+		encs = append(encs, Brotli)
+	}
+	if cfg.Gzip {
+		encs = append(encs, Gzip)
+	}
+	return encs
+}
+
+// Variants returns the paths of every variant cfg could produce for paths,
+// regardless of file size. It is meant for planning, before the files exist.
+func Variants(paths []string, cfg model.PrecompressConfig) []string {
+	var out []string
+	for _, p := range paths {
+		if !Compressible(p) {
+			continue
+		}
+		for _, enc := range enabled(cfg) {
+			out = append(out, p+enc.Ext)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Write writes the variants selected by cfg for each compressible file in
+// paths of at least cfg.MinSize bytes, using up to parallelism workers.
+// A variant is kept as is when unchanged reports that both it and its
+// source are identical to the previous build. Write returns the paths of
+// all variants, written or kept, sorted.
+func Write(paths []string, cfg model.PrecompressConfig, parallelism int, unchanged func(path string) bool) ([]string, error) {
+	encs := enabled(cfg)
+	if len(encs) == 0 {
+		return nil, nil
+	}
+	if parallelism < 1 {
+		parallelism = 1
+	}
+	variants := make([][]string, len(paths))
+	errs := make([]error, len(paths))
+	sem := make(chan struct{}, parallelism)
+	var wg sync.WaitGroup
+	for i, p := range paths {
+		if !Compressible(p) {
+			continue
+		}
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, p string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			variants[i], errs[i] = writeVariants(p, encs, cfg.MinSize, unchanged)
+		}(i, p)
+	}
+	wg.Wait()
+	var out []string
+	for _, v := range variants {
+		out = append(out, v...)
+	}
+	sort.Strings(out)
+	return out, errors.Join(errs...)
+}
+
+// writeVariants writes the encs variants of src and returns their paths.
+func writeVariants(src string, encs []Encoding, minSize int, unchanged func(path string) bool) ([]string, error) {
+	info, err := os.Stat(src)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("precompress %s: %w", src, err)
+	}
+	if info.IsDir() || info.Size() < int64(minSize) {
+		return nil, nil
+	}
+	var data []byte
+	srcUnchanged := unchanged != nil && unchanged(src)
+	out := make([]string, 0, len(encs))
+	for _, enc := range encs {
+		dst := src + enc.Ext
+		out = append(out, dst)
+		if srcUnchanged && unchanged(dst) {
+			continue
+		}
+		if data == nil {
+			if data, err = os.ReadFile(src); err != nil {
+				return nil, fmt.Errorf("precompress %s: %w", src, err)
+			}
+		}
+		compressed, err := compress(enc, data)
+		if err == nil {
+			err = writeFileAtomic(dst, compressed, info.Mode().Perm())
+		}
+		if err != nil {
+			return nil, fmt.Errorf("precompress %s: %w", dst, err)
+		}
+	}
+	return out, nil
+}
+
+// compress returns data compressed with enc at its best compression level.
+func compress(enc Encoding, data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	var w io.WriteCloser
+	switch enc {
+	case Brotli:
+		w = brotli.NewWriterLevel(&buf, brotli.BestCompression)
+	case Gzip:
+		gw, err := gzip.NewWriterLevel(&buf, gzip.BestCompression)
+		if err != nil {
+			return nil, err
+		}
+		w = gw
+	default:
+		return nil, fmt.Errorf("unknown encoding %q", enc.Name)
+	}
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// writeFileAtomic writes data to a temporary file next to path and renames
+// it into place, so a server never reads a partially written variant.
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".gohan-tmp-")
+	if err != nil {
+		return err
+	}
+	tmpName := tmp.Name()
+	_, werr := tmp.Write(data)
+	cerr := tmp.Close()
+	if err := errors.Join(werr, cerr); err != nil {
+		_ = os.Remove(tmpName)
+		return err
+	}
+	if err := os.Chmod(tmpName, perm); err != nil {
+		_ = os.Remove(tmpName)
+		return err
+	}
+	if err := os.Rename(tmpName, path); err != nil {
+		_ = os.Remove(tmpName)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package precompress
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+
+	"github.com/bmf-san/gohan/internal/model"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	dir := t.TempDir()
+	page := filepath.Join(dir, "index.html")
+	small := filepath.Join(dir, "small.css")
+	image := filepath.Join(dir, "a.png")
+	content := strings.Repeat("<p>hello</p>\n", 100)
+	writeFile(t, page, content)
+	writeFile(t, small, "a{}")
+	writeFile(t, image, strings.Repeat("x", 2000))
+
+	cfg := model.PrecompressConfig{Gzip: true, Brotli: true, MinSize: 100}
+	got, err := Write([]string{page, small, image, filepath.Join(dir, "missing.js")}, cfg, 2, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{page + ".br", page + ".gz"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Write: got %v, want %v", got, want)
+	}
+
+	gz, err := os.ReadFile(page + ".gz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	gr, err := gzip.NewReader(bytes.NewReader(gz))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data, err := io.ReadAll(gr); err != nil || string(data) != content {
+		t.Errorf("gzip round trip: %v", err)
+	}
+	br, err := os.ReadFile(page + ".br")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data, err := io.ReadAll(brotli.NewReader(bytes.NewReader(br))); err != nil || string(data) != content {
+		t.Errorf("brotli round trip: %v", err)
+	}
+	if len(br) >= len(content) || len(gz) >= len(content) {
+		t.Errorf("variants not smaller than the source: br %d, gz %d, source %d", len(br), len(gz), len(content))
+	}
+}
+
+func TestWrite_KeepsUnchanged(t *testing.T) {
+	dir := t.TempDir()
+	page := filepath.Join(dir, "index.html")
+	writeFile(t, page, strings.Repeat("a", 200))
+	writeFile(t, page+".gz", "previous")
+
+	cfg := model.PrecompressConfig{Gzip: true, MinSize: 1}
+	unchanged := func(string) bool { return true }
+	if _, err := Write([]string{page}, cfg, 1, unchanged); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(page + ".gz"); string(data) != "previous" {
+		t.Error("an unchanged variant was recompressed")
+	}
+
+	changed := func(p string) bool { return p != page }
+	if _, err := Write([]string{page}, cfg, 1, changed); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(page + ".gz"); string(data) == "previous" {
+		t.Error("the variant of a changed file was not recompressed")
+	}
+}
+
+func TestVariants(t *testing.T) {
+	got := Variants([]string{"/p/index.html", "/p/a.png", "/p/feed.xml"}, model.PrecompressConfig{Brotli: true})
+	if want := []string{"/p/feed.xml.br", "/p/index.html.br"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Variants: got %v, want %v", got, want)
+	}
+	if got := Variants([]string{"/p/index.html"}, model.PrecompressConfig{}); got != nil {
+		t.Errorf("Variants with no encoding: got %v", got)
+	}
+}
@@ -0,0 +1,66 @@
+package processor
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/bmf-san/gohan/internal/model"
+)
+
+// Adjacent holds the chronological neighbours of an article.
+type Adjacent struct {
+	Prev *model.ProcessedArticle // next older article; nil for the oldest
+	Next *model.ProcessedArticle // next newer article; nil for the newest
+}
+
+// AdjacentArticles returns the neighbours of every article in articles that
+// has at least one. Articles are ordered by date (oldest first), then by
+// ContentPath, separately for each locale and content section (see
+// articleSection), so posts never link to pages. articles is expected to be
+// the build's article set, after draft and future-date filtering.
+func AdjacentArticles(articles []*model.ProcessedArticle) map[*model.ProcessedArticle]Adjacent {
+	type key struct{ locale, section string }
+	groups := make(map[key][]*model.ProcessedArticle)
+	for _, a := range articles {
+		k := key{a.Locale, articleSection(a)}
+		groups[k] = append(groups[k], a)
+	}
+	adj := make(map[*model.ProcessedArticle]Adjacent, len(articles))
+	for _, group := range groups {
+		if len(group) < 2 {
+			continue
+		}
+		sort.Slice(group, func(i, j int) bool {
+			di, dj := group[i].FrontMatter.Date, group[j].FrontMatter.Date
+			if !di.Equal(dj) {
+				return di.Before(dj)
+			}
+			return group[i].ContentPath < group[j].ContentPath
+		})
+		for i, a := range group {
+			var n Adjacent
+			if i > 0 {
+				n.Prev = group[i-1]
+			}
+			if i+1 < len(group) {
+				n.Next = group[i+1]
+			}
+			adj[a] = n
+		}
+	}
+	return adj
+}
+
+// articleSection returns the content section of a, like ContentSection, from
+// its ContentPath: the first directory below the locale directory, or "" for
+// files at the top of it.
+func articleSection(a *model.ProcessedArticle) string {
+	parts := strings.Split(a.ContentPath, "/")
+	if a.Locale != "" && len(parts) > 1 && parts[0] == a.Locale {
+		parts = parts[1:]
+	}
+	if len(parts) < 2 {
+		return ""
+	}
+	return parts[0]
+}
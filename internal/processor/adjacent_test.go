@@ -0,0 +1,56 @@
+package processor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bmf-san/gohan/internal/model"
+)
+
+func TestAdjacentArticles(t *testing.T) {
+	t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	mk := func(contentPath, locale string, date time.Time) *model.ProcessedArticle {
+		return &model.ProcessedArticle{
+			Article:     *testArticle(contentPath, contentPath, "", nil, nil, date),
+			ContentPath: contentPath,
+			Locale:      locale,
+		}
+	}
+	newest := mk("en/posts/newest.md", "en", t0.AddDate(0, 2, 0))
+	oldest := mk("en/posts/oldest.md", "en", t0)
+	tieB := mk("en/posts/b.md", "en", t0.AddDate(0, 1, 0))
+	tieA := mk("en/posts/a.md", "en", t0.AddDate(0, 1, 0))
+	about := mk("en/pages/about.md", "en", time.Time{})
+	contact := mk("en/pages/contact.md", "en", time.Time{})
+	ja := mk("ja/posts/hello.md", "ja", t0.AddDate(0, 1, 0))
+
+	adj := AdjacentArticles([]*model.ProcessedArticle{newest, oldest, tieB, tieA, about, contact, ja})
+	tests := []struct {
+		name       string
+		a          *model.ProcessedArticle
+		prev, next *model.ProcessedArticle
+	}{
+		{"oldest post", oldest, nil, tieA},
+		{"date tie ordered by path", tieA, oldest, tieB},
+		{"date tie ordered by path", tieB, tieA, newest},
+		{"newest post", newest, tieB, nil},
+		{"pages are a separate section", about, nil, contact},
+		{"pages are a separate section", contact, about, nil},
+	}
+	path := func(a *model.ProcessedArticle) string {
+		if a == nil {
+			return "<nil>"
+		}
+		return a.ContentPath
+	}
+	for _, tt := range tests {
+		got := adj[tt.a]
+		if got.Prev != tt.prev || got.Next != tt.next {
+			t.Errorf("%s: %s: got prev=%s next=%s, want prev=%s next=%s",
+				tt.name, tt.a.ContentPath, path(got.Prev), path(got.Next), path(tt.prev), path(tt.next))
+		}
+	}
+	if _, ok := adj[ja]; ok {
+		t.Error("the only ja post has neighbours")
+	}
+}
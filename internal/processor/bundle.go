@@ -0,0 +1,150 @@
+package processor
+
+import (
+	"io/fs"
+	"mime"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/bmf-san/gohan/internal/model"
+)
+
+// isBundleIndex reports whether name is the Markdown file that makes its
+// directory a leaf bundle.
+func isBundleIndex(name string) bool {
+	switch strings.ToLower(name) {
+	case "index.md", "index.markdown":
+		return true
+	}
+	return false
+}
+
+// IsLeafBundle reports whether a is the index.md of a leaf bundle: a
+// directory below the content directory (or below a locale directory) whose
+// other files are the article's resources. An index.md directly in the
+// content or locale directory is an ordinary article, and so is the index.md
+// of a section: a directory holding other Markdown files outside nested
+// bundles.
+func IsLeafBundle(a *model.Article, cfg model.Config) bool {
+	if !isBundleIndex(filepath.Base(a.FilePath)) {
+		return false
+	}
+	rel, err := filepath.Rel(cfg.Build.ContentDir, a.FilePath)
+	if err != nil {
+		return false
+	}
+	dir := filepath.ToSlash(filepath.Dir(rel))
+	if locale := detectLocale(a, cfg); locale != "" {
+		dir = strings.TrimPrefix(strings.TrimPrefix(dir, locale), "/")
+	}
+	if dir == "." || dir == "" || strings.HasPrefix(dir, "..") {
+		return false
+	}
+	return !hasOtherMarkdown(filepath.Dir(a.FilePath), a.FilePath)
+}
+
+// isMarkdownFile reports whether name has a Markdown extension.
+func isMarkdownFile(name string) bool {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".md", ".markdown":
+		return true
+	}
+	return false
+}
+
+// hasOtherMarkdown reports whether dir or its subdirectories, other than
+// nested bundles and dot-directories, contain a Markdown file besides index.
+func hasOtherMarkdown(dir, index string) bool {
+	found := false
+	_ = filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || p == dir {
+			return nil
+		}
+		if strings.HasPrefix(d.Name(), ".") || (d.IsDir() && hasBundleIndex(p)) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.IsDir() && p != index && isMarkdownFile(p) {
+			found = true
+			return fs.SkipAll
+		}
+		return nil
+	})
+	return found
+}
+
+// bundleResources lists the resources of the leaf bundle a, whose page is
+// written to outputPath. Markdown files, dotfiles, and nested bundles
+// (subdirectories with their own index.md) are not resources.
+func bundleResources(a *model.Article, outputPath string, cfg model.Config) ([]model.Resource, error) {
+	if !IsLeafBundle(a, cfg) {
+		return nil, nil
+	}
+	bundleDir := filepath.Dir(a.FilePath)
+	pageURL := "/"
+	if rel, err := filepath.Rel(cfg.Build.OutputDir, filepath.Dir(outputPath)); err == nil && rel != "." {
+		pageURL = "/" + filepath.ToSlash(rel) + "/"
+	}
+
+	var resources []model.Resource
+	err := filepath.WalkDir(bundleDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if p == bundleDir {
+			return nil
+		}
+		if strings.HasPrefix(d.Name(), ".") {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			if hasBundleIndex(p) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if isMarkdownFile(p) {
+			return nil
+		}
+		rel, err := filepath.Rel(bundleDir, p)
+		if err != nil {
+			return err
+		}
+		name := filepath.ToSlash(rel)
+		mediaType, _, _ := mime.ParseMediaType(mime.TypeByExtension(filepath.Ext(p)))
+		resources = append(resources, model.Resource{
+			Name:       name,
+			URL:        path.Join(pageURL, name),
+			MediaType:  mediaType,
+			SourcePath: p,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(resources, func(i, j int) bool { return resources[i].Name < resources[j].Name })
+	return resources, nil
+}
+
+// hasBundleIndex reports whether dir directly contains an index.md.
+func hasBundleIndex(dir string) bool {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return false
+	}
+	for _, e := range entries {
+		if !e.IsDir() && isBundleIndex(e.Name()) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,111 @@
+package processor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bmf-san/gohan/internal/model"
+)
+
+func TestSiteProcessor_Process_LeafBundle(t *testing.T) {
+	root := t.TempDir()
+	contentDir := filepath.Join(root, "content")
+	files := []string{
+		"posts/my-post/index.md",
+		"posts/my-post/cover.png",
+		"posts/my-post/images/diagram.svg",
+		"posts/my-post/.DS_Store",
+		"posts/my-post/nested/index.md",
+		"posts/my-post/nested/nested.png",
+	}
+	for _, f := range files {
+		p := filepath.Join(contentDir, filepath.FromSlash(f))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte("x"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cfg := model.Config{Build: model.BuildConfig{ContentDir: contentDir, OutputDir: filepath.Join(root, "public")}}
+	a := &model.Article{
+		FilePath:     filepath.Join(contentDir, "posts", "my-post", "index.md"),
+		RawContent:   "![cover](cover.png)",
+		LastModified: time.Now(),
+	}
+
+	processed, err := NewSiteProcessor().Process([]*model.Article{a}, cfg)
+	if err != nil {
+		t.Fatalf("Process: %v", err)
+	}
+	pa := processed[0]
+	if want := filepath.Join(root, "public", "posts", "my-post", "index.html"); pa.OutputPath != want {
+		t.Errorf("OutputPath: got %q, want %q", pa.OutputPath, want)
+	}
+	want := []model.Resource{
+		{Name: "cover.png", URL: "/posts/my-post/cover.png", MediaType: "image/png", SourcePath: filepath.Join(contentDir, "posts", "my-post", "cover.png")},
+		{Name: "images/diagram.svg", URL: "/posts/my-post/images/diagram.svg", MediaType: "image/svg+xml", SourcePath: filepath.Join(contentDir, "posts", "my-post", "images", "diagram.svg")},
+	}
+	if len(pa.Resources) != len(want) {
+		t.Fatalf("Resources: got %+v, want %+v", pa.Resources, want)
+	}
+	for i := range want {
+		if pa.Resources[i] != want[i] {
+			t.Errorf("Resources[%d]: got %+v, want %+v", i, pa.Resources[i], want[i])
+		}
+	}
+}
+
+func TestIsLeafBundle(t *testing.T) {
+	cfg := i18nCfg()
+	tests := []struct {
+		filePath string
+		want     bool
+	}{
+		{"content/en/posts/hello/index.md", true},
+		{"content/ja/hello/index.markdown", true},
+		{"content/en/index.md", false},
+		{"content/en/posts/hello.md", false},
+	}
+	for _, tt := range tests {
+		a := &model.Article{FilePath: tt.filePath}
+		if got := IsLeafBundle(a, cfg); got != tt.want {
+			t.Errorf("IsLeafBundle(%q) = %v, want %v", tt.filePath, got, tt.want)
+		}
+	}
+	if IsLeafBundle(&model.Article{FilePath: "content/index.md"}, model.Config{Build: model.BuildConfig{ContentDir: "content"}}) {
+		t.Error("content/index.md should not be a leaf bundle")
+	}
+}
+
+func TestIsLeafBundle_Section(t *testing.T) {
+	contentDir := filepath.Join(t.TempDir(), "content")
+	for _, f := range []string{
+		"posts/index.md",
+		"posts/hello.md",
+		"posts/logo.png",
+		"docs/index.md",
+		"docs/guide/intro.md",
+		"trip/index.md",
+		"trip/map.png",
+		"trip/day1/index.md",
+		"trip/.drafts/todo.md",
+	} {
+		p := filepath.Join(contentDir, filepath.FromSlash(f))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte("x"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cfg := model.Config{Build: model.BuildConfig{ContentDir: contentDir}}
+	for dir, want := range map[string]bool{"posts": false, "docs": false, "trip": true} {
+		a := &model.Article{FilePath: filepath.Join(contentDir, dir, "index.md")}
+		if got := IsLeafBundle(a, cfg); got != want {
+			t.Errorf("IsLeafBundle(%s/index.md) = %v, want %v", dir, got, want)
+		}
+	}
+}
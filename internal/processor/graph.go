@@ -2,10 +2,106 @@ package processor
 
 import (
 	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
 
 	"github.com/bmf-san/gohan/internal/model"
 )
 
+// TagNode returns the dependency-graph node path for the tag named name.
+func TagNode(name string) string { return "tag:" + name }
+
+// CategoryNode returns the dependency-graph node path for the category named name.
+func CategoryNode(name string) string { return "category:" + name }
+
+// ArchiveNode returns the dependency-graph node path for the year archive of year.
+func ArchiveNode(year int) string { return fmt.Sprintf("archive:%d", year) }
+
+// SeriesNode returns the dependency-graph node path for the series named name.
+func SeriesNode(name string) string { return "series:" + name }
+
+// TermNode returns the dependency-graph node path for term of the
+// user-defined taxonomy named taxonomy.
+func TermNode(taxonomy, term string) string { return "term:" + taxonomy + ":" + term }
+
+// PageNode returns the dependency-graph node path for the rendered page of the
+// article at contentPath (its ProcessedArticle.ContentPath). Articles shown on
+// another article's page, such as its previous and next articles, link to it.
+func PageNode(contentPath string) string { return "page:" + contentPath }
+
+// RelatedNode is the dependency-graph node path standing for the body text of
+// every article. Related articles scored by content depend on the term
+// frequencies of the whole site, so any article change impacts it.
+const RelatedNode = "related:"
+
+// nodeType infers the NodeType of a graph node from its path prefix.
+func nodeType(path string) model.NodeType {
+	switch {
+	case strings.HasPrefix(path, "tag:"):
+		return model.NodeTypeTag
+	case strings.HasPrefix(path, "category:"):
+		return model.NodeTypeCategory
+	case strings.HasPrefix(path, "archive:"):
+		return model.NodeTypeArchive
+	case strings.HasPrefix(path, "series:"):
+		return model.NodeTypeSeries
+	case strings.HasPrefix(path, "page:"):
+		return model.NodeTypePage
+	case strings.HasPrefix(path, "term:"):
+		return model.NodeTypeTerm
+	}
+	return model.NodeTypeArticle
+}
+
+// DependencyMap flattens g into the article → dependencies adjacency map
+// persisted in BuildManifest.Dependencies. Article paths are made relative to
+// contentDir (with forward slashes) so the map stays valid when the project is
+// checked out elsewhere. Every article appears as a key, even one without
+// dependencies, and each dependency list is sorted.
+func DependencyMap(g *model.DependencyGraph, contentDir string) map[string][]string {
+	deps := make(map[string][]string)
+	for path, n := range g.Nodes {
+		if n.Type != model.NodeTypeArticle {
+			continue
+		}
+		key := path
+		if contentDir != "" {
+			if rel, err := filepath.Rel(contentDir, path); err == nil {
+				key = filepath.ToSlash(rel)
+			}
+		}
+		list := append([]string{}, g.Edges[path]...)
+		sort.Strings(list)
+		deps[key] = list
+	}
+	return deps
+}
+
+// GraphFromDependencyMap rebuilds a DependencyGraph from a map produced by
+// DependencyMap, resolving article paths against contentDir so the result is
+// keyed like a graph from SiteProcessor.BuildDependencyGraph. Node
+// modification times are not persisted, so callers comparing the result with
+// CalculateDiff should rely on AddedFiles and DeletedFiles only.
+func GraphFromDependencyMap(deps map[string][]string, contentDir string) *model.DependencyGraph {
+	g := &model.DependencyGraph{
+		Nodes: make(map[string]*model.Node),
+		Edges: make(map[string][]string),
+	}
+	for rel, targets := range deps {
+		articlePath := filepath.FromSlash(rel)
+		if contentDir != "" {
+			articlePath = filepath.Join(contentDir, articlePath)
+		}
+		addNode(g, &model.Node{Path: articlePath, Type: model.NodeTypeArticle})
+		for _, to := range targets {
+			addNode(g, &model.Node{Path: to, Type: nodeType(to)})
+			addEdge(g, articlePath, to)
+		}
+	}
+	return g
+}
+
 // addNode inserts a node into the graph if it does not already exist.
 func addNode(g *model.DependencyGraph, node *model.Node) {
 	if _, exists := g.Nodes[node.Path]; !exists {
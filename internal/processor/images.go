@@ -0,0 +1,110 @@
+package processor
+
+import (
+	"net/url"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/bmf-san/gohan/internal/imageproc"
+	"github.com/bmf-san/gohan/internal/model"
+	"github.com/bmf-san/gohan/internal/parser"
+)
+
+// imageRef is a processed image referenced from an article body. It is
+// stored in the render cache so that a cached body can be checked against
+// the current image and its variants planned without converting again.
+type imageRef struct {
+	Source string `json:"source"`
+	URL    string `json:"url"`
+	Hash   string `json:"hash"`
+}
+
+// articleImages resolves the Markdown images of one article to source
+// files: bundle resources for relative destinations, and files in the assets
+// or static directory for site-root paths.
+type articleImages struct {
+	planner   *imageproc.Planner
+	cfg       model.Config
+	resources []model.Resource
+}
+
+// resolver returns the parser.ImageResolver for the article, appending every
+// image it processes to refs.
+func (ai *articleImages) resolver(refs *[]imageRef) parser.ImageResolver {
+	return func(dest string) *parser.ImageAttrs {
+		src, u := ai.source(dest)
+		if src == "" {
+			return nil
+		}
+		// An unreadable image is left as is; `gohan check` reports
+		// missing files.
+		img, err := ai.planner.Plan(src, u)
+		if err != nil || img == nil {
+			return nil
+		}
+		*refs = append(*refs, imageRef{Source: src, URL: u, Hash: img.Hash})
+		attrs := &parser.ImageAttrs{Width: img.Width, Height: img.Height}
+		if len(img.Variants) > 0 {
+			attrs.Srcset = img.Srcset()
+			attrs.Sizes = ai.cfg.Images.Sizes
+		}
+		return attrs
+	}
+}
+
+// source returns the file behind the image destination dest and its URL, or
+// "" for remote images and destinations that are not a known file.
+func (ai *articleImages) source(dest string) (string, string) {
+	u, err := url.Parse(dest)
+	if err != nil || u.Scheme != "" || u.Host != "" || u.Path == "" {
+		return "", ""
+	}
+	p := path.Clean(u.Path)
+	if !strings.HasPrefix(p, "/") {
+		for _, r := range ai.resources {
+			if r.Name == p {
+				return r.SourcePath, r.URL
+			}
+		}
+		return "", ""
+	}
+	if rest, ok := strings.CutPrefix(p, "/assets/"); ok && ai.cfg.Build.AssetsDir != "" {
+		return filepath.Join(ai.cfg.Build.AssetsDir, filepath.FromSlash(rest)), p
+	}
+	if ai.cfg.Build.StaticDir != "" {
+		return filepath.Join(ai.cfg.Build.StaticDir, filepath.FromSlash(p)), p
+	}
+	return "", ""
+}
+
+// current reports whether every image in refs is unchanged, so that a cached
+// body rendered with them is still valid.
+func (ai *articleImages) current(refs []imageRef) bool {
+	for _, r := range refs {
+		img, err := ai.planner.Plan(r.Source, r.URL)
+		if err != nil || img == nil || img.Hash != r.Hash {
+			return false
+		}
+	}
+	return true
+}
+
+// variants returns the variants of the images in refs, each listed once.
+func (ai *articleImages) variants(refs []imageRef) []model.ImageVariant {
+	var out []model.ImageVariant
+	seen := make(map[string]bool)
+	for _, r := range refs {
+		img, err := ai.planner.Plan(r.Source, r.URL)
+		if err != nil || img == nil {
+			continue
+		}
+		for _, v := range img.Variants {
+			if !seen[v.URL] {
+				seen[v.URL] = true
+				out = append(out, v)
+			}
+		}
+	}
+	return out
+}
@@ -0,0 +1,127 @@
+package processor
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bmf-san/gohan/internal/model"
+)
+
+func writeTestPNG(t *testing.T, path string, w, h int) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewNRGBA(image.Rect(0, 0, w, h))); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSiteProcessor_Process_Images(t *testing.T) {
+	root := t.TempDir()
+	contentDir := filepath.Join(root, "content")
+	writeTestPNG(t, filepath.Join(contentDir, "posts", "trip", "cover.png"), 1000, 500)
+	writeTestPNG(t, filepath.Join(root, "static", "images", "map.png"), 300, 300)
+	index := filepath.Join(contentDir, "posts", "trip", "index.md")
+	if err := os.WriteFile(index, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := model.Config{
+		Build: model.BuildConfig{
+			ContentDir: contentDir,
+			OutputDir:  filepath.Join(root, "public"),
+			StaticDir:  filepath.Join(root, "static"),
+		},
+		Images: model.ImagesConfig{Enabled: true, Widths: []int{480, 960}, Sizes: "100vw"},
+	}
+	a := &model.Article{
+		FilePath:   index,
+		RawContent: "![Cover](cover.png)\n\n![Map](/images/map.png)\n\n![Remote](https://example.com/x.png)\n",
+	}
+
+	p := NewSiteProcessor()
+	p.SetRenderCache(NewRenderCache(filepath.Join(root, "cache")))
+	processed, err := p.Process([]*model.Article{a}, cfg)
+	if err != nil {
+		t.Fatalf("Process: %v", err)
+	}
+	pa := processed[0]
+	html := string(pa.HTMLContent)
+	for _, want := range []string{
+		`<img src="cover.png" alt="Cover" width="1000" height="500" srcset="/posts/trip/cover-480w.png 480w, /posts/trip/cover-960w.png 960w, /posts/trip/cover.png 1000w" sizes="100vw">`,
+		`<img src="/images/map.png" alt="Map" width="300" height="300">`,
+		`<img src="https://example.com/x.png" alt="Remote">`,
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("expected %s in HTMLContent, got: %s", want, html)
+		}
+	}
+	if len(pa.ImageVariants) != 2 || pa.ImageVariants[0].URL != "/posts/trip/cover-480w.png" {
+		t.Errorf("ImageVariants: got %+v", pa.ImageVariants)
+	}
+
+	// Replacing the image invalidates the cached body even though the
+	// Markdown is unchanged.
+	writeTestPNG(t, filepath.Join(contentDir, "posts", "trip", "cover.png"), 800, 800)
+	p = NewSiteProcessor()
+	p.SetRenderCache(NewRenderCache(filepath.Join(root, "cache")))
+	processed, err = p.Process([]*model.Article{a}, cfg)
+	if err != nil {
+		t.Fatalf("Process: %v", err)
+	}
+	if html := string(processed[0].HTMLContent); !strings.Contains(html, `width="800" height="800"`) {
+		t.Errorf("stale cached body after image change: %s", html)
+	}
+	if len(processed[0].ImageVariants) != 1 {
+		t.Errorf("ImageVariants after image change: got %+v", processed[0].ImageVariants)
+	}
+}
+
+func TestSiteProcessor_Process_ImagesSameBodyInTwoBundles(t *testing.T) {
+	root := t.TempDir()
+	contentDir := filepath.Join(root, "content")
+	cfg := model.Config{
+		Build:  model.BuildConfig{ContentDir: contentDir, OutputDir: filepath.Join(root, "public")},
+		Images: model.ImagesConfig{Enabled: true, Widths: []int{480}, Sizes: "100vw"},
+	}
+	var articles []*model.Article
+	for name, size := range map[string]int{"a": 1000, "b": 600} {
+		writeTestPNG(t, filepath.Join(contentDir, "posts", name, "cover.png"), size, size)
+		index := filepath.Join(contentDir, "posts", name, "index.md")
+		if err := os.WriteFile(index, nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		articles = append(articles, &model.Article{FilePath: index, RawContent: "![](cover.png)\n"})
+	}
+
+	// The second build reads both bodies from the cache the first one wrote.
+	for build := 1; build <= 2; build++ {
+		p := NewSiteProcessor()
+		p.SetRenderCache(NewRenderCache(filepath.Join(root, "cache")))
+		processed, err := p.Process(articles, cfg)
+		if err != nil {
+			t.Fatalf("build %d: Process: %v", build, err)
+		}
+		for _, pa := range processed {
+			name := filepath.Base(filepath.Dir(pa.FilePath))
+			want := map[string]string{"a": `width="1000"`, "b": `width="600"`}[name]
+			if html := string(pa.HTMLContent); !strings.Contains(html, want) {
+				t.Errorf("build %d: %s: expected %s, got %s", build, name, want, html)
+			}
+			for _, v := range pa.ImageVariants {
+				if !strings.HasPrefix(v.URL, "/posts/"+name+"/") {
+					t.Errorf("build %d: %s has variant %s of another bundle", build, name, v.URL)
+				}
+			}
+		}
+	}
+}
@@ -16,11 +16,16 @@ type Processor interface {
 	// complete set of ProcessedArticles.
 	BuildDependencyGraph(articles []*model.ProcessedArticle) (*model.DependencyGraph, error)
 
-	// BuildTaxonomyRegistry collects all tags and categories referenced across
-	// all articles and validates them against the configured taxonomy YAML files.
+	// BuildTaxonomyRegistry collects all tags, categories, and user-defined
+	// taxonomy terms referenced across all articles and validates them against
+	// the configured taxonomy YAML files.
 	BuildTaxonomyRegistry(articles []*model.ProcessedArticle, cfg model.Config) (*model.TaxonomyRegistry, error)
 
 	// BuildTranslationMap links articles that share a TranslationKey by
 	// populating their Translations field. Should be called after Process.
 	BuildTranslationMap(articles []*model.ProcessedArticle)
+
+	// BuildSeries groups the articles that declare a series into ordered
+	// series, one per locale and series name.
+	BuildSeries(articles []*model.ProcessedArticle) []*model.Series
 }
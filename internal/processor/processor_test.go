@@ -1,6 +1,7 @@
 package processor
 
 import (
+	"fmt"
 	"path/filepath"
 	"strings"
 	"testing"
@@ -48,6 +49,30 @@ func TestSiteProcessor_Process_Basic(t *testing.T) {
 	}
 }
 
+func TestSiteProcessor_Process_ParallelKeepsOrder(t *testing.T) {
+	p := NewSiteProcessor()
+	var articles []*model.Article
+	for i := 0; i < 20; i++ {
+		articles = append(articles, testArticle(fmt.Sprintf("%02d.md", i), fmt.Sprintf("T%d", i), fmt.Sprintf("body %d", i), nil, nil, time.Time{}))
+	}
+	cfg := model.Config{Build: model.BuildConfig{Parallelism: 4}}
+	got, err := p.Process(articles, cfg)
+	if err != nil {
+		t.Fatalf("Process: %v", err)
+	}
+	if len(got) != len(articles) {
+		t.Fatalf("expected %d articles, got %d", len(articles), len(got))
+	}
+	for i, pa := range got {
+		if pa.FilePath != articles[i].FilePath {
+			t.Errorf("result[%d] = %s, want %s", i, pa.FilePath, articles[i].FilePath)
+		}
+		if want := fmt.Sprintf("body %d", i); !strings.Contains(string(pa.HTMLContent), want) {
+			t.Errorf("result[%d] HTML %q does not contain %q", i, pa.HTMLContent, want)
+		}
+	}
+}
+
 func TestSiteProcessor_Process_OutputPath_WithSlug(t *testing.T) {
 	p := NewSiteProcessor()
 	a := &model.Article{
@@ -89,8 +114,8 @@ func TestSiteProcessor_BuildDependencyGraph(t *testing.T) {
 	p := NewSiteProcessor()
 	date := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
 	articles := []*model.ProcessedArticle{
-		{Article: *testArticle("a.md", "A", "", []string{"go", "ssg"}, []string{"news"}, date)},
-		{Article: *testArticle("b.md", "B", "", []string{"go"}, nil, time.Time{})},
+		{Article: *testArticle("a.md", "A", "", []string{"go", "ssg"}, []string{"news"}, date), ContentPath: "a.md"},
+		{Article: *testArticle("b.md", "B", "", []string{"go"}, nil, time.Time{}), ContentPath: "b.md"},
 	}
 	g, err := p.BuildDependencyGraph(articles)
 	if err != nil {
@@ -111,8 +136,51 @@ func TestSiteProcessor_BuildDependencyGraph(t *testing.T) {
 	if _, ok := g.Nodes["archive:2024"]; !ok {
 		t.Error("expected archive:2024 node")
 	}
-	if len(g.Edges["a.md"]) != 4 {
-		t.Errorf("a.md: expected 4 edges, got %d: %v", len(g.Edges["a.md"]), g.Edges["a.md"])
+	// b (undated) is a's previous article, so a's change impacts b's page.
+	if n, ok := g.Nodes["page:b.md"]; !ok || n.Type != model.NodeTypePage {
+		t.Errorf("page:b.md node: %+v", n)
+	}
+	if len(g.Edges["a.md"]) != 5 {
+		t.Errorf("a.md: expected 5 edges, got %d: %v", len(g.Edges["a.md"]), g.Edges["a.md"])
+	}
+}
+
+func TestDependencyMap_RoundTrip(t *testing.T) {
+	contentDir := filepath.Join("/site", "content")
+	date := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	articles := []*model.ProcessedArticle{
+		{Article: *testArticle(filepath.Join(contentDir, "posts", "a.md"), "A", "", []string{"ssg", "go"}, []string{"news"}, date), ContentPath: "posts/a.md"},
+		{Article: *testArticle(filepath.Join(contentDir, "b.md"), "B", "", nil, nil, time.Time{}), ContentPath: "b.md"},
+	}
+	g, err := NewSiteProcessor().BuildDependencyGraph(articles)
+	if err != nil {
+		t.Fatalf("BuildDependencyGraph: %v", err)
+	}
+	deps := DependencyMap(g, contentDir)
+	want := []string{"archive:2024", "category:news", "tag:go", "tag:ssg"}
+	if strings.Join(deps["posts/a.md"], ",") != strings.Join(want, ",") {
+		t.Errorf("posts/a.md deps: got %v, want %v", deps["posts/a.md"], want)
+	}
+	if d, ok := deps["b.md"]; !ok || len(d) != 0 {
+		t.Errorf("b.md: expected an empty entry, got %v (present=%v)", d, ok)
+	}
+
+	back := GraphFromDependencyMap(deps, contentDir)
+	a := filepath.Join(contentDir, "posts", "a.md")
+	if n, ok := back.Nodes[a]; !ok || n.Type != model.NodeTypeArticle {
+		t.Fatalf("expected article node %s", a)
+	}
+	if n, ok := back.Nodes["tag:go"]; !ok || n.Type != model.NodeTypeTag || len(n.Dependents) != 1 {
+		t.Errorf("tag:go node: %+v", n)
+	}
+	if n, ok := back.Nodes["category:news"]; !ok || n.Type != model.NodeTypeCategory {
+		t.Errorf("category:news node: %+v", n)
+	}
+	if n, ok := back.Nodes["archive:2024"]; !ok || n.Type != model.NodeTypeArchive {
+		t.Errorf("archive:2024 node: %+v", n)
+	}
+	if _, ok := back.Nodes[filepath.Join(contentDir, "b.md")]; !ok {
+		t.Error("expected article node for b.md")
 	}
 }
 
@@ -134,6 +202,59 @@ func TestSiteProcessor_BuildTaxonomyRegistry(t *testing.T) {
 	}
 }
 
+func TestArticleTerms(t *testing.T) {
+	cfg := model.Config{Taxonomies: []model.TaxonomyConfig{{Name: "authors"}, {Name: "genres"}, {Name: "moods"}}}
+	a := &model.Article{FrontMatter: model.FrontMatter{Extra: map[string]interface{}{
+		"authors": []interface{}{"alice", " bob ", "alice", ""},
+		"genres":  "essay",
+		"series":  "ignored: not a configured taxonomy",
+	}}}
+	got := articleTerms(a, cfg)
+	if strings.Join(got["authors"], ",") != "alice,bob" {
+		t.Errorf("authors: got %v, want [alice bob]", got["authors"])
+	}
+	if strings.Join(got["genres"], ",") != "essay" {
+		t.Errorf("genres: got %v, want [essay]", got["genres"])
+	}
+	if _, ok := got["moods"]; ok || len(got) != 2 {
+		t.Errorf("got %v, want only authors and genres", got)
+	}
+	if got := articleTerms(&model.Article{}, cfg); got != nil {
+		t.Errorf("no terms: got %v, want nil", got)
+	}
+}
+
+func TestSiteProcessor_Taxonomies(t *testing.T) {
+	p := NewSiteProcessor()
+	articles := []*model.ProcessedArticle{
+		{Article: *testArticle("a.md", "A", "", nil, nil, time.Time{}), ContentPath: "a.md", Taxonomies: map[string][]string{"authors": {"alice", "bob"}}},
+		{Article: *testArticle("b.md", "B", "", nil, nil, time.Time{}), ContentPath: "b.md", Taxonomies: map[string][]string{"authors": {"alice"}}},
+	}
+	cfg := model.Config{Taxonomies: []model.TaxonomyConfig{{Name: "authors"}, {Name: "genres"}}}
+	reg, err := p.BuildTaxonomyRegistry(articles, cfg)
+	if err != nil {
+		t.Fatalf("BuildTaxonomyRegistry: %v", err)
+	}
+	if got := reg.Taxonomies["authors"]; len(got) != 2 || got[0].Name != "alice" || got[1].Name != "bob" {
+		t.Errorf("authors: got %v, want [alice bob]", got)
+	}
+	if _, ok := reg.Taxonomies["genres"]; ok {
+		t.Error("genres has no terms and should have no entry")
+	}
+
+	g, err := p.BuildDependencyGraph(articles)
+	if err != nil {
+		t.Fatalf("BuildDependencyGraph: %v", err)
+	}
+	n, ok := g.Nodes["term:authors:alice"]
+	if !ok || n.Type != model.NodeTypeTerm || len(n.Dependents) != 2 {
+		t.Errorf("term:authors:alice node: %+v", n)
+	}
+	if back := GraphFromDependencyMap(DependencyMap(g, ""), ""); back.Nodes["term:authors:bob"] == nil || back.Nodes["term:authors:bob"].Type != model.NodeTypeTerm {
+		t.Errorf("term:authors:bob should round-trip as a term node: %+v", back.Nodes["term:authors:bob"])
+	}
+}
+
 func TestCalculateImpact(t *testing.T) {
 	g := &model.DependencyGraph{
 		Nodes: map[string]*model.Node{
@@ -435,3 +556,22 @@ func TestProcess_ContentPathSet(t *testing.T) {
 		t.Errorf("ContentPath: got %q, want %q", processed[0].ContentPath, want)
 	}
 }
+
+func TestApplyGitInfo(t *testing.T) {
+	commit := time.Date(2025, 2, 3, 0, 0, 0, 0, time.UTC)
+	tracked := &model.ProcessedArticle{Article: *testArticle("/c/posts/a.md", "A", "", nil, nil, time.Time{}), ContentPath: "posts/a.md"}
+	untracked := &model.ProcessedArticle{Article: *testArticle("/c/posts/b.md", "B", "", nil, nil, time.Time{}), ContentPath: "posts/b.md"}
+	mtime := untracked.LastModified
+	ApplyGitInfo([]*model.ProcessedArticle{tracked, untracked}, map[string]*model.GitInfo{
+		"posts/a.md": {Hash: "abc", LastCommitDate: commit},
+	})
+	if tracked.GitInfo == nil || tracked.GitInfo.Hash != "abc" {
+		t.Errorf("tracked GitInfo: %+v", tracked.GitInfo)
+	}
+	if !tracked.LastModified.Equal(commit) {
+		t.Errorf("tracked LastModified: got %v, want %v", tracked.LastModified, commit)
+	}
+	if untracked.GitInfo != nil || !untracked.LastModified.Equal(mtime) {
+		t.Errorf("untracked article changed: %+v", untracked)
+	}
+}
@@ -0,0 +1,164 @@
+package processor
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/bmf-san/gohan/internal/model"
+)
+
+// RenderCacheDir is the name of the render cache directory inside the build
+// cache directory (.gohan/cache/render).
+const RenderCacheDir = "render"
+
+// renderCacheVersion is mixed into every key; bump it whenever the converter
+// pipeline or the stored entry format changes so old entries are ignored.
+const renderCacheVersion = "2"
+
+// renderEntry is the part of a ProcessedArticle derived solely from the
+// article's Markdown body and the converter options.
+type renderEntry struct {
+	HTMLContent template.HTML    `json:"html_content"`
+	TOC         []model.TOCEntry `json:"toc"`
+	Summary     string           `json:"summary"`
+	WordCount   int              `json:"word_count"`
+	// Images lists the processed images the body embeds; empty unless
+	// images.enabled is set.
+	Images []imageRef `json:"images,omitempty"`
+}
+
+// RenderCache is a content-addressed on-disk cache of rendered article
+// bodies. Entries are keyed by a SHA-256 of the Markdown body and the
+// converter options (syntax highlight theme, line numbers, GFM, Mermaid,
+// image widths and sizes, and with images the article's paths), so an entry
+// never goes stale: any change to its
+// inputs yields a new key. Entries embedding processed images are also
+// checked against the current content of those images.
+// RenderCache is safe for concurrent use.
+type RenderCache struct {
+	dir string
+
+	mu     sync.Mutex
+	used   map[string]bool
+	hits   int
+	misses int
+}
+
+// NewRenderCache returns a RenderCache storing entries under dir.
+func NewRenderCache(dir string) *RenderCache {
+	return &RenderCache{dir: dir, used: make(map[string]bool)}
+}
+
+// renderCacheKey returns the cache key for a Markdown body rendered with the
+// converter options described by cfg. With images.enabled the rendered body
+// also depends on the images its relative destinations resolve to, so the
+// key includes scope, the source and output paths of the article; two
+// bundles with the same Markdown never share an entry.
+func renderCacheKey(raw, scope string, cfg model.Config) string {
+	h := sha256.New()
+	// Only the highlighting and image options vary; GFM and Mermaid are
+	// always enabled.
+	fmt.Fprintf(h, "v%s\x00theme=%s\x00lines=%t\x00gfm\x00mermaid\x00",
+		renderCacheVersion, cfg.SyntaxHighlight.Theme, cfg.SyntaxHighlight.LineNumbers)
+	if cfg.Images.Enabled {
+		fmt.Fprintf(h, "images=%v\x00sizes=%s\x00scope=%s\x00", cfg.Images.Widths, cfg.Images.Sizes, scope)
+	}
+	h.Write([]byte(raw))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func (c *RenderCache) path(key string) string {
+	return filepath.Join(c.dir, key[:2], key+".json")
+}
+
+// get returns the entry stored under key. A missing or unreadable entry is a
+// miss.
+func (c *RenderCache) get(key string) (*renderEntry, bool) {
+	c.mu.Lock()
+	c.used[key] = true
+	c.mu.Unlock()
+
+	data, err := os.ReadFile(c.path(key))
+	var e renderEntry
+	if err == nil {
+		err = json.Unmarshal(data, &e)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err != nil {
+		c.misses++
+		return nil, false
+	}
+	c.hits++
+	return &e, true
+}
+
+// put stores e under key, writing to a temporary file first so that an
+// interrupted build never leaves a truncated entry behind.
+func (c *RenderCache) put(key string, e *renderEntry) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	path := c.path(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-")
+	if err != nil {
+		return err
+	}
+	_, werr := tmp.Write(data)
+	cerr := tmp.Close()
+	if err := errors.Join(werr, cerr); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	return nil
+}
+
+// Stats returns the number of cache hits and misses since the cache was
+// created.
+func (c *RenderCache) Stats() (hits, misses int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.hits, c.misses
+}
+
+// Prune deletes every entry that was not looked up since the cache was
+// created, so that entries for deleted or edited articles do not accumulate.
+// Call it after Process has seen the complete article set.
+func (c *RenderCache) Prune() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	err := filepath.WalkDir(c.dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		key := strings.TrimSuffix(d.Name(), ".json")
+		if c.used[key] {
+			return nil
+		}
+		return os.Remove(path)
+	})
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,100 @@
+package processor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bmf-san/gohan/internal/model"
+)
+
+func TestRenderCache_ReusesUnchangedArticles(t *testing.T) {
+	dir := t.TempDir()
+	a := testArticle("a.md", "A", "# Heading\n\nSome body text.\n", nil, nil, time.Time{})
+	cfg := model.Config{}
+
+	first := NewSiteProcessor()
+	first.SetRenderCache(NewRenderCache(dir))
+	want, err := first.Process([]*model.Article{a}, cfg)
+	if err != nil {
+		t.Fatalf("Process: %v", err)
+	}
+
+	cache := NewRenderCache(dir)
+	second := NewSiteProcessor()
+	second.SetRenderCache(cache)
+	got, err := second.Process([]*model.Article{a}, cfg)
+	if err != nil {
+		t.Fatalf("Process: %v", err)
+	}
+	if hits, misses := cache.Stats(); hits != 1 || misses != 0 {
+		t.Errorf("Stats: got %d hits, %d misses, want 1, 0", hits, misses)
+	}
+	if got[0].HTMLContent != want[0].HTMLContent || got[0].Summary != want[0].Summary || got[0].WordCount != want[0].WordCount {
+		t.Errorf("cached article differs: got %+v, want %+v", got[0], want[0])
+	}
+	if len(got[0].TOC) != 1 || got[0].TOC[0].Text != "Heading" {
+		t.Errorf("TOC: got %+v", got[0].TOC)
+	}
+}
+
+func TestRenderCache_KeyIncludesOptions(t *testing.T) {
+	base := renderCacheKey("body", "", model.Config{})
+	if renderCacheKey("body", "", model.Config{}) != base {
+		t.Error("key is not deterministic")
+	}
+	if renderCacheKey("body!", "", model.Config{}) == base {
+		t.Error("key ignores the Markdown body")
+	}
+	var themed model.Config
+	themed.SyntaxHighlight.Theme = "monokai"
+	if renderCacheKey("body", "", themed) == base {
+		t.Error("key ignores the highlight theme")
+	}
+	var numbered model.Config
+	numbered.SyntaxHighlight.LineNumbers = true
+	if renderCacheKey("body", "", numbered) == base {
+		t.Error("key ignores line numbers")
+	}
+	if renderCacheKey("body", "a.md", model.Config{}) != base {
+		t.Error("key depends on the article without images")
+	}
+	imaged := model.Config{Images: model.ImagesConfig{Enabled: true}}
+	if renderCacheKey("body", "a.md", imaged) == renderCacheKey("body", "b.md", imaged) {
+		t.Error("key ignores the article with images")
+	}
+}
+
+func TestRenderCache_Prune(t *testing.T) {
+	dir := t.TempDir()
+	a := testArticle("a.md", "A", "old body", nil, nil, time.Time{})
+	p := NewSiteProcessor()
+	p.SetRenderCache(NewRenderCache(dir))
+	if _, err := p.Process([]*model.Article{a}, model.Config{}); err != nil {
+		t.Fatalf("Process: %v", err)
+	}
+	stale := NewRenderCache(dir).path(renderCacheKey("old body", "", model.Config{}))
+
+	a.RawContent = "new body"
+	cache := NewRenderCache(dir)
+	p.SetRenderCache(cache)
+	if _, err := p.Process([]*model.Article{a}, model.Config{}); err != nil {
+		t.Fatalf("Process: %v", err)
+	}
+	if err := cache.Prune(); err != nil {
+		t.Fatalf("Prune: %v", err)
+	}
+	if _, err := os.Stat(stale); !os.IsNotExist(err) {
+		t.Errorf("expected stale entry to be pruned, stat err = %v", err)
+	}
+	if _, err := os.Stat(cache.path(renderCacheKey("new body", "", model.Config{}))); err != nil {
+		t.Errorf("expected current entry to be kept: %v", err)
+	}
+}
+
+func TestRenderCache_PruneMissingDir(t *testing.T) {
+	if err := NewRenderCache(filepath.Join(t.TempDir(), "none")).Prune(); err != nil {
+		t.Errorf("Prune on missing dir: %v", err)
+	}
+}
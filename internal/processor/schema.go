@@ -0,0 +1,204 @@
+package processor
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bmf-san/gohan/internal/model"
+)
+
+// SchemaError is returned by ValidateContentSchema for a front matter field
+// that violates the content schema of its section.
+type SchemaError struct {
+	FilePath string // path of the offending article
+	Field    string // front matter key
+	Message  string // what is wrong, e.g. "required field is missing"
+}
+
+func (e *SchemaError) Error() string {
+	return fmt.Sprintf("article %q: %s: %s", e.FilePath, e.Field, e.Message)
+}
+
+// ContentSection returns the content section of the article at filePath: the
+// first directory below cfg.Build.ContentDir, skipping the locale directory
+// when i18n is enabled. Files directly in the content (or locale) directory
+// have no section and return "".
+func ContentSection(filePath string, cfg model.Config) string {
+	rel, err := filepath.Rel(cfg.Build.ContentDir, filePath)
+	if err != nil {
+		return ""
+	}
+	parts := strings.Split(filepath.ToSlash(rel), "/")
+	if len(parts) > 1 && slices.Contains(cfg.I18n.Locales, parts[0]) {
+		parts = parts[1:]
+	}
+	if len(parts) < 2 {
+		return ""
+	}
+	return parts[0]
+}
+
+// ValidateContentSchema checks the front matter of each article against the
+// schema of its section in cfg.ContentSchema, falling back to the "*" entry.
+// It returns one error per violation, ordered by article and field. The
+// schema is assumed to have been validated by config.Load.
+func ValidateContentSchema(articles []*model.Article, cfg model.Config) []error {
+	if len(cfg.ContentSchema) == 0 {
+		return nil
+	}
+	patterns := map[string]*regexp.Regexp{}
+	var errs []error
+	for _, a := range articles {
+		s, ok := cfg.ContentSchema[ContentSection(a.FilePath, cfg)]
+		if !ok {
+			if s, ok = cfg.ContentSchema["*"]; !ok {
+				continue
+			}
+		}
+		fields := make([]string, 0, len(s.Fields))
+		for f := range s.Fields {
+			fields = append(fields, f)
+		}
+		sort.Strings(fields)
+		for _, f := range fields {
+			fs := s.Fields[f]
+			if fs.Pattern != "" && patterns[fs.Pattern] == nil {
+				patterns[fs.Pattern] = regexp.MustCompile(fs.Pattern)
+			}
+			for _, msg := range checkField(a.FrontMatter, f, fs, patterns[fs.Pattern]) {
+				errs = append(errs, &SchemaError{FilePath: a.FilePath, Field: f, Message: msg})
+			}
+		}
+	}
+	return errs
+}
+
+// checkField returns the violations of field in fm. pattern is fs.Pattern
+// compiled, or nil.
+func checkField(fm model.FrontMatter, field string, fs model.FieldSchema, pattern *regexp.Regexp) []string {
+	v, ok := FrontMatterValue(fm, field)
+	if !ok {
+		if fs.Required {
+			return []string{"required field is missing"}
+		}
+		return nil
+	}
+	if fs.Type != "" && !hasSchemaType(v, fs.Type) {
+		return []string{fmt.Sprintf("must be of type %s, got %s", fs.Type, schemaTypeOf(v))}
+	}
+	if len(fs.Enum) == 0 && pattern == nil {
+		return nil
+	}
+	values := []any{v}
+	if list, isList := v.([]any); isList {
+		values = list
+	}
+	var msgs []string
+	for _, e := range values {
+		s, scalar := scalarString(e)
+		switch {
+		case !scalar:
+			msgs = append(msgs, fmt.Sprintf("%s value cannot be matched against enum or pattern", schemaTypeOf(e)))
+		case len(fs.Enum) > 0 && !slices.Contains(fs.Enum, s):
+			msgs = append(msgs, fmt.Sprintf("value %q is not one of %s", s, strings.Join(fs.Enum, ", ")))
+		case pattern != nil && !pattern.MatchString(s):
+			msgs = append(msgs, fmt.Sprintf("value %q does not match pattern %s", s, fs.Pattern))
+		}
+	}
+	return msgs
+}
+
+// FrontMatterValue returns the value of the front matter key field, in the
+// form the YAML decoder produces for Extra values. Built-in fields are
+// present when they are not empty.
+func FrontMatterValue(fm model.FrontMatter, field string) (any, bool) {
+	str := func(s string) (any, bool) { return s, s != "" }
+	date := func(t time.Time) (any, bool) { return t, !t.IsZero() }
+	list := func(l []string) (any, bool) {
+		out := make([]any, len(l))
+		for i, s := range l {
+			out[i] = s
+		}
+		return out, len(l) > 0
+	}
+	switch field {
+	case "title":
+		return str(fm.Title)
+	case "date":
+		return date(fm.Date)
+	case "lastmod":
+		return date(fm.LastMod)
+	case "draft":
+		return fm.Draft, fm.Draft
+	case "tags":
+		return list(fm.Tags)
+	case "categories":
+		return list(fm.Categories)
+	case "description":
+		return str(fm.Description)
+	case "author":
+		return str(fm.Author)
+	case "slug":
+		return str(fm.Slug)
+	case "template":
+		return str(fm.Template)
+	case "translation_key":
+		return str(fm.TranslationKey)
+	case "listing_slugs":
+		return list(fm.ListingSlugs)
+	case "series":
+		return str(fm.Series)
+	case "series_order":
+		return fm.SeriesOrder, fm.SeriesOrder != 0
+	case "related":
+		return list(fm.Related)
+	case "search_exclude":
+		return fm.SearchExclude, fm.SearchExclude
+	}
+	v, ok := fm.Extra[field]
+	return v, ok && v != nil
+}
+
+// hasSchemaType reports whether v is of the FieldSchema type typ.
+func hasSchemaType(v any, typ string) bool {
+	actual := schemaTypeOf(v)
+	return actual == typ || (typ == "float" && actual == "int")
+}
+
+// schemaTypeOf returns the FieldSchema type name of a decoded front matter
+// value.
+func schemaTypeOf(v any) string {
+	switch v.(type) {
+	case string:
+		return "string"
+	case int, int64, uint64:
+		return "int"
+	case float64:
+		return "float"
+	case bool:
+		return "bool"
+	case time.Time:
+		return "date"
+	case []any:
+		return "list"
+	case map[string]any:
+		return "map"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+// scalarString returns the string form of a string, number, or boolean.
+func scalarString(v any) (string, bool) {
+	switch x := v.(type) {
+	case string:
+		return x, true
+	case int, int64, uint64, float64, bool:
+		return fmt.Sprint(x), true
+	}
+	return "", false
+}
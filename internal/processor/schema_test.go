@@ -0,0 +1,88 @@
+package processor
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bmf-san/gohan/internal/model"
+)
+
+func TestContentSection(t *testing.T) {
+	cfg := model.Config{
+		Build: model.BuildConfig{ContentDir: "/c"},
+		I18n:  model.I18nConfig{Locales: []string{"en", "ja"}},
+	}
+	tests := map[string]string{
+		"/c/posts/a.md":          "posts",
+		"/c/ja/posts/a.md":       "posts",
+		"/c/tutorial/x/y/z.md":   "tutorial",
+		"/c/about.md":            "",
+		"/c/ja/about.md":         "",
+		"/c/en/pages/legal.md":   "pages",
+		"/c/fr/posts/bonjour.md": "fr",
+	}
+	for path, want := range tests {
+		if got := ContentSection(filepath.FromSlash(path), cfg); got != want {
+			t.Errorf("ContentSection(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
+
+func TestValidateContentSchema(t *testing.T) {
+	cfg := model.Config{
+		Build: model.BuildConfig{ContentDir: "/c"},
+		ContentSchema: map[string]model.SectionSchema{
+			"posts": {Fields: map[string]model.FieldSchema{
+				"description": {Required: true},
+				"tags":        {Enum: []string{"go", "web"}},
+				"series":      {Type: "string", Pattern: "^[a-z-]+$"},
+				"rating":      {Type: "float"},
+				"featured":    {Type: "bool"},
+			}},
+			"*": {Fields: map[string]model.FieldSchema{"title": {Required: true}}},
+		},
+	}
+	articles := []*model.Article{
+		{FilePath: "/c/posts/ok.md", FrontMatter: model.FrontMatter{
+			Description: "d", Tags: []string{"go"}, Series: "intro-to-go",
+			Extra: map[string]interface{}{"rating": 4, "featured": true},
+		}},
+		{FilePath: "/c/posts/bad.md", FrontMatter: model.FrontMatter{
+			Tags: []string{"go", "rust"}, Series: "Intro",
+			Extra: map[string]interface{}{"rating": "high", "featured": time.Now()},
+		}},
+		{FilePath: "/c/pages/untitled.md"},
+		{FilePath: "/c/top.md", FrontMatter: model.FrontMatter{Title: "Top"}},
+	}
+
+	errs := ValidateContentSchema(articles, cfg)
+	var got []string
+	for _, err := range errs {
+		var se *SchemaError
+		if !errors.As(err, &se) {
+			t.Fatalf("error %v is not a *SchemaError", err)
+		}
+		got = append(got, filepath.ToSlash(se.FilePath)+" "+se.Field+": "+se.Message)
+	}
+	want := []string{
+		"/c/posts/bad.md description: required field is missing",
+		"/c/posts/bad.md featured: must be of type bool, got date",
+		"/c/posts/bad.md rating: must be of type float, got string",
+		`/c/posts/bad.md series: value "Intro" does not match pattern ^[a-z-]+$`,
+		`/c/posts/bad.md tags: value "rust" is not one of go, web`,
+		"/c/pages/untitled.md title: required field is missing",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("violations:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestValidateContentSchema_NoSchema(t *testing.T) {
+	articles := []*model.Article{{FilePath: "/c/posts/a.md"}}
+	if errs := ValidateContentSchema(articles, model.Config{}); errs != nil {
+		t.Errorf("expected no errors without a schema, got %v", errs)
+	}
+}
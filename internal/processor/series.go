@@ -0,0 +1,62 @@
+package processor
+
+import (
+	"sort"
+
+	"github.com/bmf-san/gohan/internal/model"
+)
+
+// BuildSeries groups the articles that set series in their front matter into
+// one Series per locale and series name. Parts are ordered by ascending
+// series_order, parts without one after the numbered ones, then by date
+// (oldest first) and FilePath. Series are sorted by locale, then name.
+func (p *SiteProcessor) BuildSeries(articles []*model.ProcessedArticle) []*model.Series {
+	type key struct{ locale, name string }
+	byKey := make(map[key]*model.Series)
+	var series []*model.Series
+	for _, a := range articles {
+		name := a.FrontMatter.Series
+		if name == "" {
+			continue
+		}
+		k := key{a.Locale, name}
+		s, ok := byKey[k]
+		if !ok {
+			s = &model.Series{Name: name, Locale: a.Locale}
+			byKey[k] = s
+			series = append(series, s)
+		}
+		s.Articles = append(s.Articles, a)
+	}
+	for _, s := range series {
+		sort.Slice(s.Articles, func(i, j int) bool {
+			return seriesLess(s.Articles[i], s.Articles[j])
+		})
+	}
+	sort.Slice(series, func(i, j int) bool {
+		if series[i].Locale != series[j].Locale {
+			return series[i].Locale < series[j].Locale
+		}
+		return series[i].Name < series[j].Name
+	})
+	return series
+}
+
+// seriesLess reports whether part a comes before part b in reading order.
+func seriesLess(a, b *model.ProcessedArticle) bool {
+	oa, ob := a.FrontMatter.SeriesOrder, b.FrontMatter.SeriesOrder
+	if oa != ob {
+		switch {
+		case oa == 0:
+			return false
+		case ob == 0:
+			return true
+		}
+		return oa < ob
+	}
+	da, db := a.FrontMatter.Date, b.FrontMatter.Date
+	if !da.Equal(db) {
+		return da.Before(db)
+	}
+	return a.FilePath < b.FilePath
+}
@@ -0,0 +1,61 @@
+package processor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bmf-san/gohan/internal/model"
+)
+
+func TestSiteProcessor_BuildSeries(t *testing.T) {
+	t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	mk := func(path, series string, order int, date time.Time, locale string) *model.ProcessedArticle {
+		a := testArticle(path, path, "", nil, nil, date)
+		a.FrontMatter.Series = series
+		a.FrontMatter.SeriesOrder = order
+		return &model.ProcessedArticle{Article: *a, Locale: locale}
+	}
+	articles := []*model.ProcessedArticle{
+		mk("unordered.md", "Go", 0, t0, "en"),
+		mk("two.md", "Go", 2, t0.AddDate(0, 0, 1), "en"),
+		mk("one.md", "Go", 1, t0.AddDate(0, 0, 2), "en"),
+		mk("late.md", "Go", 0, t0.AddDate(0, 1, 0), "en"),
+		mk("ja.md", "Go", 1, t0, "ja"),
+		mk("alpha.md", "Alpha", 0, t0, "en"),
+		mk("standalone.md", "", 0, t0, "en"),
+	}
+
+	series := NewSiteProcessor().BuildSeries(articles)
+	want := []struct {
+		name, locale string
+		parts        []string
+	}{
+		{"Alpha", "en", []string{"alpha.md"}},
+		{"Go", "en", []string{"one.md", "two.md", "unordered.md", "late.md"}},
+		{"Go", "ja", []string{"ja.md"}},
+	}
+	if len(series) != len(want) {
+		t.Fatalf("got %d series, want %d", len(series), len(want))
+	}
+	for i, w := range want {
+		s := series[i]
+		if s.Name != w.name || s.Locale != w.locale {
+			t.Errorf("series[%d] = %s/%s, want %s/%s", i, s.Locale, s.Name, w.locale, w.name)
+			continue
+		}
+		var got []string
+		for _, a := range s.Articles {
+			got = append(got, a.FilePath)
+		}
+		if len(got) != len(w.parts) {
+			t.Errorf("%s/%s parts: got %v, want %v", w.locale, w.name, got, w.parts)
+			continue
+		}
+		for j := range got {
+			if got[j] != w.parts[j] {
+				t.Errorf("%s/%s parts: got %v, want %v", w.locale, w.name, got, w.parts)
+				break
+			}
+		}
+	}
+}
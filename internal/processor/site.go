@@ -1,26 +1,43 @@
 package processor
 
 import (
+	"errors"
 	"fmt"
+	"html/template"
 	"path/filepath"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/bmf-san/gohan/internal/highlight"
+	"github.com/bmf-san/gohan/internal/imageproc"
 	"github.com/bmf-san/gohan/internal/model"
 	"github.com/bmf-san/gohan/internal/parser"
 )
 
 // SiteProcessor implements the Processor interface.
-type SiteProcessor struct{}
+type SiteProcessor struct {
+	cache *RenderCache // set via SetRenderCache; nil disables caching
+}
 
 // NewSiteProcessor returns a new SiteProcessor.
 func NewSiteProcessor() *SiteProcessor {
 	return &SiteProcessor{}
 }
 
+// SetRenderCache makes Process reuse the rendered HTML, TOC, summary, and word
+// count of articles whose Markdown body and converter options are unchanged
+// since an earlier build. Pass nil to disable caching.
+func (p *SiteProcessor) SetRenderCache(c *RenderCache) {
+	p.cache = c
+}
+
 // Process converts raw Articles into ProcessedArticles by rendering Markdown
-// to HTML, extracting summaries, and computing output paths.
+// to HTML, extracting summaries, and computing output paths. Up to
+// cfg.Build.Parallelism articles are converted concurrently; the result keeps
+// the input order. Every article that fails to render is reported, joined
+// with errors.Join, rather than only the first.
 func (p *SiteProcessor) Process(articles []*model.Article, cfg model.Config) ([]*model.ProcessedArticle, error) {
 	hlCfg := highlight.Config{
 		Theme:       cfg.SyntaxHighlight.Theme,
@@ -30,38 +47,137 @@ func (p *SiteProcessor) Process(articles []*model.Article, cfg model.Config) ([]
 	if hlCfg.Theme != "" {
 		convOpts = append(convOpts, parser.WithHighlighting(hlCfg))
 	}
+	var planner *imageproc.Planner
+	if cfg.Images.Enabled {
+		convOpts = append(convOpts, parser.WithImages())
+		planner = imageproc.NewPlanner(cfg.Images)
+	}
 	conv := parser.NewConverter(convOpts...)
-	result := make([]*model.ProcessedArticle, 0, len(articles))
-	for _, a := range articles {
-		html, err := conv.Convert([]byte(a.RawContent))
-		if err != nil {
-			return nil, fmt.Errorf("processor: render %s: %w", a.FilePath, err)
-		}
-		words := countWords(a.RawContent)
-		processed := &model.ProcessedArticle{
-			Article:     *a,
-			HTMLContent: html,
-			Summary:     extractSummary(a.RawContent, 200),
-			OutputPath:  computeOutputPath(a, cfg),
-			ContentPath: computeContentPath(a, cfg),
-			Locale:      detectLocale(a, cfg),
-			URL:         computeArticleURL(a, cfg),
-			WordCount:   words,
-			ReadingTime: readingTimeMinutes(words),
-			TOC:         parser.ExtractTOC([]byte(a.RawContent)),
-		}
-		result = append(result, processed)
+	parallelism := cfg.Build.Parallelism
+	if parallelism <= 0 {
+		parallelism = 1
+	}
+	result := make([]*model.ProcessedArticle, len(articles))
+	errs := make([]error, len(articles))
+	sem := make(chan struct{}, parallelism)
+	var wg sync.WaitGroup
+	for i, a := range articles {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, a *model.Article) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			result[i], errs[i] = p.processArticle(conv, planner, a, cfg)
+		}(i, a)
+	}
+	wg.Wait()
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
 	}
 	return result, nil
 }
 
+// processArticle renders a single article and resolves its paths. planner
+// is nil unless images.enabled is set.
+func (p *SiteProcessor) processArticle(conv *parser.Converter, planner *imageproc.Planner, a *model.Article, cfg model.Config) (*model.ProcessedArticle, error) {
+	outputPath := computeOutputPath(a, cfg)
+	resources, err := bundleResources(a, outputPath, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("processor: list bundle resources of %s: %w", a.FilePath, err)
+	}
+	var images *articleImages
+	if planner != nil {
+		images = &articleImages{planner: planner, cfg: cfg, resources: resources}
+	}
+	body, err := p.render(conv, images, a, outputPath, cfg)
+	if err != nil {
+		return nil, err
+	}
+	var variants []model.ImageVariant
+	if images != nil {
+		variants = images.variants(body.Images)
+	}
+	return &model.ProcessedArticle{
+		Article:       *a,
+		HTMLContent:   body.HTMLContent,
+		Summary:       body.Summary,
+		OutputPath:    outputPath,
+		ContentPath:   computeContentPath(a, cfg),
+		Locale:        detectLocale(a, cfg),
+		URL:           computeArticleURL(a, cfg),
+		WordCount:     body.WordCount,
+		ReadingTime:   readingTimeMinutes(body.WordCount),
+		Taxonomies:    articleTerms(a, cfg),
+		TOC:           body.TOC,
+		Resources:     resources,
+		ImageVariants: variants,
+	}, nil
+}
+
+// render converts a's Markdown body, written to outputPath, consulting the
+// render cache first when one is set. Failing to store an entry is not an
+// error: the article is simply converted again by the next build.
+func (p *SiteProcessor) render(conv *parser.Converter, images *articleImages, a *model.Article, outputPath string, cfg model.Config) (*renderEntry, error) {
+	var key string
+	if p.cache != nil {
+		key = renderCacheKey(a.RawContent, a.FilePath+"\x00"+outputPath, cfg)
+		// A cached body is stale when an image it embeds the dimensions
+		// and variants of has changed since.
+		if e, ok := p.cache.get(key); ok && (images == nil || images.current(e.Images)) {
+			return e, nil
+		}
+	}
+	var html template.HTML
+	var refs []imageRef
+	var err error
+	if images != nil {
+		html, err = conv.ConvertWithImages([]byte(a.RawContent), images.resolver(&refs))
+	} else {
+		html, err = conv.Convert([]byte(a.RawContent))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("processor: render %s: %w", a.FilePath, err)
+	}
+	e := &renderEntry{
+		HTMLContent: html,
+		TOC:         parser.ExtractTOC([]byte(a.RawContent)),
+		Summary:     extractSummary(a.RawContent, 200),
+		WordCount:   countWords(a.RawContent),
+		Images:      refs,
+	}
+	if p.cache != nil {
+		_ = p.cache.put(key, e)
+	}
+	return e, nil
+}
+
+// ApplyGitInfo attaches the git history in infos (keyed by content-relative
+// path, as returned by diff.LoadGitInfo) to each article via its ContentPath.
+// The article's LastModified is replaced with the last commit date, since the
+// file modification time is meaningless after a fresh checkout.
+func ApplyGitInfo(articles []*model.ProcessedArticle, infos map[string]*model.GitInfo) {
+	for _, a := range articles {
+		info, ok := infos[a.ContentPath]
+		if !ok {
+			continue
+		}
+		a.GitInfo = info
+		a.LastModified = info.LastCommitDate
+	}
+}
+
 // BuildDependencyGraph constructs a DependencyGraph from all processed articles,
-// linking each article to its tag, category, and archive (year) nodes.
+// linking each article to its tag, category, user-defined taxonomy term,
+// series, and archive (year) nodes,
+// and to the page nodes of its previous and next articles, whose navigation
+// shows it.
 func (p *SiteProcessor) BuildDependencyGraph(articles []*model.ProcessedArticle) (*model.DependencyGraph, error) {
 	g := &model.DependencyGraph{
 		Nodes: make(map[string]*model.Node),
 		Edges: make(map[string][]string),
 	}
+	adjacent := AdjacentArticles(articles)
 	for _, a := range articles {
 		articlePath := a.FilePath
 		addNode(g, &model.Node{
@@ -70,26 +186,52 @@ func (p *SiteProcessor) BuildDependencyGraph(articles []*model.ProcessedArticle)
 			LastModified: a.LastModified,
 		})
 		for _, tag := range a.FrontMatter.Tags {
-			tagPath := "tag:" + tag
+			tagPath := TagNode(tag)
 			addNode(g, &model.Node{Path: tagPath, Type: model.NodeTypeTag, LastModified: time.Time{}})
 			addEdge(g, articlePath, tagPath)
 		}
 		for _, cat := range a.FrontMatter.Categories {
-			catPath := "category:" + cat
+			catPath := CategoryNode(cat)
 			addNode(g, &model.Node{Path: catPath, Type: model.NodeTypeCategory, LastModified: time.Time{}})
 			addEdge(g, articlePath, catPath)
 		}
+		names := make([]string, 0, len(a.Taxonomies))
+		for name := range a.Taxonomies {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			for _, term := range a.Taxonomies[name] {
+				termPath := TermNode(name, term)
+				addNode(g, &model.Node{Path: termPath, Type: model.NodeTypeTerm, LastModified: time.Time{}})
+				addEdge(g, articlePath, termPath)
+			}
+		}
+		if a.FrontMatter.Series != "" {
+			seriesPath := SeriesNode(a.FrontMatter.Series)
+			addNode(g, &model.Node{Path: seriesPath, Type: model.NodeTypeSeries, LastModified: time.Time{}})
+			addEdge(g, articlePath, seriesPath)
+		}
 		if !a.FrontMatter.Date.IsZero() {
-			year := fmt.Sprintf("archive:%d", a.FrontMatter.Date.Year())
+			year := ArchiveNode(a.FrontMatter.Date.Year())
 			addNode(g, &model.Node{Path: year, Type: model.NodeTypeArchive, LastModified: time.Time{}})
 			addEdge(g, articlePath, year)
 		}
+		for _, n := range []*model.ProcessedArticle{adjacent[a].Prev, adjacent[a].Next} {
+			if n == nil {
+				continue
+			}
+			page := PageNode(n.ContentPath)
+			addNode(g, &model.Node{Path: page, Type: model.NodeTypePage, LastModified: time.Time{}})
+			addEdge(g, articlePath, page)
+		}
 	}
 	return g, nil
 }
 
-// BuildTaxonomyRegistry collects all unique tags and categories referenced
-// across the article set and returns a TaxonomyRegistry.
+// BuildTaxonomyRegistry collects all unique tags, categories, and terms of the
+// user-defined taxonomies of cfg referenced across the article set and
+// returns a TaxonomyRegistry.
 func (p *SiteProcessor) BuildTaxonomyRegistry(articles []*model.ProcessedArticle, cfg model.Config) (*model.TaxonomyRegistry, error) {
 	tagSeen := make(map[string]bool)
 	catSeen := make(map[string]bool)
@@ -108,9 +250,63 @@ func (p *SiteProcessor) BuildTaxonomyRegistry(articles []*model.ProcessedArticle
 			}
 		}
 	}
+	for _, tc := range cfg.Taxonomies {
+		seen := make(map[string]bool)
+		var terms []model.Taxonomy
+		for _, a := range articles {
+			for _, term := range a.Taxonomies[tc.Name] {
+				if !seen[term] {
+					seen[term] = true
+					terms = append(terms, model.Taxonomy{Name: term})
+				}
+			}
+		}
+		setTerms(reg, tc.Name, terms)
+	}
 	return reg, nil
 }
 
+// articleTerms returns the terms a assigns to each user-defined taxonomy of
+// cfg through the front matter field named after it, which holds a single
+// value or a list. Duplicate and empty terms are dropped; nil when a assigns
+// none.
+func articleTerms(a *model.Article, cfg model.Config) map[string][]string {
+	var out map[string][]string
+	for _, tc := range cfg.Taxonomies {
+		var values []interface{}
+		switch v := a.FrontMatter.Extra[tc.Name].(type) {
+		case nil:
+			continue
+		case []interface{}:
+			values = v
+		case []string:
+			for _, s := range v {
+				values = append(values, s)
+			}
+		default:
+			values = []interface{}{v}
+		}
+		seen := make(map[string]bool, len(values))
+		var terms []string
+		for _, v := range values {
+			term := strings.TrimSpace(fmt.Sprint(v))
+			if term == "" || seen[term] {
+				continue
+			}
+			seen[term] = true
+			terms = append(terms, term)
+		}
+		if len(terms) == 0 {
+			continue
+		}
+		if out == nil {
+			out = make(map[string][]string)
+		}
+		out[tc.Name] = terms
+	}
+	return out
+}
+
 // computeContentPath returns the content-dir-relative path to the source file
 // (e.g. "posts/hello-world.md"), using forward slashes for URL compatibility.
 func computeContentPath(a *model.Article, cfg model.Config) string {
@@ -199,7 +395,8 @@ func (p *SiteProcessor) BuildTranslationMap(articles []*model.ProcessedArticle)
 }
 
 // computeOutputPath determines the output HTML path for an article.
-// Respects FrontMatter.Slug when set; otherwise uses the file base name.
+// Respects FrontMatter.Slug when set; otherwise uses the file base name, or
+// for a leaf bundle (posts/my-post/index.md) the bundle directory name.
 // When i18n is active, strips the locale segment from the content path and
 // re-adds it as a URL prefix for non-default locales.
 func computeOutputPath(a *model.Article, cfg model.Config) string {
@@ -209,6 +406,10 @@ func computeOutputPath(a *model.Article, cfg model.Config) string {
 	}
 	dir := filepath.Dir(rel)
 	base := strings.TrimSuffix(filepath.Base(rel), filepath.Ext(rel))
+	if IsLeafBundle(a, cfg) {
+		base = filepath.Base(dir)
+		dir = filepath.Dir(dir)
+	}
 	if a.FrontMatter.Slug != "" {
 		// Sanitize slug against path traversal — take only the last path
 		// component so that "../../etc/passwd" reduces to "passwd".
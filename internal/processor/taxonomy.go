@@ -5,16 +5,18 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 
 	"gopkg.in/yaml.v3"
 
 	"github.com/bmf-san/gohan/internal/model"
 )
 
-// LoadTaxonomyRegistry reads tags.yaml and categories.yaml from taxonomyDir
-// and returns the combined TaxonomyRegistry.
+// LoadTaxonomyRegistry reads tags.yaml and categories.yaml from taxonomyDir,
+// plus {name}.yaml for each user-defined taxonomy in custom, and returns the
+// combined TaxonomyRegistry.
 // Missing files are treated as empty (no error).
-func LoadTaxonomyRegistry(taxonomyDir string) (*model.TaxonomyRegistry, error) {
+func LoadTaxonomyRegistry(taxonomyDir string, custom ...model.TaxonomyConfig) (*model.TaxonomyRegistry, error) {
 	reg := &model.TaxonomyRegistry{}
 
 	tags, err := loadTaxonomyFile(filepath.Join(taxonomyDir, "tags.yaml"))
@@ -29,9 +31,47 @@ func LoadTaxonomyRegistry(taxonomyDir string) (*model.TaxonomyRegistry, error) {
 	}
 	reg.Categories = cats
 
+	for _, tc := range custom {
+		terms, err := loadTaxonomyFile(filepath.Join(taxonomyDir, tc.Name+".yaml"))
+		if err != nil {
+			return nil, fmt.Errorf("taxonomy: load %s: %w", tc.Name, err)
+		}
+		setTerms(reg, tc.Name, terms)
+	}
+
 	return reg, nil
 }
 
+// setTerms stores terms as the terms of the user-defined taxonomy name in
+// reg. Empty term lists are not stored.
+func setTerms(reg *model.TaxonomyRegistry, name string, terms []model.Taxonomy) {
+	if len(terms) == 0 {
+		return
+	}
+	if reg.Taxonomies == nil {
+		reg.Taxonomies = make(map[string][]model.Taxonomy)
+	}
+	reg.Taxonomies[name] = terms
+}
+
+// ResolveTaxonomyRegistry returns the registry the site is built with:
+// derived, as returned by BuildTaxonomyRegistry, with tags and categories
+// replaced by those of merged when merged declares any, and each
+// user-defined taxonomy replaced by the terms merged declares for it.
+func ResolveTaxonomyRegistry(derived, merged *model.TaxonomyRegistry) *model.TaxonomyRegistry {
+	out := &model.TaxonomyRegistry{Tags: derived.Tags, Categories: derived.Categories}
+	if len(merged.Tags) > 0 || len(merged.Categories) > 0 {
+		out.Tags, out.Categories = merged.Tags, merged.Categories
+	}
+	for name, terms := range derived.Taxonomies {
+		setTerms(out, name, terms)
+	}
+	for name, terms := range merged.Taxonomies {
+		setTerms(out, name, terms)
+	}
+	return out
+}
+
 // loadTaxonomyFile reads a YAML file containing a list of Taxonomy entries.
 // Returns nil slice if the file does not exist.
 func loadTaxonomyFile(path string) ([]model.Taxonomy, error) {
@@ -59,13 +99,14 @@ func loadTaxonomyFileWithFallback(primary, fallback string) ([]model.Taxonomy, e
 }
 
 // LoadLocaleAwareTaxonomyRegistries loads a taxonomy registry per locale.
-// For each locale it looks for {contentDir}/{locale}/tags.yaml (and categories.yaml),
-// falling back to the global {contentDir}/tags.yaml when the locale file is absent.
+// For each locale it looks for {contentDir}/{locale}/tags.yaml (and categories.yaml,
+// and {name}.yaml for each user-defined taxonomy in custom), falling back to
+// the global {contentDir}/tags.yaml when the locale file is absent.
 // The returned map also has an "" (empty string) key holding the global registry.
-func LoadLocaleAwareTaxonomyRegistries(contentDir string, locales []string) (map[string]*model.TaxonomyRegistry, error) {
+func LoadLocaleAwareTaxonomyRegistries(contentDir string, locales []string, custom ...model.TaxonomyConfig) (map[string]*model.TaxonomyRegistry, error) {
 	registries := make(map[string]*model.TaxonomyRegistry, len(locales)+1)
 
-	global, err := LoadTaxonomyRegistry(contentDir)
+	global, err := LoadTaxonomyRegistry(contentDir, custom...)
 	if err != nil {
 		return nil, err
 	}
@@ -86,7 +127,18 @@ func LoadLocaleAwareTaxonomyRegistries(contentDir string, locales []string) (map
 		if err != nil {
 			return nil, fmt.Errorf("taxonomy: locale %q categories: %w", locale, err)
 		}
-		registries[locale] = &model.TaxonomyRegistry{Tags: tags, Categories: cats}
+		reg := &model.TaxonomyRegistry{Tags: tags, Categories: cats}
+		for _, tc := range custom {
+			terms, err := loadTaxonomyFileWithFallback(
+				filepath.Join(contentDir, locale, tc.Name+".yaml"),
+				filepath.Join(contentDir, tc.Name+".yaml"),
+			)
+			if err != nil {
+				return nil, fmt.Errorf("taxonomy: locale %q %s: %w", locale, tc.Name, err)
+			}
+			setTerms(reg, tc.Name, terms)
+		}
+		registries[locale] = reg
 	}
 	return registries, nil
 }
@@ -96,8 +148,20 @@ func LoadLocaleAwareTaxonomyRegistries(contentDir string, locales []string) (map
 func MergeTaxonomyRegistries(registries map[string]*model.TaxonomyRegistry) *model.TaxonomyRegistry {
 	tagSeen := make(map[string]bool)
 	catSeen := make(map[string]bool)
+	termSeen := make(map[string]map[string]bool)
 	merged := &model.TaxonomyRegistry{}
 	for _, reg := range registries {
+		for name, terms := range reg.Taxonomies {
+			if termSeen[name] == nil {
+				termSeen[name] = make(map[string]bool)
+			}
+			for _, t := range terms {
+				if !termSeen[name][t.Name] {
+					termSeen[name][t.Name] = true
+					setTerms(merged, name, append(merged.Taxonomies[name], t))
+				}
+			}
+		}
 		for _, t := range reg.Tags {
 			if !tagSeen[t.Name] {
 				tagSeen[t.Name] = true
@@ -114,13 +178,26 @@ func MergeTaxonomyRegistries(registries map[string]*model.TaxonomyRegistry) *mod
 	return merged
 }
 
+// TaxonomyError reports an article that references a tag, category, or
+// user-defined taxonomy term missing from the taxonomy registry.
+type TaxonomyError struct {
+	FilePath string // path of the offending article
+	Kind     string // "tag", "category", or the user-defined taxonomy name
+	Name     string // the unknown tag, category, or term
+}
+
+func (e *TaxonomyError) Error() string {
+	return fmt.Sprintf("article %q: unknown %s %q", e.FilePath, e.Kind, e.Name)
+}
+
 // ValidateArticleTaxonomiesLocale validates each article against its locale's
 // registry from the registries map. Falls back to the "" key when no
 // locale-specific registry is found. Only validates when the registry has entries.
 func ValidateArticleTaxonomiesLocale(articles []*model.ProcessedArticle, registries map[string]*model.TaxonomyRegistry) []error {
 	type setsPair struct {
-		tags map[string]bool
-		cats map[string]bool
+		tags  map[string]bool
+		cats  map[string]bool
+		terms map[string]map[string]bool
 	}
 	sets := make(map[string]setsPair, len(registries))
 	for locale, reg := range registries {
@@ -132,7 +209,7 @@ func ValidateArticleTaxonomiesLocale(articles []*model.ProcessedArticle, registr
 		for _, c := range reg.Categories {
 			cs[c.Name] = true
 		}
-		sets[locale] = setsPair{ts, cs}
+		sets[locale] = setsPair{ts, cs, termSets(reg)}
 	}
 
 	var errs []error
@@ -144,23 +221,62 @@ func ValidateArticleTaxonomiesLocale(articles []*model.ProcessedArticle, registr
 		if len(sp.tags) > 0 {
 			for _, t := range a.FrontMatter.Tags {
 				if !sp.tags[t] {
-					errs = append(errs, fmt.Errorf("article %q: unknown tag %q", a.FilePath, t))
+					errs = append(errs, &TaxonomyError{FilePath: a.FilePath, Kind: "tag", Name: t})
 				}
 			}
 		}
 		if len(sp.cats) > 0 {
 			for _, c := range a.FrontMatter.Categories {
 				if !sp.cats[c] {
-					errs = append(errs, fmt.Errorf("article %q: unknown category %q", a.FilePath, c))
+					errs = append(errs, &TaxonomyError{FilePath: a.FilePath, Kind: "category", Name: c})
 				}
 			}
 		}
+		errs = append(errs, validateTerms(a, sp.terms)...)
+	}
+	return errs
+}
+
+// termSets returns the term names of each user-defined taxonomy of reg.
+func termSets(reg *model.TaxonomyRegistry) map[string]map[string]bool {
+	sets := make(map[string]map[string]bool, len(reg.Taxonomies))
+	for name, terms := range reg.Taxonomies {
+		s := make(map[string]bool, len(terms))
+		for _, t := range terms {
+			s[t.Name] = true
+		}
+		sets[name] = s
+	}
+	return sets
+}
+
+// validateTerms checks the user-defined taxonomy terms of a against sets.
+// Taxonomies without registry entries are not validated.
+func validateTerms(a *model.ProcessedArticle, sets map[string]map[string]bool) []error {
+	names := make([]string, 0, len(a.Taxonomies))
+	for name := range a.Taxonomies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var errs []error
+	for _, name := range names {
+		set := sets[name]
+		if len(set) == 0 {
+			continue
+		}
+		for _, term := range a.Taxonomies[name] {
+			if !set[term] {
+				errs = append(errs, &TaxonomyError{FilePath: a.FilePath, Kind: name, Name: term})
+			}
+		}
 	}
 	return errs
 }
 
 // ValidateArticleTaxonomies checks that every tag and category referenced in
-// an article exists in the registry.  It returns one error per violation.
+// an article exists in the registry, as does every user-defined taxonomy term
+// when the registry declares terms for that taxonomy.  It returns one error
+// per violation.
 func ValidateArticleTaxonomies(articles []*model.ProcessedArticle, registry *model.TaxonomyRegistry) []error {
 	tagSet := make(map[string]bool, len(registry.Tags))
 	for _, t := range registry.Tags {
@@ -171,18 +287,21 @@ func ValidateArticleTaxonomies(articles []*model.ProcessedArticle, registry *mod
 		catSet[c.Name] = true
 	}
 
+	terms := termSets(registry)
+
 	var errs []error
 	for _, a := range articles {
 		for _, t := range a.FrontMatter.Tags {
 			if !tagSet[t] {
-				errs = append(errs, fmt.Errorf("article %q: unknown tag %q", a.FilePath, t))
+				errs = append(errs, &TaxonomyError{FilePath: a.FilePath, Kind: "tag", Name: t})
 			}
 		}
 		for _, c := range a.FrontMatter.Categories {
 			if !catSet[c] {
-				errs = append(errs, fmt.Errorf("article %q: unknown category %q", a.FilePath, c))
+				errs = append(errs, &TaxonomyError{FilePath: a.FilePath, Kind: "category", Name: c})
 			}
 		}
+		errs = append(errs, validateTerms(a, terms)...)
 	}
 	return errs
 }
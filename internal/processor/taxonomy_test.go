@@ -1,6 +1,7 @@
 package processor
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
 	"runtime"
@@ -266,7 +267,14 @@ func TestValidateArticleTaxonomiesLocale_FallbackToGlobal(t *testing.T) {
 	}
 	errs := ValidateArticleTaxonomiesLocale(articles, regs)
 	if len(errs) != 1 {
-		t.Errorf("expected 1 error (unknown tag), got %d: %v", len(errs), errs)
+		t.Fatalf("expected 1 error (unknown tag), got %d: %v", len(errs), errs)
+	}
+	var te *TaxonomyError
+	if !errors.As(errs[0], &te) {
+		t.Fatalf("expected *TaxonomyError, got %T", errs[0])
+	}
+	if te.FilePath != articles[1].FilePath || te.Kind != "tag" || te.Name != "unknown" {
+		t.Errorf("unexpected error fields: %+v", te)
 	}
 }
 
@@ -361,3 +369,95 @@ func TestValidateArticleTaxonomiesLocale_UnknownCategory(t *testing.T) {
 		t.Errorf("expected 1 error for unknown category, got %d: %v", len(errs), errs)
 	}
 }
+
+func TestLoadLocaleAwareTaxonomyRegistries_Custom(t *testing.T) {
+	dir := t.TempDir()
+	writeTaxFile(t, dir, "authors.yaml", "- name: alice\n  description: Editor\n")
+	if err := os.MkdirAll(filepath.Join(dir, "ja"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writeTaxFile(t, filepath.Join(dir, "ja"), "authors.yaml", "- name: アリス\n")
+	custom := []model.TaxonomyConfig{{Name: "authors"}, {Name: "genres"}}
+
+	regs, err := LoadLocaleAwareTaxonomyRegistries(dir, []string{"en", "ja"}, custom...)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := regs[""].Taxonomies["authors"]; len(got) != 1 || got[0].Description != "Editor" {
+		t.Errorf("global authors: got %v", got)
+	}
+	if got := regs["en"].Taxonomies["authors"]; len(got) != 1 || got[0].Name != "alice" {
+		t.Errorf("en should fall back to global: got %v", got)
+	}
+	if got := regs["ja"].Taxonomies["authors"]; len(got) != 1 || got[0].Name != "アリス" {
+		t.Errorf("ja should use its own file: got %v", got)
+	}
+	if _, ok := regs[""].Taxonomies["genres"]; ok {
+		t.Error("genres has no registry file and should have no entry")
+	}
+
+	merged := MergeTaxonomyRegistries(regs)
+	if got := merged.Taxonomies["authors"]; len(got) != 2 {
+		t.Errorf("merged authors: got %v, want alice and アリス", got)
+	}
+}
+
+func TestLoadTaxonomyRegistry_InvalidCustomYAML(t *testing.T) {
+	dir := t.TempDir()
+	writeTaxFile(t, dir, "authors.yaml", "invalid: {yaml: [unclosed")
+	if _, err := LoadTaxonomyRegistry(dir, model.TaxonomyConfig{Name: "authors"}); err == nil {
+		t.Error("expected error for invalid authors.yaml")
+	}
+}
+
+func TestValidateArticleTaxonomiesLocale_CustomTerms(t *testing.T) {
+	regs := map[string]*model.TaxonomyRegistry{
+		"": {Taxonomies: map[string][]model.Taxonomy{"authors": {{Name: "alice"}}}},
+	}
+	articles := []*model.ProcessedArticle{
+		{Article: *testArticle("a.md", "", "", nil, nil, time.Time{}), Taxonomies: map[string][]string{"authors": {"alice", "bob"}}},
+		// genres has no registry entries, so its terms are not validated.
+		{Article: *testArticle("b.md", "", "", nil, nil, time.Time{}), Taxonomies: map[string][]string{"genres": {"essay"}}},
+	}
+	errs := ValidateArticleTaxonomiesLocale(articles, regs)
+	if len(errs) != 1 {
+		t.Fatalf("expected 1 error, got %d: %v", len(errs), errs)
+	}
+	var te *TaxonomyError
+	if !errors.As(errs[0], &te) || te.Kind != "authors" || te.Name != "bob" {
+		t.Errorf("got %v, want unknown authors %q", errs[0], "bob")
+	}
+	if errs := ValidateArticleTaxonomies(articles, regs[""]); len(errs) != 1 {
+		t.Errorf("ValidateArticleTaxonomies: expected 1 error, got %d: %v", len(errs), errs)
+	}
+}
+
+func TestResolveTaxonomyRegistry(t *testing.T) {
+	derived := &model.TaxonomyRegistry{
+		Tags: []model.Taxonomy{{Name: "go"}},
+		Taxonomies: map[string][]model.Taxonomy{
+			"authors": {{Name: "alice"}},
+			"genres":  {{Name: "essay"}},
+		},
+	}
+
+	got := ResolveTaxonomyRegistry(derived, &model.TaxonomyRegistry{})
+	if len(got.Tags) != 1 || len(got.Taxonomies["authors"]) != 1 || len(got.Taxonomies["genres"]) != 1 {
+		t.Errorf("empty registry should keep derived entries: got %+v", got)
+	}
+
+	merged := &model.TaxonomyRegistry{
+		Categories: []model.Taxonomy{{Name: "tools"}},
+		Taxonomies: map[string][]model.Taxonomy{"authors": {{Name: "alice", Description: "Editor"}, {Name: "bob"}}},
+	}
+	got = ResolveTaxonomyRegistry(derived, merged)
+	if len(got.Tags) != 0 || len(got.Categories) != 1 {
+		t.Errorf("tags and categories should come from the registry: got %v, %v", got.Tags, got.Categories)
+	}
+	if a := got.Taxonomies["authors"]; len(a) != 2 || a[0].Description != "Editor" {
+		t.Errorf("authors should come from the registry: got %v", a)
+	}
+	if g := got.Taxonomies["genres"]; len(g) != 1 || g[0].Name != "essay" {
+		t.Errorf("genres should stay derived: got %v", g)
+	}
+}
@@ -0,0 +1,51 @@
+package processor
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Tokenize splits text into lower-cased search terms. Runs of letters and
+// digits outside CJK scripts become one term each, except single letters;
+// runs of CJK characters, which are not separated by spaces, become
+// overlapping bigrams ("検索機能" → "検索", "索機", "機能"), or a single
+// term when the run is one character long. Everything else separates terms.
+//
+// A client searching an index built from these terms must tokenize queries
+// the same way.
+func Tokenize(text string) []string {
+	var terms []string
+	var word []rune // current non-CJK run
+	var cjk []rune  // current CJK run
+	flushWord := func() {
+		if len(word) > 1 || (len(word) == 1 && unicode.IsDigit(word[0])) {
+			terms = append(terms, string(word))
+		}
+		word = word[:0]
+	}
+	flushCJK := func() {
+		if len(cjk) == 1 {
+			terms = append(terms, string(cjk))
+		}
+		for i := 0; i+1 < len(cjk); i++ {
+			terms = append(terms, string(cjk[i:i+2]))
+		}
+		cjk = cjk[:0]
+	}
+	for _, r := range strings.ToLower(text) {
+		switch {
+		case isCJK(r):
+			flushWord()
+			cjk = append(cjk, r)
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			flushCJK()
+			word = append(word, r)
+		default:
+			flushWord()
+			flushCJK()
+		}
+	}
+	flushWord()
+	flushCJK()
+	return terms
+}
@@ -0,0 +1,24 @@
+package processor
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"Hello, World! Go 1.26 a", []string{"hello", "world", "go", "1", "26"}},
+		{"全文検索", []string{"全文", "文検", "検索"}},
+		{"gohanの検索 機能", []string{"gohan", "の検", "検索", "機能"}},
+		{"字", []string{"字"}},
+		{"  ", nil},
+	}
+	for _, tt := range tests {
+		if got := Tokenize(tt.in); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Tokenize(%q): got %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
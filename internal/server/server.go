@@ -3,14 +3,20 @@ package server
 import (
 	"bytes"
 	"fmt"
+	"log/slog"
+	"mime"
 	"net/http"
 	"os"
+	"path"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/fsnotify/fsnotify"
+
+	"github.com/bmf-san/gohan/internal/precompress"
 )
 
 // ─────────────────────────────────────────
@@ -33,10 +39,17 @@ type FsnotifyWatcher struct {
 	watcher *fsnotify.Watcher
 	events  chan string
 	done    chan struct{}
+	logger  *slog.Logger // receives watcher errors; nil prints them to stderr
 }
 
 // NewFsnotifyWatcher creates and starts a new FsnotifyWatcher.
 func NewFsnotifyWatcher() (*FsnotifyWatcher, error) {
+	return newFsnotifyWatcher(nil)
+}
+
+// newFsnotifyWatcher creates and starts a FsnotifyWatcher reporting errors
+// to logger.
+func newFsnotifyWatcher(logger *slog.Logger) (*FsnotifyWatcher, error) {
 	w, err := fsnotify.NewWatcher()
 	if err != nil {
 		return nil, err
@@ -45,6 +58,7 @@ func NewFsnotifyWatcher() (*FsnotifyWatcher, error) {
 		watcher: w,
 		events:  make(chan string, 100),
 		done:    make(chan struct{}),
+		logger:  logger,
 	}
 	go fw.loop()
 	return fw, nil
@@ -69,13 +83,28 @@ func (fw *FsnotifyWatcher) loop() {
 			if !ok {
 				return
 			}
-			fmt.Fprintf(os.Stderr, "warn: file watcher: %v\n", err)
+			warn(fw.logger, "file watcher", err)
 		case <-fw.done:
 			return
 		}
 	}
 }
 
+// warn reports a non-fatal problem in the named part of the server as a WARN
+// record of logger, or as a "warn: <scope>: <err>" line on stderr when logger
+// is nil. args are key-value pairs such as "file" and a path.
+func warn(logger *slog.Logger, scope string, err error, args ...any) {
+	if logger == nil {
+		msg := fmt.Sprintf("warn: %s: %v", scope, err)
+		for i := 0; i+1 < len(args); i += 2 {
+			msg += fmt.Sprintf(" (%v %v)", args[i], args[i+1])
+		}
+		fmt.Fprintln(os.Stderr, msg)
+		return
+	}
+	logger.Warn(scope, append([]any{"scope", scope, "error", err.Error()}, args...)...)
+}
+
 // Add adds a path to be watched.
 func (fw *FsnotifyWatcher) Add(path string) error { return fw.watcher.Add(path) }
 
@@ -163,8 +192,7 @@ type injectingResponseWriter struct {
 func (w *injectingResponseWriter) Header() http.Header { return w.wrapped.Header() }
 
 func (w *injectingResponseWriter) WriteHeader(code int) {
-	ct := w.Header().Get("Content-Type")
-	w.isHTML = strings.Contains(ct, "text/html")
+	w.isHTML = isPlainHTML(w.Header())
 	w.header = code
 }
 
@@ -172,8 +200,7 @@ func (w *injectingResponseWriter) Write(b []byte) (int, error) {
 	// http.FileServer may call Write without calling WriteHeader first (implicit
 	// 200). Detect HTML from Content-Type at first Write if not yet determined.
 	if w.header == 0 {
-		ct := w.Header().Get("Content-Type")
-		w.isHTML = strings.Contains(ct, "text/html")
+		w.isHTML = isPlainHTML(w.Header())
 		w.header = http.StatusOK
 	}
 	if !w.isHTML {
@@ -222,6 +249,12 @@ func (w *injectingResponseWriter) flush() {
 	_, _ = w.wrapped.Write(body)
 }
 
+// isPlainHTML reports whether h describes an uncompressed HTML response,
+// the only kind the SSE script can be injected into.
+func isPlainHTML(h http.Header) bool {
+	return strings.Contains(h.Get("Content-Type"), "text/html") && h.Get("Content-Encoding") == ""
+}
+
 // noListFS wraps http.Dir and disables directory listings.
 // Directories without an index.html return os.ErrNotExist so that
 // http.FileServer responds with 404 instead of showing a file list.
@@ -257,6 +290,70 @@ func injectingHandler(h http.Handler) http.Handler {
 	})
 }
 
+// precompressedHandler serves the precompressed variant of a requested file
+// (e.g. "index.html.br") with a Content-Encoding header when the client
+// accepts its encoding and the variant exists in dir, as a CDN serving
+// precompressed files would. Other requests are passed to h.
+func precompressedHandler(dir string, h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			h.ServeHTTP(w, r)
+			return
+		}
+		name := path.Clean("/" + r.URL.Path)
+		if strings.HasSuffix(r.URL.Path, "/") {
+			name = path.Join(name, "index.html")
+		}
+		if !precompress.Compressible(name) {
+			h.ServeHTTP(w, r)
+			return
+		}
+		w.Header().Add("Vary", "Accept-Encoding")
+		for _, enc := range precompress.Encodings {
+			if !acceptsEncoding(r.Header.Get("Accept-Encoding"), enc.Name) {
+				continue
+			}
+			f, err := http.Dir(dir).Open(name + enc.Ext)
+			if err != nil {
+				continue
+			}
+			info, err := f.Stat()
+			if err != nil || info.IsDir() {
+				_ = f.Close()
+				continue
+			}
+			ct := mime.TypeByExtension(path.Ext(name))
+			if ct == "" {
+				ct = "application/octet-stream"
+			}
+			w.Header().Set("Content-Type", ct)
+			w.Header().Set("Content-Encoding", enc.Name)
+			http.ServeContent(w, r, name, info.ModTime(), f)
+			_ = f.Close()
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+// acceptsEncoding reports whether the Accept-Encoding header value header
+// lists encoding with a non-zero quality.
+func acceptsEncoding(header, encoding string) bool {
+	for _, part := range strings.Split(header, ",") {
+		token, params, _ := strings.Cut(part, ";")
+		if !strings.EqualFold(strings.TrimSpace(token), encoding) {
+			continue
+		}
+		q, ok := strings.CutPrefix(strings.TrimSpace(params), "q=")
+		if !ok {
+			return true
+		}
+		v, err := strconv.ParseFloat(q, 64)
+		return err == nil && v > 0
+	}
+	return false
+}
+
 // ─────────────────────────────────────────
 // DevServer
 // ─────────────────────────────────────────
@@ -269,6 +366,13 @@ type DevServer struct {
 	RootDir     string // project root; WatchDirs are resolved relative to this when set
 	Watcher     FileWatcher
 	RebuildFunc func() error // called on file change; may be nil
+	// Logger receives warnings as structured records. When nil they are
+	// printed to stderr as plain text.
+	Logger *slog.Logger
+	// Precompressed serves the ".br" and ".gz" variants written by
+	// build.precompress to clients that accept them. Live reload is not
+	// injected into precompressed pages.
+	Precompressed bool
 }
 
 // NewDevServer creates a new DevServer.
@@ -323,13 +427,16 @@ func (s *DevServer) Start() error {
 
 	// Static file server with script injection.
 	// noListFS disables directory listings: directories without index.html return 404.
-	fileHandler := injectingHandler(http.FileServer(noListFS{http.Dir(s.OutDir)}))
-	mux.Handle("/", fileHandler)
+	var fileHandler http.Handler = http.FileServer(noListFS{http.Dir(s.OutDir)})
+	if s.Precompressed {
+		fileHandler = precompressedHandler(s.OutDir, fileHandler)
+	}
+	mux.Handle("/", injectingHandler(fileHandler))
 
 	// Start file watcher if available
 	if s.Watcher == nil {
 		// Try to create a real watcher; silently skip if unavailable
-		if fw, err := NewFsnotifyWatcher(); err == nil {
+		if fw, err := newFsnotifyWatcher(s.Logger); err == nil {
 			s.Watcher = fw
 			defer func() { _ = fw.Close() }()
 		}
@@ -341,7 +448,7 @@ func (s *DevServer) Start() error {
 				path = filepath.Join(s.RootDir, dir)
 			}
 			if err := s.Watcher.Add(path); err != nil {
-				fmt.Fprintf(os.Stderr, "warn: watch %s: %v\n", path, err)
+				warn(s.Logger, "watch", err, "file", path)
 			}
 		}
 		go s.watchLoop(broadcaster)
@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strings"
 	"sync/atomic"
 	"testing"
@@ -177,3 +179,67 @@ func TestWatchLoop_Debounce(t *testing.T) {
 	}
 	b.unsubscribe(ch)
 }
+
+func TestPrecompressedHandler(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"index.html":        "<html><body>plain</body></html>",
+		"index.html.br":     "BR",
+		"index.html.gz":     "GZ",
+		"css/site.css":      "body{}",
+		"css/site.css.gz":   "CSSGZ",
+		"images/a.png":      "png",
+		"images/a.png.gz":   "never served",
+		"search-index.json": "{}",
+	}
+	for name, content := range files {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	handler := injectingHandler(precompressedHandler(dir, http.FileServer(noListFS{http.Dir(dir)})))
+
+	tests := []struct {
+		path, accept       string
+		wantBody, encoding string
+	}{
+		{"/", "gzip, deflate, br", "BR", "br"},
+		{"/index.html", "gzip", "GZ", "gzip"},
+		{"/", "gzip;q=0, br;q=0", "<html><body>plain", ""},
+		{"/css/site.css", "br, gzip;q=0.5", "CSSGZ", "gzip"},
+		{"/search-index.json", "br, gzip", "{}", ""},
+		{"/images/a.png", "gzip", "png", ""},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", tt.path, nil)
+		req.Header.Set("Accept-Encoding", tt.accept)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		body := rec.Body.String()
+		if tt.encoding == "" {
+			if !strings.HasPrefix(body, tt.wantBody) {
+				t.Errorf("%s (%s): got body %q, want %q", tt.path, tt.accept, body, tt.wantBody)
+			}
+		} else if body != tt.wantBody {
+			t.Errorf("%s (%s): got body %q, want %q (no script injected)", tt.path, tt.accept, body, tt.wantBody)
+		}
+		if got := rec.Header().Get("Content-Encoding"); got != tt.encoding {
+			t.Errorf("%s (%s): Content-Encoding %q, want %q", tt.path, tt.accept, got, tt.encoding)
+		}
+		if tt.encoding != "" && rec.Header().Get("Vary") != "Accept-Encoding" {
+			t.Errorf("%s: missing Vary: Accept-Encoding", tt.path)
+		}
+	}
+
+	req := httptest.NewRequest("GET", "/index.html", nil)
+	req.Header.Set("Accept-Encoding", "br")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/html") {
+		t.Errorf("Content-Type of a precompressed page: got %q, want text/html", ct)
+	}
+}
@@ -1,6 +1,10 @@
 package server
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
 	"testing"
 	"time"
 )
@@ -135,3 +139,15 @@ func TestWatchLoop_ExitsOnChannelClose(t *testing.T) {
 		t.Fatal("timeout: watchLoop did not exit after channel close")
 	}
 }
+
+func TestWarn_Logger(t *testing.T) {
+	var buf bytes.Buffer
+	warn(slog.New(slog.NewJSONHandler(&buf, nil)), "watch", errors.New("no such directory"), "file", "themes")
+	var rec map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &rec); err != nil {
+		t.Fatalf("not a JSON record: %q", buf.String())
+	}
+	if rec["level"] != "WARN" || rec["scope"] != "watch" || rec["error"] != "no such directory" || rec["file"] != "themes" {
+		t.Errorf("record = %v", rec)
+	}
+}
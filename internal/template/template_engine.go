@@ -26,10 +26,12 @@ func NewEngine() *Engine {
 }
 
 // Load parses all .html files found (recursively) under templateDir.
-// Built-in helper functions (formatDate, tagURL, categoryURL, markdownify) are
-// registered automatically; callers may supply additional functions via funcs.
+// Built-in helper functions (formatDate, tagURL, categoryURL, taxonomyURL,
+// markdownify) are registered automatically; callers may supply additional
+// functions via funcs.
 // defaultLocale is the site's primary locale (e.g. "en"); pass "" for non-i18n
-// sites. tagURL and categoryURL use it to decide when to omit the locale prefix.
+// sites. tagURL, categoryURL, and taxonomyURL use it to decide when to omit
+// the locale prefix.
 func (e *Engine) Load(templateDir string, funcs template.FuncMap, defaultLocale string) error {
 	allFuncs := builtinFuncs(defaultLocale)
 	for k, v := range funcs {
@@ -74,10 +76,16 @@ func (e *Engine) Render(w io.Writer, templateName string, data *model.Site) erro
 	return nil
 }
 
+// Has reports whether a template named name (a file base name or a {{define}}
+// block) has been loaded.
+func (e *Engine) Has(name string) bool {
+	return e.tmpl != nil && e.tmpl.Lookup(name) != nil
+}
+
 // builtinFuncs returns the default template function map.
-// defaultLocale is the site's primary locale; tagURL and categoryURL use it to
-// omit the locale prefix for the default locale (and for non-i18n sites when
-// "" is passed).
+// defaultLocale is the site's primary locale; tagURL, categoryURL, and
+// taxonomyURL use it to omit the locale prefix for the default locale (and
+// for non-i18n sites when "" is passed).
 func builtinFuncs(defaultLocale string) template.FuncMap {
 	conv := parser.NewConverter(parser.WithGFM())
 	return template.FuncMap{
@@ -101,6 +109,21 @@ func builtinFuncs(defaultLocale string) template.FuncMap {
 			}
 			return "/" + locale + "/categories/" + toSlug(cat) + "/"
 		},
+		// taxonomyURL returns the locale-aware canonical URL for a term of a
+		// user-defined taxonomy, or of the taxonomy's index page when term
+		// is "".
+		// locale="" or locale==defaultLocale → /{taxonomy}/{slug}/
+		// otherwise → /{locale}/{taxonomy}/{slug}/
+		"taxonomyURL": func(locale, taxonomy, term string) string {
+			url := "/" + taxonomy + "/"
+			if locale != "" && locale != defaultLocale {
+				url = "/" + locale + url
+			}
+			if term == "" {
+				return url
+			}
+			return url + toSlug(term) + "/"
+		},
 		// markdownify converts a Markdown string to safe HTML.
 		"markdownify": func(s string) (template.HTML, error) {
 			return conv.Convert([]byte(s))
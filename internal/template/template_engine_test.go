@@ -173,6 +173,28 @@ func TestEngine_Builtin_CategoryURL(t *testing.T) {
 	}
 }
 
+func TestEngine_Builtin_TaxonomyURL(t *testing.T) {
+	fn, ok := builtinFuncs("en")["taxonomyURL"].(func(string, string, string) string)
+	if !ok {
+		t.Fatal("taxonomyURL not found or wrong type")
+	}
+	tests := []struct {
+		locale, taxonomy, term string
+		want                   string
+	}{
+		{"", "authors", "Jane Doe", "/authors/jane-doe/"},
+		{"en", "authors", "Jane Doe", "/authors/jane-doe/"},
+		{"ja", "authors", "Jane Doe", "/ja/authors/jane-doe/"},
+		{"en", "authors", "", "/authors/"},
+		{"ja", "authors", "", "/ja/authors/"},
+	}
+	for _, tt := range tests {
+		if got := fn(tt.locale, tt.taxonomy, tt.term); got != tt.want {
+			t.Errorf("taxonomyURL(%q, %q, %q) = %q, want %q", tt.locale, tt.taxonomy, tt.term, got, tt.want)
+		}
+	}
+}
+
 func TestEngine_Builtin_Markdownify(t *testing.T) {
 	fns := builtinFuncs("")
 	fn, ok := fns["markdownify"].(func(string) (template.HTML, error))